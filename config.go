@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/deref/extractgqlts/emit"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file read from the working directory, if present, unless --config
+// names another.
+const defaultConfigPath = "extractgqlts.yaml"
+
+// A project's settings, so that extractgqlts may be run without arguments
+// from the directory of its config file. Flags given on the command line
+// override them.
+type config struct {
+	Schema string `yaml:"schema"`
+	// Input patterns, used if none are given as arguments.
+	Inputs []string `yaml:"inputs"`
+	Output string   `yaml:"output"`
+	// Mappings of scalars to TypeScript types, as by --scalar. Those given on
+	// the command line override these by name, rather than entirely.
+	Scalars map[string]string `yaml:"scalars"`
+	// Rules attaching transport hints to operations of the --registry.
+	Hints []emit.HintRule `yaml:"hints"`
+	// Any other flag, by name without dashes. Repeatable flags may be given a
+	// list.
+	Flags map[string]interface{} `yaml:"flags"`
+}
+
+// Input patterns of the config file.
+var configInputs []string
+
+// Hint rules of the config file.
+var hintRules []emit.HintRule
+
+// Returns the input patterns given as arguments or, failing that, by the
+// config file.
+func inputArgs() []string {
+	if args := flag.Args(); len(args) > 0 {
+		return args
+	}
+	return configInputs
+}
+
+// Reads the config file, setting each flag it gives that the command line
+// did not.
+func loadConfig() error {
+	path := configPath
+	if path == "" {
+		path = defaultConfigPath
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	var cfg config
+	dec := yaml.NewDecoder(bytes.NewReader(bs))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && err != io.EOF {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	setFlag := func(name, value string) error {
+		if set[name] {
+			return nil
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("%s: invalid %s: %w", path, name, err)
+		}
+		return nil
+	}
+	if cfg.Schema != "" {
+		if err := setFlag("schema", cfg.Schema); err != nil {
+			return err
+		}
+	}
+	if cfg.Output != "" {
+		if err := setFlag("output", cfg.Output); err != nil {
+			return err
+		}
+	}
+	configInputs = cfg.Inputs
+	hintRules = cfg.Hints
+
+	var mappings stringsFlag
+	for name, typ := range cfg.Scalars {
+		mappings = append(mappings, name+"="+typ)
+	}
+	sort.Strings(mappings)
+	scalarMappings = append(mappings, scalarMappings...)
+
+	names := make([]string, 0, len(cfg.Flags))
+	for name := range cfg.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := flag.Lookup(name)
+		switch {
+		case f == nil, name == "config":
+			return fmt.Errorf("%s: unknown flag %q", path, name)
+		case name == "schema", name == "output", name == "scalar":
+			return fmt.Errorf("%s: give %s at the top level, not under flags", path, name)
+		}
+		var values []interface{}
+		switch value := cfg.Flags[name].(type) {
+		case []interface{}:
+			if _, repeatable := f.Value.(*stringsFlag); !repeatable {
+				return fmt.Errorf("%s: %s is not repeatable, so may not be a list", path, name)
+			}
+			values = value
+		default:
+			values = []interface{}{value}
+		}
+		for _, value := range values {
+			switch value.(type) {
+			case string, bool, int, float64:
+			default:
+				return fmt.Errorf("%s: invalid %s: expected a string, number, or boolean", path, name)
+			}
+			if err := setFlag(name, fmt.Sprint(value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rule := range hintRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if len(hintRules) > 0 && registryPath == "" {
+		return fmt.Errorf("%s: hints require --registry", path)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+
+	"github.com/deref/extractgqlts/extract"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Something doctor found, with how to fix it. Notes are worth knowing but do
+// not fail the command.
+type finding struct {
+	note    bool
+	problem string
+	fix     string
+}
+
+// Templates tagged as GraphQL, whose documents are nonetheless not found
+// unless they begin with #graphql.
+var taggedTemplate = regexp.MustCompile("\\b(gql|graphql)\\s*(\\(\\s*)?`")
+
+// Checks the schema, the inputs, and the options for common misconfigurations,
+// printing each with how to fix it, and fails if any are problems.
+func doctor() error {
+	findings := diagnose()
+	problems := 0
+	for _, f := range findings {
+		label := "note"
+		if !f.note {
+			label = "problem"
+			problems++
+		}
+		fmt.Printf("%s: %s\n  fix: %s\n", label, f.problem, f.fix)
+	}
+	if problems > 0 {
+		return fmt.Errorf("found %d problems", problems)
+	}
+	fmt.Println("no problems found")
+	return nil
+}
+
+// Returns what doctor finds, in order of the steps of generation.
+func diagnose() []finding {
+	var findings []finding
+	add := func(note bool, fix, format string, args ...interface{}) {
+		findings = append(findings, finding{note: note, problem: fmt.Sprintf(format, args...), fix: fix})
+	}
+	args := inputArgs()
+	if schemaPath == "" {
+		add(false, "pass --schema=path/to/schema.gql, the schema in SDL, such as one downloaded by introspection", "no --schema given")
+	}
+	if len(args) == 0 {
+		add(false, "pass input patterns such as './src/**/*.ts', quoted so that the shell does not expand them", "no inputs given")
+	}
+
+	g, err := newGenerator()
+	if err != nil {
+		add(false, "correct or remove the option; see extractgqlts -help", "invalid options: %v", err)
+	}
+	if _, err := parsePackages(); err != nil {
+		add(false, "correct or remove the option; see extractgqlts -help", "invalid options: %v", err)
+	}
+	if splitDir != "" && (target != "typescript" || templatePath != "" || validators != "" || augmentModule != "") {
+		add(false, "drop --split-dir, or --template, --validators, and --augment", "--split-dir requires --target=typescript without --template, --validators, or --augment")
+	}
+	if warnDeprecated {
+		for _, spec := range lintRules {
+			if strings.HasPrefix(spec, "no-deprecated") {
+				add(true, "drop --warn-deprecated", "--lint=no-deprecated supersedes --warn-deprecated")
+				break
+			}
+		}
+	}
+	relativeScalars := strings.HasPrefix(scalarsModule, "./") || strings.HasPrefix(scalarsModule, "../")
+	if verifyScalarsDir != "" && !relativeScalars {
+		add(true, "pass a relative --scalars-module to have it checked", "--verify-scalars does not check package specifiers such as %q", scalarsModule)
+	}
+	if g == nil || schemaPath == "" {
+		return findings
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	tp, err := g.Typer(ctx)
+	if err != nil {
+		add(false, "check the path, and that the file is GraphQL SDL rather than introspection JSON", "cannot load schema %s: %v", schemaPath, err)
+		return findings
+	}
+	if tp.Schema.Query == nil {
+		add(false, "declare `type Query { ... }`, or name the query type with `schema { query: ... }`", "schema %s has no query type, so no query can be valid", schemaPath)
+	}
+	if len(args) == 0 {
+		return findings
+	}
+
+	documents := 0
+	var untagged []string
+	for _, pattern := range args {
+		paths, errs := generate.ExpandPatterns([]string{pattern}, inputExtensions())
+		for _, err := range errs {
+			add(false, "correct the pattern's brackets and braces", "%v", err)
+		}
+		if len(errs) == 0 && len(paths) == 0 {
+			wd, _ := os.Getwd()
+			add(false, fmt.Sprintf("check the pattern against the working directory, %s, and quote it so that the shell does not expand ** itself", wd), "pattern %q matches no files", pattern)
+		}
+		for _, path := range paths {
+			bs, err := ioutil.ReadFile(path)
+			if err != nil {
+				add(false, "check the file's permissions, or exclude it from the patterns", "cannot read input: %v", err)
+				continue
+			}
+			queries, _ := extract.QueriesFromBytes(bs)
+			documents += len(queries)
+			if len(queries) == 0 && taggedTemplate.Match(bs) {
+				untagged = append(untagged, path)
+			}
+		}
+	}
+	if len(untagged) > 0 {
+		add(false, "begin each template with #graphql, as in gql`#graphql query ...`", "templates tagged as GraphQL lack the #graphql marker, so they are ignored, in %s", strings.Join(untagged, ", "))
+	} else if documents == 0 {
+		add(false, "begin each GraphQL template literal with #graphql, as in `#graphql query ...`", "the inputs contain no GraphQL documents, so nothing is generated")
+	}
+	if documents == 0 {
+		return findings
+	}
+
+	res, err := g.Generate(ctx, ioutil.Discard, args)
+	if err != nil {
+		add(false, "fix the error, then run doctor again", "generation failed: %v", err)
+		return findings
+	}
+	failures := 0
+	for _, d := range res.Diagnostics {
+		if d.Severity == generate.SeverityError {
+			failures++
+		}
+	}
+	if failures > 0 {
+		add(false, "run extractgqlts without doctor to see them; generation fails while any remain", "generation reports errors (%d)", failures)
+	}
+
+	unmapped, err := typer.UnmappedScalars(tp.Schema, res.Types, tp.Options.Scalars)
+	if err != nil || len(unmapped) == 0 {
+		return findings
+	}
+	var names []string
+	for _, scalar := range unmapped {
+		names = append(names, scalar.Scalar)
+	}
+	if verifyScalarsDir == "" || !relativeScalars {
+		add(true, fmt.Sprintf("pass --verify-scalars=DIR to check that the module exports them, or map them with --scalar %s=string", names[0]),
+			"unmapped custom scalars are imported from %q: %s", scalarsModule, strings.Join(names, ", "))
+		return findings
+	}
+	if err := generate.VerifyScalarsModule(verifyScalarsDir, scalarsModule, names); err != nil {
+		add(false, fmt.Sprintf("export the scalars from the module, such as `export type %s = string;`, or map them with --scalar", names[0]), "%v", err)
+	}
+	return findings
+}
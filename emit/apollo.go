@@ -0,0 +1,46 @@
+package emit
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits an Apollo operation manifest, as written by `apollo client:extract`,
+// for safelisting operations with the Apollo operation registry. The documents
+// are the trusted documents of the operations, and their signatures are the
+// trusted document IDs. See TrustedDocuments.
+type ApolloManifest struct {
+	IDs DocumentIDs
+}
+
+type apolloManifest struct {
+	Version    int                       `json:"version"`
+	Operations []apolloManifestOperation `json:"operations"`
+}
+
+type apolloManifestOperation struct {
+	Signature string `json:"signature"`
+	Document  string `json:"document"`
+}
+
+func (e *ApolloManifest) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	docs, err := TrustedDocuments(types, e.IDs)
+	if err != nil {
+		return err
+	}
+	manifest := apolloManifest{
+		Version:    2,
+		Operations: make([]apolloManifestOperation, 0, len(docs)),
+	}
+	for _, doc := range docs {
+		manifest.Operations = append(manifest.Operations, apolloManifestOperation{
+			Signature: doc.ID,
+			Document:  doc.Document,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(manifest)
+}
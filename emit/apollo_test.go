@@ -0,0 +1,26 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApolloManifest(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &ApolloManifest{}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, `query Now { now }`, `fragment UserName on User { name }`))) {
+		return
+	}
+	assert.Equal(t, `{
+  "version": 2,
+  "operations": [
+    {
+      "signature": "e2b911f89125614e8bd8be9ec2508c90f1444f3766eef9359c77e0120f45e062",
+      "document": "query Now {\n\tnow\n}\n"
+    }
+  ]
+}
+`, buf.String())
+}
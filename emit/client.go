@@ -0,0 +1,165 @@
+package emit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Clients are TypeScript modules with a function, or similar, per named
+// operation. They are emitted alongside the types, which they import from
+// TypesModule.
+
+// Constructors for the clients selectable by name, as with the --client-style
+// flag of the extractgqlts command.
+var Clients = map[string]func(typesModule string) Emitter{
+	"fetch":           func(typesModule string) Emitter { return &FetchClient{TypesModule: typesModule} },
+	"graphql-request": func(typesModule string) Emitter { return &GraphQLRequestClient{TypesModule: typesModule} },
+	"graphql-ws":      func(typesModule string) Emitter { return &GraphQLWSClient{TypesModule: typesModule} },
+	"persisted":       func(typesModule string) Emitter { return &PersistedClient{TypesModule: typesModule} },
+	"react-apollo":    func(typesModule string) Emitter { return &ReactApolloClient{TypesModule: typesModule} },
+	"react-query":     func(typesModule string) Emitter { return &ReactQueryClient{TypesModule: typesModule} },
+	"sse":             func(typesModule string) Emitter { return &SSEClient{TypesModule: typesModule} },
+	"svelte-urql":     func(typesModule string) Emitter { return &SvelteURQLClient{TypesModule: typesModule} },
+	"sveltekit":       func(typesModule string) Emitter { return &SvelteKitClient{TypesModule: typesModule} },
+}
+
+// Returns the sorted names of all clients.
+func ClientNames() []string {
+	names := make([]string, 0, len(Clients))
+	for name := range Clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func NewClient(name, typesModule string) (Emitter, error) {
+	newClient, ok := Clients[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown client style %q, expected one of: %s", name, strings.Join(ClientNames(), ", "))
+	}
+	return newClient(typesModule), nil
+}
+
+const defaultTypesModule = "./types.generated"
+
+// A named operation, as seen by clients.
+type operation struct {
+	Kind typer.OperationKind
+	Name string
+	// A JavaScript identifier for the operation, such as getUser.
+	Func  string
+	Query string
+	// See typer.QueryType.Sent.
+	Sent      string
+	Data      string
+	Variables string
+	// Whether the operation has no variables, so they may be omitted.
+	NoVariables bool
+	// Whether the variables may hold uploads. See typer.QueryType.
+	Multipart bool
+	// Whether results are streamed, as for subscriptions and live queries.
+	Streamed bool
+}
+
+// Returns the named operations of the given kinds, in order of appearance,
+// and the declarations they reference. Operations that would duplicate the
+// function of an earlier one are omitted.
+func clientOperations(types typer.GeneratedTypes, kinds ...typer.OperationKind) (ops []operation, imports []string) {
+	decls := typer.NewDeclarationSet(types.Declarations)
+	seen := make(map[string]bool)
+	for _, entry := range types.QueryMap {
+		if entry.Name == "" || !hasKind(kinds, entry.Operation) {
+			continue
+		}
+		data, ok1 := entry.Data.(typer.NamedType)
+		variables, ok2 := entry.Variables.(typer.NamedType)
+		if !ok1 || !ok2 {
+			continue
+		}
+		fn := funcName(entry.Name)
+		if seen[fn] {
+			continue
+		}
+		seen[fn] = true
+		op := operation{
+			Kind:      entry.Operation,
+			Name:      entry.Name,
+			Func:      fn,
+			Query:     entry.Query,
+			Sent:      entry.Sent,
+			Data:      data.Name,
+			Variables: variables.Name,
+			Multipart: entry.Multipart,
+			Streamed:  entry.Streamed(),
+		}
+		if decl, ok := decls.Get(variables.Name); ok {
+			obj, isObject := decl.Type.(typer.ObjectType)
+			op.NoVariables = isObject && len(obj.Fields) == 0
+		}
+		ops = append(ops, op)
+		imports = append(imports, data.Name, variables.Name)
+	}
+	return ops, imports
+}
+
+func hasKind(kinds []typer.OperationKind, kind typer.OperationKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Converts an operation name to a function name, such as GetUser to getUser.
+func funcName(name string) string {
+	fn := strings.ToLower(name[:1]) + name[1:]
+	if reservedWords[fn] {
+		fn += "_"
+	}
+	return fn
+}
+
+var reservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true,
+	"do": true, "else": true, "enum": true, "export": true, "extends": true,
+	"false": true, "finally": true, "for": true, "function": true, "if": true,
+	"import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true,
+	"true": true, "try": true, "typeof": true, "var": true, "void": true,
+	"while": true, "with": true, "yield": true, "let": true, "static": true,
+	"implements": true, "interface": true, "package": true, "private": true,
+	"protected": true, "public": true, "await": true,
+}
+
+func writeClientHeader(ew *errWriter, typesModule string, imports []string) {
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	if len(imports) > 0 {
+		writeTypeImports(ew, typesModule, imports)
+		ew.println()
+	}
+}
+
+func writeTypeImports(ew *errWriter, typesModule string, imports []string) {
+	if len(imports) == 0 {
+		return
+	}
+	if typesModule == "" {
+		typesModule = defaultTypesModule
+	}
+	ew.printf("import type { %s } from %s;\n", strings.Join(imports, ", "), typer.StringToJSON(typesModule))
+}
+
+// The parameter list of an operation function, after any leading parameters.
+func variablesParam(op operation) string {
+	if op.NoVariables {
+		return fmt.Sprintf("variables: %s = {}", op.Variables)
+	}
+	return fmt.Sprintf("variables: %s", op.Variables)
+}
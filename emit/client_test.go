@@ -0,0 +1,369 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const clientTestSchema = `
+type Query {
+	user(id: ID!): User
+	now: String!
+}
+
+type Mutation {
+	deleteUser(id: ID!): Boolean!
+}
+
+type Subscription {
+	now: String!
+}
+
+type User {
+	id: ID!
+	name: String!
+}
+`
+
+// Types each query against clientTestSchema.
+func clientTestTypes(t *testing.T, queries ...string) typer.GeneratedTypes {
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema}),
+	}
+	for _, query := range queries {
+		tp.PrepareString("ops.ts", query)
+	}
+	for _, query := range queries {
+		if _, _, err := tp.VisitString("ops.ts", query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tp.GeneratedTypes
+}
+
+var clientTestQueries = []string{
+	`query GetUser($id: ID!) { user(id: $id) { name } }`,
+	`query Now { now }`,
+	`mutation Delete($id: ID!) { deleteUser(id: $id) }`,
+	`subscription Ticks { now }`,
+	`{ now }`,
+	`query GetUser { now }`,
+}
+
+func TestClientOperations(t *testing.T) {
+	ops, imports := clientOperations(clientTestTypes(t, clientTestQueries...), typer.OperationQuery, typer.OperationMutation)
+	assert.Equal(t, []operation{
+		{
+			Kind:      typer.OperationQuery,
+			Name:      "GetUser",
+			Func:      "getUser",
+			Query:     clientTestQueries[0],
+			Data:      "Query_GetUser_Data",
+			Variables: "Query_GetUser_Variables",
+		},
+		{
+			Kind:        typer.OperationQuery,
+			Name:        "Now",
+			Func:        "now",
+			Query:       clientTestQueries[1],
+			Data:        "Query_Now_Data",
+			Variables:   "Query_Now_Variables",
+			NoVariables: true,
+		},
+		{
+			Kind:      typer.OperationMutation,
+			Name:      "Delete",
+			Func:      "delete_",
+			Query:     clientTestQueries[2],
+			Data:      "Mutation_Delete_Data",
+			Variables: "Mutation_Delete_Variables",
+		},
+	}, ops)
+	assert.Equal(t, []string{
+		"Query_GetUser_Data", "Query_GetUser_Variables",
+		"Query_Now_Data", "Query_Now_Variables",
+		"Mutation_Delete_Data", "Mutation_Delete_Variables",
+	}, imports)
+}
+
+func TestFetchClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &FetchClient{}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, clientTestQueries[:2]...))) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `import type { Query_GetUser_Data, Query_GetUser_Variables, Query_Now_Data, Query_Now_Variables } from "./types.generated";`)
+	assert.Contains(t, out, `export function setTransport(t: Transport): void {`)
+	assert.Contains(t, out, `form.append("operations", `)
+	assert.Contains(t, out, `
+export async function getUser(variables: Query_GetUser_Variables): Promise<Query_GetUser_Data> {
+  return (await send("query GetUser($id: ID!) { user(id: $id) { name } }", variables)) as Query_GetUser_Data;
+}
+
+export async function now(variables: Query_Now_Variables = {}): Promise<Query_Now_Data> {
+  return (await send("query Now { now }", variables)) as Query_Now_Data;
+}
+`)
+}
+
+func TestFetchClientMultipart(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &FetchClient{}
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{
+			Name:  "schema.gql",
+			Input: "type Query { ok: Boolean }\ntype Mutation { setAvatar(file: Upload!): Boolean! }\nscalar Upload",
+		}),
+	}
+	if _, _, err := tp.VisitString("ops.ts", `mutation SetAvatar($file: Upload!) { setAvatar(file: $file) }`); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.NoError(t, emitter.Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Contains(t, buf.String(), `
+export async function setAvatar(variables: Mutation_SetAvatar_Variables): Promise<Mutation_SetAvatar_Data> {
+  return (await send("mutation SetAvatar($file: Upload!) { setAvatar(file: $file) }", variables, true)) as Mutation_SetAvatar_Data;
+}
+`)
+}
+
+func TestGraphQLRequestClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &GraphQLRequestClient{TypesModule: "./types"}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, clientTestQueries[:3]...))) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `import type { GraphQLClient } from "graphql-request";
+import type { Query_GetUser_Data, Query_GetUser_Variables, Query_Now_Data, Query_Now_Variables, Mutation_Delete_Data, Mutation_Delete_Variables } from "./types";
+`)
+	assert.Contains(t, out, `
+export function getSdk(client: GraphQLClient, withWrapper: SdkFunctionWrapper = defaultWrapper) {
+  return {
+    GetUser(variables: Query_GetUser_Variables, requestHeaders?: RequestHeaders): Promise<Query_GetUser_Data> {
+      return withWrapper((wrappedRequestHeaders) => client.request<Query_GetUser_Data>("query GetUser($id: ID!) { user(id: $id) { name } }", variables, { ...requestHeaders, ...wrappedRequestHeaders }), "GetUser", "query", variables);
+    },
+    Now(variables?: Query_Now_Variables, requestHeaders?: RequestHeaders): Promise<Query_Now_Data> {
+      return withWrapper((wrappedRequestHeaders) => client.request<Query_Now_Data>("query Now { now }", variables, { ...requestHeaders, ...wrappedRequestHeaders }), "Now", "query", variables);
+    },
+    Delete(variables: Mutation_Delete_Variables, requestHeaders?: RequestHeaders): Promise<Mutation_Delete_Data> {
+      return withWrapper((wrappedRequestHeaders) => client.request<Mutation_Delete_Data>("mutation Delete($id: ID!) { deleteUser(id: $id) }", variables, { ...requestHeaders, ...wrappedRequestHeaders }), "Delete", "mutation", variables);
+    },
+  };
+}
+
+export type Sdk = ReturnType<typeof getSdk>;
+`)
+}
+
+func TestSvelteURQLClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &SvelteURQLClient{}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, clientTestQueries[1:4]...))) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import { queryStore, mutationStore, subscriptionStore } from "@urql/svelte";
+import type { Client } from "@urql/svelte";
+import type { Query_Now_Data, Query_Now_Variables, Mutation_Delete_Data, Mutation_Delete_Variables, Subscription_Ticks_Data, Subscription_Ticks_Variables } from "./types.generated";
+
+export function queryStore_Now(client: Client, variables: Query_Now_Variables = {}) {
+  return queryStore<Query_Now_Data, Query_Now_Variables>({ client, query: "query Now { now }", variables });
+}
+
+export function mutationStore_Delete(client: Client, variables: Mutation_Delete_Variables) {
+  return mutationStore<Mutation_Delete_Data, Mutation_Delete_Variables>({ client, query: "mutation Delete($id: ID!) { deleteUser(id: $id) }", variables });
+}
+
+export function subscriptionStore_Ticks(client: Client, variables: Subscription_Ticks_Variables = {}) {
+  return subscriptionStore<Subscription_Ticks_Data, Subscription_Ticks_Data, Subscription_Ticks_Variables>({ client, query: "subscription Ticks { now }", variables });
+}
+`, buf.String())
+}
+
+func TestSvelteKitClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &SvelteKitClient{}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, clientTestQueries[:4]...))) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `import { error } from "@sveltejs/kit";`)
+	assert.Contains(t, out, `
+export function load_GetUser(fetch: Fetch, variables: Query_GetUser_Variables): Promise<Result<Query_GetUser_Data, Query_GetUser_Variables>> {
+  return request(fetch, "query GetUser($id: ID!) { user(id: $id) { name } }", variables);
+}
+
+export function load_Now(fetch: Fetch, variables: Query_Now_Variables = {}): Promise<Result<Query_Now_Data, Query_Now_Variables>> {
+  return request(fetch, "query Now { now }", variables);
+}
+
+export function mutate_Delete(fetch: Fetch, variables: Mutation_Delete_Variables): Promise<Result<Mutation_Delete_Data, Mutation_Delete_Variables>> {
+  return request(fetch, "mutation Delete($id: ID!) { deleteUser(id: $id) }", variables);
+}
+`)
+	assert.NotContains(t, out, "Ticks")
+}
+
+func TestReactQueryClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &ReactQueryClient{}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, clientTestQueries[1:3]...))) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `export function setTransport(t: Transport): void {`)
+	assert.Contains(t, out, `
+export function useNowQuery(variables: Query_Now_Variables = {}, options?: Omit<UseQueryOptions<Query_Now_Data>, "queryKey" | "queryFn">) {
+  return useQuery<Query_Now_Data>({
+    queryKey: ["Now", variables],
+    queryFn: () => send("query Now { now }", variables) as Promise<Query_Now_Data>,
+    ...options,
+  });
+}
+
+export function useDeleteMutation(options?: Omit<UseMutationOptions<Mutation_Delete_Data, Error, Mutation_Delete_Variables>, "mutationFn">) {
+  return useMutation<Mutation_Delete_Data, Error, Mutation_Delete_Variables>({
+    mutationFn: (variables: Mutation_Delete_Variables) => send("mutation Delete($id: ID!) { deleteUser(id: $id) }", variables) as Promise<Mutation_Delete_Data>,
+    ...options,
+  });
+}
+`)
+}
+
+func TestGraphQLWSClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &GraphQLWSClient{}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, clientTestQueries[:4]...))) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `import type { Subscription_Ticks_Data, Subscription_Ticks_Variables } from "./types.generated";`)
+	assert.Contains(t, out, `
+export function subscribe_Ticks(client: Client, variables: Subscription_Ticks_Variables = {}, handlers: Handlers<Subscription_Ticks_Data>): () => void {
+  return subscribe(client, "subscription Ticks { now }", variables, handlers);
+}
+
+export function iterate_Ticks(client: Client, variables: Subscription_Ticks_Variables = {}): AsyncGenerator<Subscription_Ticks_Data> {
+  return iterate(client, "subscription Ticks { now }", variables);
+}
+`)
+	assert.NotContains(t, out, "GetUser")
+}
+
+func TestReactApolloClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &ReactApolloClient{}
+	queries := append([]string{`fragment UserName on User { name }`}, clientTestQueries[:4]...)
+	queries[1] = `query GetUser($id: ID!) { user(id: $id) { ...UserName } }`
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, queries...))) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `import type { LazyQueryHookOptions, MutationHookOptions, QueryHookOptions, SubscriptionHookOptions, TypedDocumentNode } from "@apollo/client";`)
+	assert.Contains(t, out, `
+export const GetUserDocument: TypedDocumentNode<Query_GetUser_Data, Query_GetUser_Variables> = gql("query GetUser ($id: ID!) {\n\tuser(id: $id) {\n\t\t... UserName\n\t}\n}\nfragment UserName on User {\n\tname\n}\n");
+
+export function useGetUserQuery(options: QueryHookOptions<Query_GetUser_Data, Query_GetUser_Variables> & { variables: Query_GetUser_Variables }) {
+  return useQuery<Query_GetUser_Data, Query_GetUser_Variables>(GetUserDocument, options);
+}
+
+export function useGetUserLazyQuery(options?: LazyQueryHookOptions<Query_GetUser_Data, Query_GetUser_Variables>) {
+  return useLazyQuery<Query_GetUser_Data, Query_GetUser_Variables>(GetUserDocument, options);
+}
+`)
+	assert.Contains(t, out, `
+export function useNowQuery(options?: QueryHookOptions<Query_Now_Data, Query_Now_Variables>) {
+  return useQuery<Query_Now_Data, Query_Now_Variables>(NowDocument, options);
+}
+`)
+	assert.Contains(t, out, `
+export function useDeleteMutation(options?: MutationHookOptions<Mutation_Delete_Data, Mutation_Delete_Variables>) {
+  return useMutation<Mutation_Delete_Data, Mutation_Delete_Variables>(DeleteDocument, options);
+}
+`)
+	assert.Contains(t, out, `
+export function useTicksSubscription(options?: SubscriptionHookOptions<Subscription_Ticks_Data, Subscription_Ticks_Variables>) {
+  return useSubscription<Subscription_Ticks_Data, Subscription_Ticks_Variables>(TicksDocument, options);
+}
+`)
+}
+
+func TestPersistedClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &PersistedClient{}
+	types := clientTestTypes(t, clientTestQueries[:2]...)
+	if !assert.NoError(t, emitter.Emit(&buf, types)) {
+		return
+	}
+	docs, err := TrustedDocuments(types, DocumentIDs{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `export function persistedTransport(url: string, init: RequestInit = {}): Transport {`)
+	assert.Contains(t, out, `
+const getUserOperation: PersistedOperation = {
+  operationName: "GetUser",
+  query: "query GetUser ($id: ID!) {\n\tuser(id: $id) {\n\t\tname\n\t}\n}\n",
+  sha256Hash: "`+docs[0].ID+`",
+};
+
+export async function getUser(variables: Query_GetUser_Variables): Promise<Query_GetUser_Data> {
+  return (await send(getUserOperation, variables)) as Query_GetUser_Data;
+}
+`)
+	assert.Contains(t, out, `
+export async function now(variables: Query_Now_Variables = {}): Promise<Query_Now_Data> {
+  return (await send(nowOperation, variables)) as Query_Now_Data;
+}
+`)
+}
+
+func TestSSEClient(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &SSEClient{}
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{
+			Name:  "schema.gql",
+			Input: clientTestSchema + "\ndirective @live on QUERY",
+		}),
+	}
+	queries := append([]string{`query LiveUser($id: ID!) @live { user(id: $id) { name } }`}, clientTestQueries[1:4]...)
+	for _, query := range queries {
+		if _, _, err := tp.VisitString("ops.ts", query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !assert.NoError(t, emitter.Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `import type { Query_LiveUser_Data, Query_LiveUser_Variables, Subscription_Ticks_Data, Subscription_Ticks_Variables } from "./types.generated";
+
+export const transports = {
+  LiveUser: "sse",
+  Now: "http",
+  Delete: "http",
+  Ticks: "sse",
+} as const;
+`)
+	assert.Contains(t, out, `
+export function subscribe_LiveUser(url: string, variables: Query_LiveUser_Variables, handlers: Handlers<Query_LiveUser_Data>): () => void {
+  return subscribe(url, "LiveUser", "query LiveUser($id: ID!) @live { user(id: $id) { name } }", variables, handlers);
+}
+
+export function subscribe_Ticks(url: string, variables: Subscription_Ticks_Variables = {}, handlers: Handlers<Subscription_Ticks_Data>): () => void {
+  return subscribe(url, "Ticks", "subscription Ticks { now }", variables, handlers);
+}
+`)
+	assert.NotContains(t, out, "subscribe_Now")
+}
@@ -0,0 +1,154 @@
+package emit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Formats of Collection.
+var CollectionFormats = []string{"postman", "insomnia", "graphiql"}
+
+// Emits the named operations as a collection of requests, so that developers
+// can replay the application's queries against another environment. Each
+// request carries its operation's trusted document, so fragments are inlined.
+// See TrustedDocuments.
+//
+// The postman format is a Postman v2.1 collection, which Insomnia can also
+// import. The insomnia format is an Insomnia v4 export. The graphiql format is
+// GraphiQL's tab state, to be stored under the graphiql:tabState key of the
+// browser's localStorage.
+type Collection struct {
+	// One of CollectionFormats. Defaults to postman.
+	Format string
+	// URL of the GraphQL endpoint. Defaults to an environment variable named
+	// endpoint, in the syntax of the format. Unused by graphiql.
+	Endpoint string
+}
+
+func (e *Collection) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	docs, err := TrustedDocuments(types, DocumentIDs{})
+	if err != nil {
+		return err
+	}
+	var named []TrustedDocument
+	for _, doc := range docs {
+		if doc.Name != "" {
+			named = append(named, doc)
+		}
+	}
+
+	var collection interface{}
+	switch e.Format {
+	case "", "postman":
+		collection = postmanCollection(named, e.endpoint("{{endpoint}}"))
+	case "insomnia":
+		collection = insomniaExport(named, e.endpoint("{{ _.endpoint }}"))
+	case "graphiql":
+		collection = graphiqlTabState(named)
+	default:
+		return fmt.Errorf("unknown collection format %q, expected one of: %s", e.Format, strings.Join(CollectionFormats, ", "))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(collection)
+}
+
+func (e *Collection) endpoint(variable string) string {
+	if e.Endpoint != "" {
+		return e.Endpoint
+	}
+	return variable
+}
+
+type jsonObject = map[string]interface{}
+
+func postmanCollection(docs []TrustedDocument, endpoint string) jsonObject {
+	items := make([]jsonObject, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, jsonObject{
+			"name": doc.Name,
+			"request": jsonObject{
+				"method": "POST",
+				"header": []jsonObject{
+					{"key": "Content-Type", "value": "application/json"},
+				},
+				"body": jsonObject{
+					"mode": "graphql",
+					"graphql": jsonObject{
+						"query":     doc.Document,
+						"variables": "{}",
+					},
+				},
+				"url": endpoint,
+			},
+		})
+	}
+	return jsonObject{
+		"info": jsonObject{
+			"name":   "extractgqlts",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		"item": items,
+	}
+}
+
+func insomniaExport(docs []TrustedDocument, endpoint string) jsonObject {
+	const workspace = "wrk_extractgqlts"
+	resources := []jsonObject{
+		{"_id": workspace, "_type": "workspace", "name": "extractgqlts"},
+	}
+	for _, doc := range docs {
+		body, err := json.Marshal(jsonObject{
+			"query":         doc.Document,
+			"operationName": doc.Name,
+		})
+		if err != nil {
+			panic(err)
+		}
+		resources = append(resources, jsonObject{
+			"_id":      "req_" + doc.ID[:16],
+			"_type":    "request",
+			"parentId": workspace,
+			"name":     doc.Name,
+			"method":   "POST",
+			"url":      endpoint,
+			"headers": []jsonObject{
+				{"name": "Content-Type", "value": "application/json"},
+			},
+			"body": jsonObject{
+				"mimeType": "application/graphql",
+				"text":     string(body),
+			},
+		})
+	}
+	return jsonObject{
+		"_type":           "export",
+		"__export_format": 4,
+		"__export_source": "extractgqlts",
+		"resources":       resources,
+	}
+}
+
+func graphiqlTabState(docs []TrustedDocument) jsonObject {
+	tabs := make([]jsonObject, 0, len(docs))
+	for _, doc := range docs {
+		tabs = append(tabs, jsonObject{
+			"id":            doc.ID[:16],
+			"hash":          nil,
+			"title":         doc.Name,
+			"query":         doc.Document,
+			"variables":     nil,
+			"headers":       nil,
+			"operationName": doc.Name,
+			"response":      nil,
+		})
+	}
+	return jsonObject{
+		"tabs":           tabs,
+		"activeTabIndex": 0,
+	}
+}
@@ -0,0 +1,114 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollection(t *testing.T) {
+	types := clientTestTypes(t, `query Now { now }`, `{ now }`)
+	tests := []struct {
+		Format   string
+		Endpoint string
+		Expected string
+	}{
+		{
+			Format: "postman",
+			Expected: `{
+  "info": {
+    "name": "extractgqlts",
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "item": [
+    {
+      "name": "Now",
+      "request": {
+        "body": {
+          "graphql": {
+            "query": "query Now {\n\tnow\n}\n",
+            "variables": "{}"
+          },
+          "mode": "graphql"
+        },
+        "header": [
+          {
+            "key": "Content-Type",
+            "value": "application/json"
+          }
+        ],
+        "method": "POST",
+        "url": "{{endpoint}}"
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			Format:   "insomnia",
+			Endpoint: "https://staging.example.com/graphql",
+			Expected: `{
+  "__export_format": 4,
+  "__export_source": "extractgqlts",
+  "_type": "export",
+  "resources": [
+    {
+      "_id": "wrk_extractgqlts",
+      "_type": "workspace",
+      "name": "extractgqlts"
+    },
+    {
+      "_id": "req_e2b911f89125614e",
+      "_type": "request",
+      "body": {
+        "mimeType": "application/graphql",
+        "text": "{\"operationName\":\"Now\",\"query\":\"query Now {\\n\\tnow\\n}\\n\"}"
+      },
+      "headers": [
+        {
+          "name": "Content-Type",
+          "value": "application/json"
+        }
+      ],
+      "method": "POST",
+      "name": "Now",
+      "parentId": "wrk_extractgqlts",
+      "url": "https://staging.example.com/graphql"
+    }
+  ]
+}
+`,
+		},
+		{
+			Format: "graphiql",
+			Expected: `{
+  "activeTabIndex": 0,
+  "tabs": [
+    {
+      "hash": null,
+      "headers": null,
+      "id": "e2b911f89125614e",
+      "operationName": "Now",
+      "query": "query Now {\n\tnow\n}\n",
+      "response": null,
+      "title": "Now",
+      "variables": null
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		emitter := &Collection{Format: test.Format, Endpoint: test.Endpoint}
+		if assert.NoError(t, emitter.Emit(&buf, types), test.Format) {
+			assert.Equal(t, test.Expected, buf.String(), test.Format)
+		}
+	}
+
+	err := (&Collection{Format: "bruno"}).Emit(&bytes.Buffer{}, types)
+	assert.EqualError(t, err, `unknown collection format "bruno", expected one of: postman, insomnia, graphiql`)
+}
@@ -0,0 +1,26 @@
+package emit
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Emits, as JSON, which types and fields of the schema are used by at least
+// one operation. See typer.SchemaCoverage.
+type Coverage struct {
+	// The schema the types were generated against.
+	Schema *ast.Schema
+}
+
+func (e *Coverage) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	coverage, err := typer.SchemaCoverage(e.Schema, types)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(coverage)
+}
@@ -0,0 +1,38 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestCoverage(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema})
+	types := clientTestTypes(t, `{ now }`, `subscription { now }`)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Coverage{Schema: schema}).Emit(&buf, types)) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `"fieldsUsed": 2,
+  "fieldsTotal": 6,`)
+	assert.Contains(t, out, `{
+      "name": "User",
+      "kind": "OBJECT",
+      "used": false,
+      "fields": [
+        {
+          "name": "id",
+          "used": false
+        },
+        {
+          "name": "name",
+          "used": false
+        }
+      ]
+    }`)
+}
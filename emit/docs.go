@@ -0,0 +1,196 @@
+package emit
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a reference of every operation, with its document, its variables,
+// the types of its result, and where it is used, as a living catalog of what
+// the frontend asks of the API. Documents are shown with their fragments
+// inlined. The reference is Markdown, or an HTML page if HTML is set.
+type Docs struct {
+	HTML bool
+}
+
+type docOperation struct {
+	Title     string
+	Locations []string
+	Document  string
+	Variables []docVariable
+	// Declarations of the result type and the types it references.
+	Result string
+}
+
+type docVariable struct {
+	Name     string
+	Type     string
+	Required bool
+}
+
+func (e *Docs) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, err := docOperations(types)
+	if err != nil {
+		return err
+	}
+	if e.HTML {
+		return docsTemplate.Execute(w, ops)
+	}
+	ew := &errWriter{w: w}
+	ew.println("# GraphQL Operations")
+	for _, op := range ops {
+		ew.println()
+		ew.printf("## %s\n", op.Title)
+		ew.println()
+		ew.printf("Used in %s.\n", "`"+strings.Join(op.Locations, "`, `")+"`")
+		ew.println()
+		ew.println("```graphql")
+		ew.printf("%s", op.Document)
+		ew.println("```")
+		if len(op.Variables) > 0 {
+			ew.println()
+			ew.println("| Variable | Type | Required |")
+			ew.println("| --- | --- | --- |")
+			for _, v := range op.Variables {
+				required := "no"
+				if v.Required {
+					required = "yes"
+				}
+				ew.printf("| `$%s` | `%s` | %s |\n", v.Name, strings.ReplaceAll(v.Type, "|", `\|`), required)
+			}
+		}
+		ew.println()
+		ew.println("```typescript")
+		ew.println(op.Result)
+		ew.println("```")
+	}
+	return ew.err
+}
+
+// Returns the operations of types in order of appearance, merging those with
+// the same document.
+func docOperations(types typer.GeneratedTypes) ([]*docOperation, error) {
+	shared, err := sharedFragments(types)
+	if err != nil {
+		return nil, err
+	}
+	decls := typer.NewDeclarationSet(types.Declarations)
+	var ops []*docOperation
+	byQuery := make(map[string]*docOperation)
+	for _, entry := range types.QueryMap {
+		if entry.Operation == typer.OperationFragment {
+			continue
+		}
+		location := fmt.Sprintf("%s:%d:%d", entry.Location.File, entry.Location.Line, entry.Location.Column)
+		if op, ok := byQuery[entry.Query]; ok {
+			op.Locations = append(op.Locations, location)
+			continue
+		}
+		document, err := inlinedDocument(entry, shared)
+		if err != nil {
+			return nil, err
+		}
+		op := &docOperation{
+			Title:     string(entry.Operation) + " " + entry.Name,
+			Locations: []string{location},
+			Document:  document,
+			Variables: docVariables(decls, entry.Variables),
+			Result:    docResult(decls, entry.Data),
+		}
+		if entry.Name == "" {
+			op.Title = "anonymous " + string(entry.Operation) + " at " + location
+		}
+		byQuery[entry.Query] = op
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func docVariables(decls *typer.DeclarationSet, typ typer.Type) []docVariable {
+	if named, ok := typ.(typer.NamedType); ok {
+		if decl, ok := decls.Get(named.Name); ok {
+			typ = decl.Type
+		}
+	}
+	obj, ok := typ.(typer.ObjectType)
+	if !ok {
+		return nil
+	}
+	vars := make([]docVariable, 0, len(obj.Fields))
+	for _, field := range obj.Fields {
+		_, nullable := field.Type.(typer.NullableType)
+		vars = append(vars, docVariable{
+			Name:     field.Name,
+			Type:     typer.RenderType(field.Type),
+			Required: !nullable,
+		})
+	}
+	return vars
+}
+
+// Returns the declaration of a result type, preceded by the declarations it
+// references, directly or not.
+func docResult(decls *typer.DeclarationSet, typ typer.Type) string {
+	named, ok := typ.(typer.NamedType)
+	if !ok {
+		return fmt.Sprintf("type Data = %s;", typer.RenderType(typ))
+	}
+	deps := typer.NewDeclarationSet(nil)
+	var visit func(name string)
+	visit = func(name string) {
+		decl, ok := decls.Get(name)
+		if !ok || !deps.Add(decl) {
+			return
+		}
+		for _, dep := range decl.Dependencies {
+			visit(dep)
+		}
+	}
+	visit(named.Name)
+	var lines []string
+	for _, decl := range deps.Ordered() {
+		lines = append(lines, decl.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>GraphQL Operations</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: auto; }
+pre { background: #f4f4f4; padding: 8px; overflow-x: auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>GraphQL Operations</h1>
+<ul>
+{{- range $i, $op := .}}
+<li><a href="#op-{{$i}}">{{$op.Title}}</a></li>
+{{- end}}
+</ul>
+{{- range $i, $op := .}}
+<h2 id="op-{{$i}}">{{$op.Title}}</h2>
+<p>Used in {{range $j, $loc := $op.Locations}}{{if $j}}, {{end}}<code>{{$loc}}</code>{{end}}.</p>
+<pre>{{$op.Document}}</pre>
+{{- if $op.Variables}}
+<table>
+<tr><th>Variable</th><th>Type</th><th>Required</th></tr>
+{{- range $op.Variables}}
+<tr><td><code>${{.Name}}</code></td><td><code>{{.Type}}</code></td><td>{{if .Required}}yes{{else}}no{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<pre>{{$op.Result}}</pre>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,69 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocs(t *testing.T) {
+	types := clientTestTypes(t,
+		`fragment UserName on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { id ...UserName } }`,
+		`{ now }`,
+	)
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Docs{}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, "# GraphQL Operations\n"+`
+## query GetUser
+
+Used in `+"`ops.ts:1:1`"+`.
+
+`+"```graphql"+`
+query GetUser ($id: ID!) {
+	user(id: $id) {
+		id
+		... UserName
+	}
+}
+fragment UserName on User {
+	name
+}
+`+"```"+`
+
+| Variable | Type | Required |
+| --- | --- | --- |
+| `+"`$id` | `string`"+` | yes |
+
+`+"```typescript"+`
+export type Fragment_UserName_Data = { __typename: "User"; name: string; };
+export type Query_GetUser_Data = { __typename: "Query"; user: (({ __typename: "User"; id: string; } & Fragment_UserName_Data) | null); };
+`+"```"+`
+
+## anonymous query at ops.ts:1:1
+
+Used in `+"`ops.ts:1:1`"+`.
+
+`+"```graphql"+`
+query {
+	now
+}
+`+"```"+`
+
+`+"```typescript"+`
+type Data = { __typename: "Query"; now: string; };
+`+"```"+`
+`, buf.String())
+
+	buf.Reset()
+	if !assert.NoError(t, (&Docs{HTML: true}).Emit(&buf, types)) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `<li><a href="#op-0">query GetUser</a></li>`)
+	assert.Contains(t, out, `<tr><td><code>$id</code></td><td><code>string</code></td><td>yes</td></tr>`)
+	assert.Contains(t, out, `<pre>type Data = { __typename: &#34;Query&#34;; now: string; };</pre>`)
+}
@@ -0,0 +1,51 @@
+package emit
+
+import (
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a TypeScript module with a string constant per named operation, such
+// as Query_GetUser_Document, holding the operation with every fragment it
+// spreads inlined, for transports that cannot resolve fragments defined in
+// other documents.
+type Documents struct{}
+
+func (e *Documents) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	shared, err := sharedFragments(types)
+	if err != nil {
+		return err
+	}
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	seen := make(map[string]bool)
+	for _, entry := range types.QueryMap {
+		data, ok := entry.Data.(typer.NamedType)
+		if !ok || entry.Name == "" || entry.Operation == typer.OperationFragment {
+			continue
+		}
+		name := derivedName(data.Name, "Document")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		document, err := inlinedDocument(entry, shared)
+		if err != nil {
+			return err
+		}
+		ew.println()
+		ew.printf("export const %s = %s;\n", name, typer.StringToJSON(document))
+	}
+	return ew.err
+}
+
+// Names something of an operation after its data declaration, such as
+// Query_GetUser_Document for Query_GetUser_Data and the part Document.
+func derivedName(dataName, part string) string {
+	if strings.HasSuffix(dataName, "_Data") {
+		return strings.TrimSuffix(dataName, "_Data") + "_" + part
+	}
+	return dataName + part
+}
@@ -0,0 +1,55 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestDocuments(t *testing.T) {
+	var buf bytes.Buffer
+	types := clientTestTypes(t,
+		`fragment UserName on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { ...UserName ...UserID } } fragment UserID on User { id ...UserName }`,
+		`{ now }`,
+	)
+	if !assert.NoError(t, (&Documents{}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export const Query_GetUser_Document = "query GetUser ($id: ID!) {\n\tuser(id: $id) {\n\t\t... UserName\n\t\t... UserID\n\t}\n}\nfragment UserID on User {\n\tid\n\t... UserName\n}\nfragment UserName on User {\n\tname\n}\n";
+`, buf.String())
+}
+
+func TestDocumentsAsSent(t *testing.T) {
+	addTypename, _ := typer.TransformByName("add-typename")
+	tp := &typer.Typer{
+		Schema:  gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema}),
+		Options: typer.Options{Transforms: []typer.Transform{addTypename}},
+	}
+	queries := []string{
+		`fragment UserName on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { ...UserName } }`,
+	}
+	for _, query := range queries {
+		tp.PrepareString("ops.ts", query)
+	}
+	for _, query := range queries {
+		if _, _, err := tp.VisitString("ops.ts", query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Documents{}).Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export const Query_GetUser_Document = "query GetUser ($id: ID!) {\n\tuser(id: $id) {\n\t\t... UserName\n\t\t__typename\n\t}\n}\nfragment UserName on User {\n\tname\n\t__typename\n}\n";
+`, buf.String())
+}
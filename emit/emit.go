@@ -0,0 +1,127 @@
+// Package emit renders generated types as output artifacts.
+package emit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// An Emitter writes an artifact derived from generated types.
+type Emitter interface {
+	Emit(w io.Writer, types typer.GeneratedTypes) error
+}
+
+// Constructors for the emitters selectable by name, as with the --target
+// flag of the extractgqlts command.
+var Targets = map[string]func() Emitter{
+	"typescript":  func() Emitter { return &TypeScript{} },
+	"json":        func() Emitter { return &JSON{} },
+	"flow":        func() Emitter { return &Flow{} },
+	"json-schema": func() Emitter { return &JSONSchema{} },
+}
+
+// Returns the sorted names of all targets.
+func TargetNames() []string {
+	names := make([]string, 0, len(Targets))
+	for name := range Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func NewTarget(name string) (Emitter, error) {
+	newEmitter, ok := Targets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown target %q, expected one of: %s", name, strings.Join(TargetNames(), ", "))
+	}
+	return newEmitter(), nil
+}
+
+// Collects write errors, so that emitters can write freely and check for
+// failure once at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, v ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, v...)
+	}
+}
+
+func (ew *errWriter) println(v ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintln(ew.w, v...)
+	}
+}
+
+// Returns the names of the custom scalars, without duplicates, in order of
+// first use.
+func uniqueScalars(types typer.GeneratedTypes) []string {
+	seen := make(map[string]bool, len(types.Scalars))
+	var res []string
+	for _, scalar := range types.Scalars {
+		if seen[scalar] {
+			continue
+		}
+		seen[scalar] = true
+		res = append(res, scalar)
+	}
+	return res
+}
+
+var queryGroupTitles = []struct {
+	kind  typer.OperationKind
+	title string
+}{
+	{typer.OperationQuery, "Queries"},
+	{typer.OperationMutation, "Mutations"},
+	{typer.OperationSubscription, "Subscriptions"},
+	{typer.OperationFragment, "Fragments"},
+}
+
+// Calls write with each entry of a map keyed by query, such as QueryTypes, in
+// order of appearance or, if grouped, grouped by kind and sorted by name,
+// anonymous operations last, with a comment of the given indent before each
+// group.
+func writeQueryEntries(ew *errWriter, indent string, entries []typer.QueryType, grouped bool, write func(entry typer.QueryType)) {
+	if !grouped {
+		for _, entry := range entries {
+			write(entry)
+		}
+		return
+	}
+	first := true
+	for _, group := range queryGroupTitles {
+		var members []typer.QueryType
+		for _, entry := range entries {
+			if entry.Operation == group.kind {
+				members = append(members, entry)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		sort.SliceStable(members, func(i, j int) bool {
+			a, b := members[i].Name, members[j].Name
+			if a == "" || b == "" {
+				return a != "" && b == ""
+			}
+			return a < b
+		})
+		if !first {
+			ew.println()
+		}
+		first = false
+		ew.printf("%s// %s\n", indent, group.title)
+		for _, entry := range members {
+			write(entry)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package emit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// A document as extracted, for tools that read GraphQL from files of their
+// own, such as graphql-eslint.
+type SourceDocument struct {
+	// A file name for the document, unique among those returned together,
+	// derived from its location, such as src_App.tsx-12.graphql.
+	Filename string
+	Text     string
+}
+
+// Returns each distinct document of types, operations and fragments alike,
+// exactly as written, in order of appearance. Fragments remain separate
+// documents, so that tools resolve them as extractgqlts does.
+func SourceDocuments(types typer.GeneratedTypes) []SourceDocument {
+	var docs []SourceDocument
+	seen := make(map[string]bool)
+	filenames := make(map[string]bool)
+	for _, entry := range types.QueryMap {
+		if seen[entry.Query] {
+			continue
+		}
+		seen[entry.Query] = true
+		base := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(entry.Location.File)
+		filename := fmt.Sprintf("%s-%d.graphql", base, entry.Location.Line)
+		for i := 2; filenames[filename]; i++ {
+			filename = fmt.Sprintf("%s-%d-%d.graphql", base, entry.Location.Line, i)
+		}
+		filenames[filename] = true
+		text := entry.Query
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		docs = append(docs, SourceDocument{Filename: filename, Text: text})
+	}
+	return docs
+}
@@ -0,0 +1,27 @@
+package emit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceDocuments(t *testing.T) {
+	types := clientTestTypes(t,
+		`fragment UserName on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { ...UserName } }`,
+		`query GetUser($id: ID!) { user(id: $id) { ...UserName } }`,
+	)
+	types.QueryMap[0].Location.File = "src/user.ts"
+	assert.Equal(t, []SourceDocument{
+		{Filename: "src_user.ts-1.graphql", Text: "fragment UserName on User { name }\n"},
+		{Filename: "ops.ts-1.graphql", Text: "query GetUser($id: ID!) { user(id: $id) { ...UserName } }\n"},
+	}, SourceDocuments(types))
+
+	types = clientTestTypes(t, `query A { now }`, `query B { now }`)
+	docs := SourceDocuments(types)
+	if assert.Len(t, docs, 2) {
+		assert.Equal(t, "ops.ts-1.graphql", docs[0].Filename)
+		assert.Equal(t, "ops.ts-1-2.graphql", docs[1].Filename)
+	}
+}
@@ -0,0 +1,20 @@
+package emit
+
+import "strings"
+
+var scriptExtensions = []string{".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs"}
+
+// Returns module with an explicit .ts extension if it is a relative specifier
+// without one, such as ./scalars.ts for ./scalars. Deno and Node ESM, unlike
+// bundlers, do not guess extensions.
+func ESMSpecifier(module string) string {
+	if !strings.HasPrefix(module, "./") && !strings.HasPrefix(module, "../") {
+		return module
+	}
+	for _, ext := range scriptExtensions {
+		if strings.HasSuffix(module, ext) {
+			return module
+		}
+	}
+	return module + ".ts"
+}
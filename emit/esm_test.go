@@ -0,0 +1,21 @@
+package emit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestESMSpecifier(t *testing.T) {
+	tests := map[string]string{
+		"./scalars":          "./scalars.ts",
+		"../types.generated": "../types.generated.ts",
+		"./scalars.ts":       "./scalars.ts",
+		"./scalars.js":       "./scalars.js",
+		"$lib/scalars":       "$lib/scalars",
+		"zod":                "zod",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, ESMSpecifier(input), input)
+	}
+}
@@ -0,0 +1,107 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a module with an async function per named query and mutation, such
+// as getUser(variables): Promise<Query_GetUser_Data>. Requests are sent by a
+// transport that the application supplies with setTransport, such as the
+// included fetchTransport. Operations whose variables may hold uploads are
+// flagged as multipart, which fetchTransport sends per the GraphQL multipart
+// request specification.
+type FetchClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+const fetchClientRuntime = `// Sends an operation to a GraphQL server and resolves to its data. Multipart
+// is set if the variables may hold files.
+export type Transport = (query: string, variables: Record<string, unknown>, multipart: boolean) => Promise<unknown>;
+
+let transport: Transport | undefined;
+
+// Sets the transport used by every operation function.
+export function setTransport(t: Transport): void {
+  transport = t;
+}
+
+// Returns a transport that POSTs operations to url with fetch, as multipart
+// form data if the variables hold files. Rejects on HTTP errors and GraphQL
+// errors.
+export function fetchTransport(url: string, init: RequestInit = {}): Transport {
+  return async (query, variables, multipart) => {
+    const files = multipart ? extractFiles(variables) : new Map<Blob, string[]>();
+    let body: BodyInit = JSON.stringify({ query, variables });
+    const headers: Record<string, string> = { "Content-Type": "application/json", ...(init.headers as Record<string, string> | undefined) };
+    if (files.size > 0) {
+      const form = new FormData();
+      form.append("operations", JSON.stringify({ query, variables }, (_key, value) => (value instanceof Blob ? null : value)));
+      form.append("map", JSON.stringify(Object.fromEntries([...files.values()].map((paths, i) => [String(i), paths]))));
+      [...files.keys()].forEach((file, i) => form.append(String(i), file));
+      body = form;
+      // Let fetch set the multipart boundary.
+      delete headers["Content-Type"];
+    }
+    const response = await fetch(url, { ...init, method: "POST", headers, body });
+    if (!response.ok) {
+      throw new Error(` + "`GraphQL request failed: ${response.status} ${response.statusText}`" + `);
+    }
+    const result = await response.json();
+    if (result.errors?.length) {
+      throw new Error(result.errors.map((error: { message: string }) => error.message).join("\n"));
+    }
+    return result.data;
+  };
+}
+
+// Returns the files in variables, each with the paths where it occurs, such as
+// variables.input.avatar.
+function extractFiles(variables: Record<string, unknown>): Map<Blob, string[]> {
+  const files = new Map<Blob, string[]>();
+  const visit = (value: unknown, path: string): void => {
+    if (value instanceof Blob) {
+      files.set(value, [...(files.get(value) ?? []), path]);
+    } else if (Array.isArray(value)) {
+      value.forEach((elem, i) => visit(elem, ` + "`${path}.${i}`" + `));
+    } else if (value !== null && typeof value === "object") {
+      Object.entries(value).forEach(([key, elem]) => visit(elem, ` + "`${path}.${key}`" + `));
+    }
+  };
+  visit(variables, "variables");
+  return files;
+}
+
+function send(query: string, variables: Record<string, unknown>, multipart = false): Promise<unknown> {
+  if (transport === undefined) {
+    return Promise.reject(new Error("no GraphQL transport, call setTransport first"));
+  }
+  return transport(query, variables, multipart);
+}
+`
+
+func (e *FetchClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, imports := clientOperations(types, typer.OperationQuery, typer.OperationMutation)
+	ew := &errWriter{w: w}
+	writeClientHeader(ew, e.TypesModule, imports)
+	ew.printf("%s", fetchClientRuntime)
+	for _, op := range ops {
+		ew.println()
+		ew.printf("export async function %s(%s): Promise<%s> {\n", op.Func, variablesParam(op), op.Data)
+		ew.printf("  return (await send(%s)) as %s;\n", sendArgs(op), op.Data)
+		ew.println("}")
+	}
+	return ew.err
+}
+
+// Returns the arguments of fetchClientRuntime's send for op.
+func sendArgs(op operation) string {
+	args := typer.StringToJSON(op.Query) + ", variables"
+	if op.Multipart {
+		args += ", true"
+	}
+	return args
+}
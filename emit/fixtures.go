@@ -0,0 +1,170 @@
+package emit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a fixture factory per named operation and fragment, such as
+// Query_GetUser_Fixture(overrides), returning data of the operation's type
+// with placeholder values, for use as Storybook args. Since fixtures are
+// derived from the types, stories stop compiling when a query's shape changes
+// rather than silently drifting.
+//
+// Strings are empty, numbers zero, booleans false, nullable values present,
+// and lists hold a single element. Where a type has alternatives, the first is
+// used. Custom scalars and enums, whose values are unknown, are the scalar
+// placeholder, which stories should override.
+type Fixtures struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+const fixturesRuntime = `// Stands in for values of custom scalars and enums, which stories should override.
+const scalar = undefined as never;
+`
+
+func (e *Fixtures) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	fw := &fixtureWriter{decls: typer.NewDeclarationSet(types.Declarations)}
+	var names, imports []string
+	seen := make(map[string]bool)
+	for _, entry := range types.QueryMap {
+		data, ok := entry.Data.(typer.NamedType)
+		if !ok || entry.Name == "" || seen[data.Name] {
+			continue
+		}
+		seen[data.Name] = true
+		names = append(names, data.Name)
+		imports = append(imports, data.Name)
+	}
+
+	var body strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&body, "\nexport function %s(overrides: Partial<%s> = {}): %s {\n", derivedName(name, "Fixture"), name, name)
+		body.WriteString("  return {\n")
+		fields, _ := fw.objectFields(typer.NamedType{Name: name})
+		for _, field := range fields {
+			fmt.Fprintf(&body, "    %s: %s,\n", field.Name, fw.value(field.Type, "    "))
+		}
+		body.WriteString("    ...overrides,\n")
+		body.WriteString("  };\n")
+		body.WriteString("}\n")
+	}
+
+	ew := &errWriter{w: w}
+	writeClientHeader(ew, e.TypesModule, imports)
+	out := body.String()
+	if fw.usesScalar {
+		ew.printf("%s", fixturesRuntime)
+	} else {
+		out = strings.TrimPrefix(out, "\n")
+	}
+	ew.printf("%s", out)
+	return ew.err
+}
+
+type fixtureWriter struct {
+	decls      *typer.DeclarationSet
+	usesScalar bool
+}
+
+var fixtureKeywords = map[string]string{
+	"string":  `""`,
+	"number":  "0",
+	"boolean": "false",
+	"unknown": "null",
+}
+
+// Returns a JavaScript expression of type typ. Nested lines are indented
+// relative to indent.
+func (fw *fixtureWriter) value(typ typer.Type, indent string) string {
+	if fields, ok := fw.objectFields(typ); ok {
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, field := range fields {
+			b.WriteString(indent + "  " + field.Name + ": " + fw.value(field.Type, indent+"  ") + ",\n")
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+	switch typ := typ.(type) {
+	case typer.NamedType:
+		if value, ok := fixtureKeywords[typ.Name]; ok {
+			return value
+		}
+		if decl, ok := fw.decls.Get(typ.Name); ok {
+			return fw.value(decl.Type, indent)
+		}
+		fw.usesScalar = true
+		return "scalar"
+	case typer.StringLiteralType:
+		return typer.StringToJSON(typ.Value)
+	case typer.ArrayType:
+		return "[" + fw.value(typ.Elem, indent) + "]"
+	case typer.NullableType:
+		return fw.value(typ.Type, indent)
+	case typer.UnionType:
+		if len(typ.Members) > 0 {
+			return fw.value(typ.Members[0], indent)
+		}
+	case typer.IntersectionType:
+		if len(typ.Members) > 0 {
+			return fw.value(typ.Members[0], indent)
+		}
+	}
+	fw.usesScalar = true
+	return "scalar"
+}
+
+// Returns the fields of typ if it is an object type. Fields of intersected
+// objects are merged, intersecting the types of fields they share.
+func (fw *fixtureWriter) objectFields(typ typer.Type) ([]typer.Field, bool) {
+	switch typ := typ.(type) {
+	case typer.ObjectType:
+		return typ.Fields, true
+	case typer.NamedType:
+		if decl, ok := fw.decls.Get(typ.Name); ok {
+			return fw.objectFields(decl.Type)
+		}
+	case typer.NullableType:
+		return fw.objectFields(typ.Type)
+	case typer.UnionType:
+		if len(typ.Members) > 0 {
+			return fw.objectFields(typ.Members[0])
+		}
+	case typer.IntersectionType:
+		var keys []string
+		merged := make(map[string][]typer.Type)
+		isObject := false
+		for _, member := range typ.Members {
+			fields, ok := fw.objectFields(member)
+			if !ok {
+				continue
+			}
+			isObject = true
+			for _, field := range fields {
+				if _, ok := merged[field.Name]; !ok {
+					keys = append(keys, field.Name)
+				}
+				merged[field.Name] = append(merged[field.Name], field.Type)
+			}
+		}
+		if !isObject {
+			return nil, false
+		}
+		fields := make([]typer.Field, 0, len(keys))
+		for _, key := range keys {
+			field := typer.Field{Name: key, Type: merged[key][0]}
+			if len(merged[key]) > 1 {
+				field.Type = typer.IntersectionType{Members: merged[key]}
+			}
+			fields = append(fields, field)
+		}
+		return fields, true
+	}
+	return nil, false
+}
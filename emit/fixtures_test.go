@@ -0,0 +1,80 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestFixtures(t *testing.T) {
+	var buf bytes.Buffer
+	types := clientTestTypes(t,
+		`fragment UserName on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { id ...UserName } now }`,
+		`{ now }`,
+	)
+	if !assert.NoError(t, (&Fixtures{}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import type { Fragment_UserName_Data, Query_GetUser_Data } from "./types.generated";
+
+export function Fragment_UserName_Fixture(overrides: Partial<Fragment_UserName_Data> = {}): Fragment_UserName_Data {
+  return {
+    __typename: "User",
+    name: "",
+    ...overrides,
+  };
+}
+
+export function Query_GetUser_Fixture(overrides: Partial<Query_GetUser_Data> = {}): Query_GetUser_Data {
+  return {
+    __typename: "Query",
+    now: "",
+    user: {
+      __typename: "User",
+      id: "",
+      name: "",
+    },
+    ...overrides,
+  };
+}
+`, buf.String())
+}
+
+func TestFixturesScalars(t *testing.T) {
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: `
+			type Query { now: Instant!, tags: [String!] }
+			scalar Instant
+		`}),
+	}
+	if _, _, err := tp.VisitString("", `query Clock { now tags }`); !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Fixtures{TypesModule: "./types"}).Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import type { Query_Clock_Data } from "./types";
+
+// Stands in for values of custom scalars and enums, which stories should override.
+const scalar = undefined as never;
+
+export function Query_Clock_Fixture(overrides: Partial<Query_Clock_Data> = {}): Query_Clock_Data {
+  return {
+    __typename: "Query",
+    now: scalar,
+    tags: [""],
+    ...overrides,
+  };
+}
+`, buf.String())
+}
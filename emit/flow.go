@@ -0,0 +1,118 @@
+package emit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a Flow module equivalent to the output of TypeScript. Object types
+// are explicitly inexact, so that intersections with fragment types are
+// inhabitable.
+type Flow struct {
+	// Module from which custom scalars are imported. Defaults to "./scalars".
+	ScalarsModule string
+}
+
+func (e *Flow) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ew := &errWriter{w: w}
+	ew.println("// @flow")
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+
+	if scalars := uniqueScalars(types); len(scalars) > 0 {
+		writeScalarImports(ew, e.ScalarsModule, scalars)
+		ew.println()
+	}
+
+	if decls := typer.NewDeclarationSet(types.Declarations); decls.Len() > 0 {
+		for _, decl := range decls.Ordered() {
+			ew.printf("export type %s = %s;\n", decl.Name, renderFlowType(decl.Type))
+		}
+		ew.println()
+	}
+
+	ew.println("export type QueryTypes = {")
+	for _, entry := range types.QueryMap {
+		ew.printf("  %s: { data: %s, variables: %s },\n", typer.StringToJSON(entry.Query), renderFlowType(entry.Data), renderFlowType(entry.Variables))
+	}
+	ew.println("};")
+	return ew.err
+}
+
+var flowKeywords = map[string]string{
+	"unknown": "mixed",
+	"never":   "empty",
+}
+
+func renderFlowType(typ typer.Type) string {
+	var b strings.Builder
+	writeFlowType(&b, typ)
+	return b.String()
+}
+
+func writeFlowType(b *strings.Builder, typ typer.Type) {
+	switch typ := typ.(type) {
+	case typer.NamedType:
+		if keyword, ok := flowKeywords[typ.Name]; ok {
+			b.WriteString(keyword)
+		} else {
+			b.WriteString(typ.Name)
+		}
+	case typer.StringLiteralType:
+		b.WriteString(typer.StringToJSON(typ.Value))
+	case typer.RawType:
+		b.WriteString(typ.Text)
+	case typer.ObjectType:
+		b.WriteString("{ ")
+		for _, field := range typ.Fields {
+			b.WriteString(field.Name)
+			b.WriteString(": ")
+			writeFlowType(b, field.Type)
+			b.WriteString(", ")
+		}
+		b.WriteString("... }")
+	case typer.ArrayType:
+		b.WriteString("Array<")
+		writeFlowType(b, typ.Elem)
+		b.WriteString(">")
+	case typer.NullableType:
+		b.WriteString("(")
+		writeFlowType(b, typ.Type)
+		b.WriteString(" | null")
+		if typ.Undefined {
+			b.WriteString(" | void")
+		}
+		b.WriteString(")")
+	case typer.UnionType:
+		writeFlowMembers(b, typ.Members, " | ")
+	case typer.IntersectionType:
+		writeFlowMembers(b, typ.Members, " & ")
+	case nil:
+		b.WriteString("mixed")
+	default:
+		panic(fmt.Errorf("unexpected type: %T", typ))
+	}
+}
+
+func writeFlowMembers(b *strings.Builder, members []typer.Type, sep string) {
+	if len(members) == 0 {
+		b.WriteString("empty")
+		return
+	}
+	for i, member := range members {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		// Unions bind more loosely than intersections.
+		if union, ok := member.(typer.UnionType); ok && sep == " & " && len(union.Members) > 1 {
+			b.WriteString("(")
+			writeFlowType(b, member)
+			b.WriteString(")")
+		} else {
+			writeFlowType(b, member)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlow(t *testing.T) {
+	types := clientTestTypes(t,
+		`fragment Named on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { id ...Named } }`,
+		`{ now }`,
+	)
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Flow{}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, `// @flow
+// GENERATED FILE. DO NOT EDIT.
+
+export type Fragment_Named_Data = { __typename: "User", name: string, ... };
+export type Fragment_Named_Variables = { ... };
+export type Query_GetUser_Data = { __typename: "Query", user: ({ __typename: "User", id: string, ... } & Fragment_Named_Data | null), ... };
+export type Query_GetUser_Variables = { id: string, ... };
+
+export type QueryTypes = {
+  "fragment Named on User { name }": { data: Fragment_Named_Data, variables: Fragment_Named_Variables },
+  "query GetUser($id: ID!) { user(id: $id) { id ...Named } }": { data: Query_GetUser_Data, variables: Query_GetUser_Variables },
+  "{ now }": { data: { __typename: "Query", now: string, ... }, variables: { ... } },
+};
+`, buf.String())
+}
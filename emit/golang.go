@@ -0,0 +1,205 @@
+package emit
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a Go source file declaring a struct per declaration, with JSON tags
+// matching response keys, so that Go services and tests share types with the
+// frontend. The document of each named operation is declared as a constant,
+// such as Query_GetUser_Document.
+//
+// Go has no unions, so the alternatives of a union are merged into one struct,
+// as are the members of an intersection. Fragments are embedded. Custom
+// scalars, which Go knows nothing about, are json.RawMessage. GraphQL Ints,
+// which are 32-bit, are int32, and Floats float64.
+type Go struct {
+	// Defaults to "graphql".
+	Package string
+}
+
+func (e *Go) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	gw := &goWriter{scalars: make(map[string]bool)}
+	for _, scalar := range types.Scalars {
+		gw.scalars[scalar] = true
+	}
+
+	var body bytes.Buffer
+	var documents []string
+	for _, entry := range types.QueryMap {
+		if data, ok := entry.Data.(typer.NamedType); ok && entry.Name != "" && entry.Operation != typer.OperationFragment {
+			documents = append(documents, fmt.Sprintf("\t%s = %s\n", derivedName(data.Name, "Document"), strconv.Quote(entry.Query)))
+		}
+	}
+	if len(documents) > 0 {
+		body.WriteString("const (\n")
+		for _, document := range documents {
+			body.WriteString(document)
+		}
+		body.WriteString(")\n\n")
+	}
+	for _, decl := range typer.NewDeclarationSet(types.Declarations).Ordered() {
+		fmt.Fprintf(&body, "type %s %s\n\n", decl.Name, gw.goType(decl.Type))
+	}
+
+	pkg := e.Package
+	if pkg == "" {
+		pkg = "graphql"
+	}
+	var src bytes.Buffer
+	src.WriteString("// Code generated by extractgqlts. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if gw.usesJSON {
+		src.WriteString("import \"encoding/json\"\n\n")
+	}
+	src.Write(body.Bytes())
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting Go: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+type goWriter struct {
+	scalars  map[string]bool
+	usesJSON bool
+}
+
+var goKeywords = map[string]string{
+	"string":  "string",
+	"number":  "float64",
+	"boolean": "bool",
+	"never":   "struct{}",
+}
+
+func (gw *goWriter) rawJSON() string {
+	gw.usesJSON = true
+	return "json.RawMessage"
+}
+
+func (gw *goWriter) goType(typ typer.Type) string {
+	switch typ := typ.(type) {
+	case typer.NamedType:
+		if typ.Scalar == "Int" {
+			return "int32"
+		}
+		if goType, ok := goKeywords[typ.Name]; ok {
+			return goType
+		}
+		if typ.Name == "unknown" || gw.scalars[typ.Name] {
+			return gw.rawJSON()
+		}
+		return typ.Name
+	case typer.StringLiteralType:
+		return "string"
+	case typer.RawType, nil:
+		return gw.rawJSON()
+	case typer.ArrayType:
+		return "[]" + gw.goType(typ.Elem)
+	case typer.NullableType:
+		inner := gw.goType(typ.Type)
+		if strings.HasPrefix(inner, "[]") || inner == "json.RawMessage" {
+			// Already nullable.
+			return inner
+		}
+		return "*" + inner
+	case typer.UnionType:
+		if isStringLiteralUnion(typ) {
+			return "string"
+		}
+		s := &goStruct{}
+		gw.merge(s, typ)
+		return gw.structType(s)
+	case typer.ObjectType, typer.IntersectionType:
+		s := &goStruct{}
+		gw.merge(s, typ)
+		return gw.structType(s)
+	default:
+		panic(fmt.Errorf("unexpected type: %T", typ))
+	}
+}
+
+func isStringLiteralUnion(typ typer.UnionType) bool {
+	for _, member := range typ.Members {
+		if _, ok := member.(typer.StringLiteralType); !ok {
+			return false
+		}
+	}
+	return len(typ.Members) > 0
+}
+
+// The members of a struct under construction.
+type goStruct struct {
+	embedded []string
+	keys     []string
+	types    map[string]typer.Type
+}
+
+// Adds the fields of typ to s. Where alternatives or members share a key, the
+// first type wins.
+func (gw *goWriter) merge(s *goStruct, typ typer.Type) {
+	switch typ := typ.(type) {
+	case typer.ObjectType:
+		if s.types == nil {
+			s.types = make(map[string]typer.Type)
+		}
+		for _, field := range typ.Fields {
+			if _, ok := s.types[field.Name]; !ok {
+				s.keys = append(s.keys, field.Name)
+				s.types[field.Name] = field.Type
+			}
+		}
+	case typer.NamedType:
+		for _, name := range s.embedded {
+			if name == typ.Name {
+				return
+			}
+		}
+		s.embedded = append(s.embedded, typ.Name)
+	case typer.UnionType:
+		for _, member := range typ.Members {
+			gw.merge(s, member)
+		}
+	case typer.IntersectionType:
+		for _, member := range typ.Members {
+			gw.merge(s, member)
+		}
+	}
+}
+
+func (gw *goWriter) structType(s *goStruct) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, name := range s.embedded {
+		fmt.Fprintf(&b, "%s\n", name)
+	}
+	used := make(map[string]bool)
+	for _, key := range s.keys {
+		name := goFieldName(key)
+		for used[name] {
+			name += "_"
+		}
+		used[name] = true
+		fmt.Fprintf(&b, "%s %s `json:%s`\n", name, gw.goType(s.types[key]), strconv.Quote(key))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// Converts a response key to an exported Go field name, such as __typename to
+// Typename.
+func goFieldName(key string) string {
+	key = strings.TrimLeft(key, "_")
+	if key == "" {
+		return "X"
+	}
+	return strings.ToUpper(key[:1]) + key[1:]
+}
@@ -0,0 +1,80 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestGo(t *testing.T) {
+	types := clientTestTypes(t,
+		`fragment Named on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { id ...Named } }`,
+		`{ now }`,
+	)
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Go{Package: "ops"}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, "// Code generated by extractgqlts. DO NOT EDIT.\n\n"+
+		"package ops\n\n"+
+		"const (\n"+
+		"\tQuery_GetUser_Document = \"query GetUser($id: ID!) { user(id: $id) { id ...Named } }\"\n"+
+		")\n\n"+
+		"type Fragment_Named_Data struct {\n"+
+		"\tTypename string `json:\"__typename\"`\n"+
+		"\tName     string `json:\"name\"`\n"+
+		"}\n\n"+
+		"type Fragment_Named_Variables struct {\n"+
+		"}\n\n"+
+		"type Query_GetUser_Data struct {\n"+
+		"\tTypename string `json:\"__typename\"`\n"+
+		"\tUser     *struct {\n"+
+		"\t\tFragment_Named_Data\n"+
+		"\t\tTypename string `json:\"__typename\"`\n"+
+		"\t\tId       string `json:\"id\"`\n"+
+		"\t} `json:\"user\"`\n"+
+		"}\n\n"+
+		"type Query_GetUser_Variables struct {\n"+
+		"\tId string `json:\"id\"`\n"+
+		"}\n", buf.String())
+}
+
+func TestGoNumbers(t *testing.T) {
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: `
+			type Query {
+				users(first: Int): [User!]!
+			}
+
+			type User {
+				age: Int!
+				score: Float
+			}
+		`}),
+	}
+	if _, _, err := tp.VisitString("", `query GetUsers($first: Int) { users(first: $first) { age score } }`); !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Go{}).Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Contains(t, buf.String(), "\t\tAge      int32    `json:\"age\"`\n\t\tScore    *float64 `json:\"score\"`\n")
+	assert.Contains(t, buf.String(), "type Query_GetUsers_Variables struct {\n\tFirst *int32 `json:\"first\"`\n}\n")
+}
+
+func TestGoFieldName(t *testing.T) {
+	for key, expected := range map[string]string{
+		"name":       "Name",
+		"__typename": "Typename",
+		"_":          "X",
+		"firstName":  "FirstName",
+	} {
+		assert.Equal(t, expected, goFieldName(key), "key: %s", key)
+	}
+}
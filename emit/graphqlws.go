@@ -0,0 +1,77 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits helpers per named subscription for graphql-ws clients: a callback
+// based subscribe_Name(client, variables, handlers), which returns a function
+// that unsubscribes, and an AsyncIterable based iterate_Name(client,
+// variables). Payloads with errors are reported as errors.
+type GraphQLWSClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+const graphQLWSRuntime = `export type Handlers<Data> = {
+  next: (data: Data) => void;
+  error?: (error: unknown) => void;
+  complete?: () => void;
+};
+
+function unwrap<Data>(result: ExecutionResult<Data>): Data {
+  if (result.errors?.length) {
+    throw new Error(result.errors.map((error) => error.message).join("\n"));
+  }
+  return result.data as Data;
+}
+
+function subscribe<Data>(client: Client, query: string, variables: Record<string, unknown>, handlers: Handlers<Data>): () => void {
+  return client.subscribe<Data>({ query, variables }, {
+    next: (result) => {
+      let data: Data;
+      try {
+        data = unwrap(result);
+      } catch (error) {
+        handlers.error?.(error);
+        return;
+      }
+      handlers.next(data);
+    },
+    error: (error) => handlers.error?.(error),
+    complete: () => handlers.complete?.(),
+  });
+}
+
+async function* iterate<Data>(client: Client, query: string, variables: Record<string, unknown>): AsyncGenerator<Data> {
+  for await (const result of client.iterate<Data>({ query, variables })) {
+    yield unwrap(result);
+  }
+}
+`
+
+func (e *GraphQLWSClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, imports := clientOperations(types, typer.OperationSubscription)
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	ew.println(`import type { Client, ExecutionResult } from "graphql-ws";`)
+	writeTypeImports(ew, e.TypesModule, imports)
+	ew.println()
+	ew.printf("%s", graphQLWSRuntime)
+	for _, op := range ops {
+		query := typer.StringToJSON(op.Query)
+		ew.println()
+		ew.printf("export function subscribe_%s(client: Client, %s, handlers: Handlers<%s>): () => void {\n", op.Name, variablesParam(op), op.Data)
+		ew.printf("  return subscribe(client, %s, variables, handlers);\n", query)
+		ew.println("}")
+		ew.println()
+		ew.printf("export function iterate_%s(client: Client, %s): AsyncGenerator<%s> {\n", op.Name, variablesParam(op), op.Data)
+		ew.printf("  return iterate(client, %s, variables);\n", query)
+		ew.println("}")
+	}
+	return ew.err
+}
@@ -0,0 +1,143 @@
+package emit
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Attaches static transport hints, such as the HTTP method to send operations
+// with or their cache policy, to the operations of the OperationRegistry that
+// it matches.
+type HintRule struct {
+	// Pattern of the names of matching operations, as of path.Match, such as
+	// Get*. Empty to match every operation, anonymous ones included.
+	Operation string `yaml:"operation"`
+	// Name, without @, of a directive that matching operations have. The
+	// schema must declare it, or --unknown-directives allow it.
+	Directive string `yaml:"directive"`
+	// Values by hint name. Each is a string, number, or boolean.
+	Hints map[string]interface{} `yaml:"hints"`
+}
+
+func (r HintRule) Validate() error {
+	if _, err := path.Match(r.Operation, ""); err != nil {
+		return fmt.Errorf("invalid operation pattern %q: %w", r.Operation, err)
+	}
+	if len(r.Hints) == 0 {
+		return fmt.Errorf("hint rule for operations %q sets no hints", r.Operation)
+	}
+	for name, value := range r.Hints {
+		switch value.(type) {
+		case string, bool, int, int64, float64:
+		default:
+			return fmt.Errorf("invalid hint %s: expected a string, number, or boolean", name)
+		}
+	}
+	return nil
+}
+
+// Reports whether the rule applies to the operation of entry.
+func (r HintRule) matches(entry typer.QueryType) bool {
+	if r.Operation != "" {
+		if ok, _ := path.Match(r.Operation, entry.Name); !ok || entry.Name == "" {
+			return false
+		}
+	}
+	if r.Directive == "" {
+		return true
+	}
+	doc, err := parser.ParseQuery(&ast.Source{Input: entry.Query})
+	if err != nil {
+		return false
+	}
+	for _, op := range doc.Operations {
+		if op.Name == entry.Name {
+			return op.Directives.ForName(r.Directive) != nil
+		}
+	}
+	return false
+}
+
+// Returns the hints of entry, as TypeScript literals by name, with those of
+// later rules taking precedence.
+func operationHints(rules []HintRule, entry typer.QueryType) map[string]string {
+	var hints map[string]string
+	for _, rule := range rules {
+		if !rule.matches(entry) {
+			continue
+		}
+		if hints == nil {
+			hints = make(map[string]string)
+		}
+		for name, value := range rule.Hints {
+			hints[name] = hintLiteral(value)
+		}
+	}
+	return hints
+}
+
+func hintLiteral(value interface{}) string {
+	bs, _ := json.Marshal(value)
+	return string(bs)
+}
+
+// Writes the OperationHints interface, in which each hint is typed as the
+// union of the values that rules give it.
+func writeOperationHints(ew *errWriter, rules []HintRule) {
+	values := make(map[string]map[string]bool)
+	for _, rule := range rules {
+		for name, value := range rule.Hints {
+			if values[name] == nil {
+				values[name] = make(map[string]bool)
+			}
+			values[name][hintLiteral(value)] = true
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		ew.println("export interface OperationHints {}")
+		return
+	}
+	ew.println("export interface OperationHints {")
+	for _, name := range names {
+		literals := make([]string, 0, len(values[name]))
+		for literal := range values[name] {
+			literals = append(literals, literal)
+		}
+		sort.Strings(literals)
+		ew.printf("  %s?: %s;\n", propertyName(name), strings.Join(literals, " | "))
+	}
+	ew.println("}")
+}
+
+// Formats hints as a TypeScript object literal.
+func hintsObject(hints map[string]string) string {
+	fields := make([]string, 0, len(hints))
+	for name, literal := range hints {
+		fields = append(fields, propertyName(name)+": "+literal)
+	}
+	sort.Strings(fields)
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Returns name as a TypeScript property name, quoted unless an identifier.
+func propertyName(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return typer.StringToJSON(name)
+}
@@ -0,0 +1,51 @@
+package emit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHintRuleMatches(t *testing.T) {
+	entry := typer.QueryType{Name: "GetUser", Query: `query Other @cached { now } query GetUser @persisted { now }`}
+	assert.True(t, HintRule{}.matches(entry))
+	assert.True(t, HintRule{Operation: "Get*"}.matches(entry))
+	assert.False(t, HintRule{Operation: "Set*"}.matches(entry))
+	assert.True(t, HintRule{Operation: "Get*", Directive: "persisted"}.matches(entry))
+	assert.False(t, HintRule{Directive: "cached"}.matches(entry))
+	assert.False(t, HintRule{Operation: "*"}.matches(typer.QueryType{Query: `{ now }`}))
+	assert.True(t, HintRule{}.matches(typer.QueryType{Query: `{ now }`}))
+
+	assert.Error(t, HintRule{Operation: "[", Hints: map[string]interface{}{"method": "GET"}}.Validate())
+	assert.Error(t, HintRule{Operation: "Get*"}.Validate())
+	assert.Error(t, HintRule{Hints: map[string]interface{}{"method": []interface{}{"GET"}}}.Validate())
+}
+
+func TestOperationRegistryHints(t *testing.T) {
+	types := clientTestTypes(t,
+		`query GetUser($id: ID!) { user(id: $id) { name } }`,
+		`mutation Delete($id: ID!) { deleteUser(id: $id) }`,
+		`{ now }`,
+	)
+	registry := &OperationRegistry{Hints: []HintRule{
+		{Hints: map[string]interface{}{"method": "POST"}},
+		{Operation: "Get*", Hints: map[string]interface{}{"method": "GET", "cache-ttl": 60}},
+	}}
+	var buf bytes.Buffer
+	if !assert.NoError(t, registry.Emit(&buf, types)) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, "export interface OperationHints {\n  \"cache-ttl\"?: 60;\n  method?: \"GET\" | \"POST\";\n}\n")
+	assert.Contains(t, out, `file: "ops.ts", line: 1, hints: { "cache-ttl": 60, method: "GET" } },`)
+	assert.Equal(t, 2, strings.Count(out, `hints: { method: "POST" }`), out)
+
+	buf.Reset()
+	if assert.NoError(t, (&OperationRegistry{}).Emit(&buf, types)) {
+		assert.Contains(t, buf.String(), "export interface OperationHints {}\n")
+		assert.NotContains(t, buf.String(), "hints: {")
+	}
+}
@@ -0,0 +1,17 @@
+package emit
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits the generated types as JSON, for consumption by other tools.
+type JSON struct{}
+
+func (e *JSON) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(types)
+}
@@ -0,0 +1,125 @@
+package emit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a JSON Schema (draft 2020-12) document mirroring the QueryTypes map
+// of TypeScript: each query string is a property whose value has data and
+// variables properties. Declarations are under $defs, so that named
+// operations may be referenced as, for example, #/$defs/Query_GetUser_Data.
+// Custom scalars accept any value.
+type JSONSchema struct{}
+
+type jsonSchema = map[string]interface{}
+
+func (e *JSONSchema) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	scalars := make(map[string]bool, len(types.Scalars))
+	for _, scalar := range types.Scalars {
+		scalars[scalar] = true
+	}
+	js := &jsonSchemaBuilder{scalars: scalars}
+
+	defs := make(jsonSchema)
+	for _, decl := range typer.NewDeclarationSet(types.Declarations).Ordered() {
+		defs[decl.Name] = js.schema(decl.Type)
+	}
+	queries := make(jsonSchema, len(types.QueryMap))
+	for _, entry := range types.QueryMap {
+		queries[entry.Query] = jsonSchema{
+			"type": "object",
+			"properties": jsonSchema{
+				"data":      js.schema(entry.Data),
+				"variables": js.schema(entry.Variables),
+			},
+			"required": []string{"data", "variables"},
+		}
+	}
+	doc := jsonSchema{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"type":       "object",
+		"properties": queries,
+	}
+	if len(defs) > 0 {
+		doc["$defs"] = defs
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+type jsonSchemaBuilder struct {
+	scalars map[string]bool
+}
+
+var jsonSchemaKeywords = map[string]jsonSchema{
+	"string":  {"type": "string"},
+	"number":  {"type": "number"},
+	"boolean": {"type": "boolean"},
+	"unknown": {},
+	"never":   {"not": jsonSchema{}},
+}
+
+func (js *jsonSchemaBuilder) schema(typ typer.Type) jsonSchema {
+	switch typ := typ.(type) {
+	case typer.NamedType:
+		if schema, ok := jsonSchemaKeywords[typ.Name]; ok {
+			return schema
+		}
+		if js.scalars[typ.Name] {
+			return jsonSchema{"title": typ.Name}
+		}
+		return jsonSchema{"$ref": "#/$defs/" + typ.Name}
+	case typer.StringLiteralType:
+		return jsonSchema{"const": typ.Value}
+	case typer.RawType:
+		return jsonSchema{"title": typ.Text}
+	case typer.ObjectType:
+		properties := make(jsonSchema, len(typ.Fields))
+		required := make([]string, len(typ.Fields))
+		for i, field := range typ.Fields {
+			properties[field.Name] = js.schema(field.Type)
+			required[i] = field.Name
+		}
+		return jsonSchema{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+	case typer.ArrayType:
+		return jsonSchema{"type": "array", "items": js.schema(typ.Elem)}
+	case typer.NullableType:
+		return jsonSchema{"anyOf": []jsonSchema{js.schema(typ.Type), {"type": "null"}}}
+	case typer.UnionType:
+		if len(typ.Members) == 1 {
+			return js.schema(typ.Members[0])
+		}
+		if isStringLiteralUnion(typ) {
+			values := make([]string, len(typ.Members))
+			for i, member := range typ.Members {
+				values[i] = member.(typer.StringLiteralType).Value
+			}
+			return jsonSchema{"enum": values}
+		}
+		return jsonSchema{"anyOf": js.schemas(typ.Members)}
+	case typer.IntersectionType:
+		return jsonSchema{"allOf": js.schemas(typ.Members)}
+	case nil:
+		return jsonSchema{}
+	default:
+		panic(fmt.Errorf("unexpected type: %T", typ))
+	}
+}
+
+func (js *jsonSchemaBuilder) schemas(types []typer.Type) []jsonSchema {
+	res := make([]jsonSchema, len(types))
+	for i, typ := range types {
+		res[i] = js.schema(typ)
+	}
+	return res
+}
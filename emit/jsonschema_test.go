@@ -0,0 +1,61 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema(t *testing.T) {
+	types := clientTestTypes(t,
+		`query GetUser($id: ID!) { user(id: $id) { name } }`,
+	)
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&JSONSchema{}).Emit(&buf, types)) {
+		return
+	}
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"query GetUser($id: ID!) { user(id: $id) { name } }": {
+				"type": "object",
+				"properties": {
+					"data": { "$ref": "#/$defs/Query_GetUser_Data" },
+					"variables": { "$ref": "#/$defs/Query_GetUser_Variables" }
+				},
+				"required": ["data", "variables"]
+			}
+		},
+		"$defs": {
+			"Query_GetUser_Data": {
+				"type": "object",
+				"properties": {
+					"__typename": { "const": "Query" },
+					"user": {
+						"anyOf": [
+							{
+								"type": "object",
+								"properties": {
+									"__typename": { "const": "User" },
+									"name": { "type": "string" }
+								},
+								"required": ["__typename", "name"]
+							},
+							{ "type": "null" }
+						]
+					}
+				},
+				"required": ["__typename", "user"]
+			},
+			"Query_GetUser_Variables": {
+				"type": "object",
+				"properties": {
+					"id": { "type": "string" }
+				},
+				"required": ["id"]
+			}
+		}
+	}`, buf.String())
+}
@@ -0,0 +1,38 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a Mock Service Worker handler factory per named query and mutation,
+// such as mockGetUserQuery(resolver), matching requests by operation name.
+// Resolvers are typed with the operation's data and variables.
+type MSWMocks struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+func (e *MSWMocks) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, imports := clientOperations(types, typer.OperationQuery, typer.OperationMutation)
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	ew.println(`import { graphql } from "msw";`)
+	ew.println(`import type { GraphQLResponseResolver, RequestHandlerOptions } from "msw";`)
+	writeTypeImports(ew, e.TypesModule, imports)
+	for _, op := range ops {
+		suffix, method := "Query", "query"
+		if op.Kind == typer.OperationMutation {
+			suffix, method = "Mutation", "mutation"
+		}
+		typeArgs := op.Data + ", " + op.Variables
+		ew.println()
+		ew.printf("export function mock%s%s(resolver: GraphQLResponseResolver<%s>, options?: RequestHandlerOptions) {\n", op.Name, suffix, typeArgs)
+		ew.printf("  return graphql.%s<%s>(%s, resolver, options);\n", method, typeArgs, typer.StringToJSON(op.Name))
+		ew.println("}")
+	}
+	return ew.err
+}
@@ -0,0 +1,30 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMSWMocks(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &MSWMocks{}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, clientTestQueries[1:5]...))) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import { graphql } from "msw";
+import type { GraphQLResponseResolver, RequestHandlerOptions } from "msw";
+import type { Query_Now_Data, Query_Now_Variables, Mutation_Delete_Data, Mutation_Delete_Variables } from "./types.generated";
+
+export function mockNowQuery(resolver: GraphQLResponseResolver<Query_Now_Data, Query_Now_Variables>, options?: RequestHandlerOptions) {
+  return graphql.query<Query_Now_Data, Query_Now_Variables>("Now", resolver, options);
+}
+
+export function mockDeleteMutation(resolver: GraphQLResponseResolver<Mutation_Delete_Data, Mutation_Delete_Variables>, options?: RequestHandlerOptions) {
+  return graphql.mutation<Mutation_Delete_Data, Mutation_Delete_Variables>("Delete", resolver, options);
+}
+`, buf.String())
+}
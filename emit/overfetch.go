@@ -0,0 +1,30 @@
+package emit
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Emits, as JSON, the fields each operation selects that code never names.
+// See typer.OverFetching.
+type OverFetch struct {
+	// The schema the types were generated against.
+	Schema *ast.Schema
+	// The names in code, such as those extract.Names finds in the inputs.
+	Names map[string]bool
+}
+
+func (e *OverFetch) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	reports, err := typer.OverFetching(e.Schema, types, func(key string) bool {
+		return e.Names[key]
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
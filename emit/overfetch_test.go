@@ -0,0 +1,40 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestOverFetch(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema})
+	types := clientTestTypes(t, `query Now { now }`)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&OverFetch{Schema: schema}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, `[
+  {
+    "operation": "query",
+    "name": "Now",
+    "location": {
+      "file": "ops.ts",
+      "line": 1,
+      "column": 1
+    },
+    "unread": [
+      "now"
+    ]
+  }
+]
+`, buf.String())
+
+	buf.Reset()
+	if assert.NoError(t, (&OverFetch{Schema: schema, Names: map[string]bool{"now": true}}).Emit(&buf, types)) {
+		assert.Equal(t, "[]\n", buf.String())
+	}
+}
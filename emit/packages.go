@@ -0,0 +1,77 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits only the types of the documents in the input files that Include
+// accepts, such as those of one package of a monorepo. See SelectFiles.
+type Filtered struct {
+	Emitter Emitter
+	Include func(file string) bool
+}
+
+func (e *Filtered) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	return e.Emitter.Emit(w, SelectFiles(types, e.Include))
+}
+
+// Returns the types of the documents in the input files that include accepts,
+// along with the declarations they depend on, such as those of shared
+// fragments defined elsewhere, and only the scalars they reference.
+func SelectFiles(types typer.GeneratedTypes, include func(file string) bool) typer.GeneratedTypes {
+	var res typer.GeneratedTypes
+	decls := typer.NewDeclarationSet(types.Declarations)
+	selected := make(map[string]bool)
+	var selectDecl func(name string)
+	selectDecl = func(name string) {
+		decl, ok := decls.Get(name)
+		if !ok || selected[name] {
+			return
+		}
+		selected[name] = true
+		for _, dep := range decl.Dependencies {
+			selectDecl(dep)
+		}
+	}
+	var refs []typer.Type
+	for _, entry := range types.QueryMap {
+		if include(entry.Location.File) {
+			res.QueryMap = append(res.QueryMap, entry)
+			refs = append(refs, entry.Data, entry.Variables)
+		}
+	}
+	for _, failure := range types.Failures {
+		if include(failure.Location.File) {
+			res.Failures = append(res.Failures, failure)
+		}
+	}
+	for _, decl := range types.Declarations {
+		if include(decl.Source) {
+			selectDecl(decl.Name)
+		}
+	}
+	for _, ref := range refs {
+		visitNamedTypes(ref, selectDecl)
+	}
+	for _, decl := range types.Declarations {
+		if selected[decl.Name] {
+			res.Declarations = append(res.Declarations, decl)
+			refs = append(refs, decl.Type)
+		}
+	}
+
+	referenced := make(map[string]bool)
+	for _, ref := range refs {
+		visitNamedTypes(ref, func(name string) {
+			referenced[name] = true
+		})
+	}
+	for _, scalar := range types.Scalars {
+		if referenced[scalar] {
+			res.Scalars = append(res.Scalars, scalar)
+		}
+	}
+	return res
+}
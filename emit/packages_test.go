@@ -0,0 +1,58 @@
+package emit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestFiltered(t *testing.T) {
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{
+			Name:  "schema.gql",
+			Input: clientTestSchema + "\nscalar Instant\nscalar Money\nextend type Query { at: Instant! price: Money! }",
+		}),
+	}
+	inputs := []struct{ file, query string }{
+		{"shared/fragments.ts", `fragment UserName on User { name }`},
+		{"packages/admin/App.tsx", `query GetUser($id: ID!) { user(id: $id) { ...UserName } }`},
+		{"packages/admin/App.tsx", `{ at }`},
+		{"packages/shop/App.tsx", `query Price { price }`},
+	}
+	for _, in := range inputs {
+		tp.PrepareString(in.file, in.query)
+	}
+	for _, in := range inputs {
+		if _, _, err := tp.VisitString(in.file, in.query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	admin := func(file string) bool { return strings.HasPrefix(file, "packages/admin/") }
+	selected := SelectFiles(tp.GeneratedTypes, admin)
+	assert.Equal(t, []string{"Instant"}, selected.Scalars)
+	assert.Len(t, selected.QueryMap, 2)
+
+	var out bytes.Buffer
+	err := (&Filtered{Emitter: &TypeScript{ScalarsModule: "@admin/scalars"}, Include: admin}).Emit(&out, tp.GeneratedTypes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import type { Instant } from "@admin/scalars";
+
+export type Fragment_UserName_Data = { __typename: "User"; name: string; };
+export type Query_GetUser_Data = { __typename: "Query"; user: (({ __typename: "User"; } & Fragment_UserName_Data) | null); };
+export type Query_GetUser_Variables = { id: string; };
+
+export type QueryTypes = {
+  "query GetUser($id: ID!) { user(id: $id) { ...UserName } }": { data: Query_GetUser_Data; variables: Query_GetUser_Variables; };
+  "{ at }": { data: { __typename: "Query"; at: Instant; }; variables: { }; };
+}
+`, out.String())
+}
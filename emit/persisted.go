@@ -0,0 +1,118 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a module like FetchClient's that sends operations as automatic
+// persisted queries: first by hash alone, then, if the server has not seen the
+// hash, with the full document, which the server stores for next time. Hashes
+// are the trusted document IDs, so the documents sent are those of the
+// trusted documents allowlist and the Apollo manifest. See TrustedDocuments.
+type PersistedClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+const persistedClientRuntime = `// An operation as sent by a persisted query transport.
+export interface PersistedOperation {
+  operationName: string;
+  query: string;
+  // Hex SHA-256 of query.
+  sha256Hash: string;
+}
+
+// Sends an operation to a GraphQL server and resolves to its data.
+export type Transport = (operation: PersistedOperation, variables: Record<string, unknown>) => Promise<unknown>;
+
+let transport: Transport | undefined;
+
+// Sets the transport used by every operation function.
+export function setTransport(t: Transport): void {
+  transport = t;
+}
+
+type GraphQLResult = { data?: unknown; errors?: { message: string; extensions?: { code?: string } }[] };
+
+function hasError(result: GraphQLResult, code: string): boolean {
+  return result.errors?.some((error) => error.extensions?.code === code || error.message === code) ?? false;
+}
+
+// Returns a transport that POSTs operations to url with fetch, using the
+// automatic persisted query protocol. Documents are sent only when the server
+// does not know their hash, and always once the server reports that it does not
+// support persisted queries. Rejects on HTTP errors and GraphQL errors.
+export function persistedTransport(url: string, init: RequestInit = {}): Transport {
+  let supported = true;
+  const post = async (body: Record<string, unknown>): Promise<GraphQLResult> => {
+    const response = await fetch(url, {
+      ...init,
+      method: "POST",
+      headers: { "Content-Type": "application/json", ...(init.headers as Record<string, string> | undefined) },
+      body: JSON.stringify(body),
+    });
+    if (!response.ok && !response.headers.get("Content-Type")?.includes("json")) {
+      throw new Error(` + "`GraphQL request failed: ${response.status} ${response.statusText}`" + `);
+    }
+    return response.json();
+  };
+  return async ({ operationName, query, sha256Hash }, variables) => {
+    const extensions = { persistedQuery: { version: 1, sha256Hash } };
+    let result: GraphQLResult | undefined;
+    if (supported) {
+      result = await post({ operationName, variables, extensions });
+      if (hasError(result, "PERSISTED_QUERY_NOT_SUPPORTED") || hasError(result, "PersistedQueryNotSupported")) {
+        supported = false;
+        result = undefined;
+      } else if (hasError(result, "PERSISTED_QUERY_NOT_FOUND") || hasError(result, "PersistedQueryNotFound")) {
+        result = undefined;
+      }
+    }
+    if (result === undefined) {
+      result = await post({ operationName, query, variables, extensions: supported ? extensions : undefined });
+    }
+    if (result.errors?.length) {
+      throw new Error(result.errors.map((error) => error.message).join("\n"));
+    }
+    return result.data;
+  };
+}
+
+function send(operation: PersistedOperation, variables: Record<string, unknown>): Promise<unknown> {
+  if (transport === undefined) {
+    return Promise.reject(new Error("no GraphQL transport, call setTransport first"));
+  }
+  return transport(operation, variables);
+}
+`
+
+func (e *PersistedClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	shared, err := sharedFragments(types)
+	if err != nil {
+		return err
+	}
+	ops, imports := clientOperations(types, typer.OperationQuery, typer.OperationMutation)
+	ew := &errWriter{w: w}
+	writeClientHeader(ew, e.TypesModule, imports)
+	ew.printf("%s", persistedClientRuntime)
+	for _, op := range ops {
+		document, err := inlinedDocument(typer.QueryType{Name: op.Name, Query: op.Query, Sent: op.Sent}, shared)
+		if err != nil {
+			return err
+		}
+		ew.println()
+		ew.printf("const %sOperation: PersistedOperation = {\n", op.Func)
+		ew.printf("  operationName: %s,\n", typer.StringToJSON(op.Name))
+		ew.printf("  query: %s,\n", typer.StringToJSON(document))
+		ew.printf("  sha256Hash: %q,\n", documentID(document))
+		ew.println("};")
+		ew.println()
+		ew.printf("export async function %s(%s): Promise<%s> {\n", op.Func, variablesParam(op), op.Data)
+		ew.printf("  return (await send(%sOperation, variables)) as %s;\n", op.Func, op.Data)
+		ew.println("}")
+	}
+	return ew.err
+}
@@ -0,0 +1,100 @@
+package emit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Plugins are programs, written in any language, that produce additional
+// output files from the generation result. Much like protoc plugins, a plugin
+// is executed once per run, reads a PluginRequest as JSON from stdin, and
+// writes a PluginResponse as JSON to stdout. Anything written to stderr is
+// passed through.
+
+// The version of the plugin protocol. Incremented on incompatible changes.
+const PluginProtocolVersion = 1
+
+type PluginRequest struct {
+	Version int                  `json:"version"`
+	Types   typer.GeneratedTypes `json:"types"`
+}
+
+type PluginResponse struct {
+	Files []PluginFile `json:"files"`
+	// If set, the plugin failed.
+	Error string `json:"error,omitempty"`
+}
+
+type PluginFile struct {
+	// A relative, slash-separated path.
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+type Plugin struct {
+	// The program and its arguments.
+	Command []string
+	// Receives the plugin's stderr. May be nil.
+	Stderr io.Writer
+}
+
+// Parses a plugin command line, splitting arguments on whitespace.
+func ParsePlugin(command string) (*Plugin, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("empty plugin command")
+	}
+	return &Plugin{Command: args}, nil
+}
+
+// Runs the plugin, returning the files it produced. The plugin is killed if
+// ctx is done before it exits.
+func (p *Plugin) Run(ctx context.Context, types typer.GeneratedTypes) ([]PluginFile, error) {
+	req, err := json.Marshal(PluginRequest{
+		Version: PluginProtocolVersion,
+		Types:   types,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command[0], p.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = p.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running plugin %q: %w", p.Command[0], err)
+	}
+	var res PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return nil, fmt.Errorf("decoding response of plugin %q: %w", p.Command[0], err)
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("plugin %q: %s", p.Command[0], res.Error)
+	}
+	for _, file := range res.Files {
+		if !isLocalPath(file.Name) {
+			return nil, fmt.Errorf("plugin %q: invalid file name %q", p.Command[0], file.Name)
+		}
+	}
+	return res.Files, nil
+}
+
+// Reports whether a slash-separated path is relative and stays within its
+// base directory.
+func isLocalPath(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || filepath.IsAbs(name) {
+		return false
+	}
+	clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(name)))
+	return clean != ".." && !strings.HasPrefix(clean, "../")
+}
@@ -0,0 +1,66 @@
+package emit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Not a real test: acts as a plugin when run by TestPlugin.
+func TestPluginProcess(t *testing.T) {
+	if os.Getenv("EXTRACTGQLTS_TEST_PLUGIN") != "1" {
+		return
+	}
+	var req PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		os.Exit(2)
+	}
+	res := PluginResponse{
+		Files: []PluginFile{
+			{
+				Name:    "queries.txt",
+				Content: fmt.Sprintf("v%d %s\n", req.Version, req.Types.QueryMap[0].Query),
+			},
+		},
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(res); err != nil {
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+func TestPlugin(t *testing.T) {
+	t.Setenv("EXTRACTGQLTS_TEST_PLUGIN", "1")
+	plugin := &Plugin{
+		Command: []string{os.Args[0], "-test.run=^TestPluginProcess$"},
+	}
+	files, err := plugin.Run(context.Background(), typer.GeneratedTypes{
+		QueryMap: []typer.QueryType{
+			{Query: "{ hello }"},
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []PluginFile{
+			{Name: "queries.txt", Content: "v1 { hello }\n"},
+		}, files)
+	}
+}
+
+func TestIsLocalPath(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"a.ts":       true,
+		"dir/a.ts":   true,
+		"dir/../a":   true,
+		"":           false,
+		"/etc/a.ts":  false,
+		"../a.ts":    false,
+		"dir/../../": false,
+	} {
+		assert.Equal(t, expected, isLocalPath(name), "name: %q", name)
+	}
+}
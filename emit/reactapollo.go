@@ -0,0 +1,66 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits Apollo Client hooks per named operation, in the style of
+// graphql-code-generator's react-apollo plugin: a typed document, such as
+// GetUserDocument, with fragments inlined, and hooks with their generics
+// bound, such as useGetUserQuery(options), useGetUserLazyQuery(options),
+// useDeleteUserMutation(options), and useTicksSubscription(options).
+type ReactApolloClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+func (e *ReactApolloClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	shared, err := sharedFragments(types)
+	if err != nil {
+		return err
+	}
+	ops, imports := clientOperations(types, typer.OperationQuery, typer.OperationMutation, typer.OperationSubscription)
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	ew.println(`import { gql, useLazyQuery, useMutation, useQuery, useSubscription } from "@apollo/client";`)
+	ew.println(`import type { LazyQueryHookOptions, MutationHookOptions, QueryHookOptions, SubscriptionHookOptions, TypedDocumentNode } from "@apollo/client";`)
+	writeTypeImports(ew, e.TypesModule, imports)
+	for _, op := range ops {
+		document, err := inlinedDocument(typer.QueryType{Name: op.Name, Query: op.Query, Sent: op.Sent}, shared)
+		if err != nil {
+			return err
+		}
+		typeArgs := op.Data + ", " + op.Variables
+		// Options must carry variables unless there are none.
+		options := "options: %sHookOptions<" + typeArgs + "> & { variables: " + op.Variables + " }"
+		if op.NoVariables {
+			options = "options?: %sHookOptions<" + typeArgs + ">"
+		}
+		ew.println()
+		ew.printf("export const %sDocument: TypedDocumentNode<%s> = gql(%s);\n", op.Name, typeArgs, typer.StringToJSON(document))
+		ew.println()
+		switch op.Kind {
+		case typer.OperationQuery:
+			ew.printf("export function use%sQuery("+options+") {\n", op.Name, "Query")
+			ew.printf("  return useQuery<%s>(%sDocument, options);\n", typeArgs, op.Name)
+			ew.println("}")
+			ew.println()
+			ew.printf("export function use%sLazyQuery(options?: LazyQueryHookOptions<%s>) {\n", op.Name, typeArgs)
+			ew.printf("  return useLazyQuery<%s>(%sDocument, options);\n", typeArgs, op.Name)
+			ew.println("}")
+		case typer.OperationMutation:
+			ew.printf("export function use%sMutation(options?: MutationHookOptions<%s>) {\n", op.Name, typeArgs)
+			ew.printf("  return useMutation<%s>(%sDocument, options);\n", typeArgs, op.Name)
+			ew.println("}")
+		case typer.OperationSubscription:
+			ew.printf("export function use%sSubscription("+options+") {\n", op.Name, "Subscription")
+			ew.printf("  return useSubscription<%s>(%sDocument, options);\n", typeArgs, op.Name)
+			ew.println("}")
+		}
+	}
+	return ew.err
+}
@@ -0,0 +1,47 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a TanStack Query hook per named query and mutation, such as
+// useGetUserQuery(variables, options) and useDeleteUserMutation(options).
+// Requests are sent as with FetchClient.
+type ReactQueryClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+func (e *ReactQueryClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, imports := clientOperations(types, typer.OperationQuery, typer.OperationMutation)
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	ew.println(`import { useMutation, useQuery } from "@tanstack/react-query";`)
+	ew.println(`import type { UseMutationOptions, UseQueryOptions } from "@tanstack/react-query";`)
+	writeTypeImports(ew, e.TypesModule, imports)
+	ew.println()
+	ew.printf("%s", fetchClientRuntime)
+	for _, op := range ops {
+		name := typer.StringToJSON(op.Name)
+		ew.println()
+		switch op.Kind {
+		case typer.OperationQuery:
+			ew.printf("export function use%sQuery(%s, options?: Omit<UseQueryOptions<%s>, \"queryKey\" | \"queryFn\">) {\n", op.Name, variablesParam(op), op.Data)
+			ew.printf("  return useQuery<%s>({\n", op.Data)
+			ew.printf("    queryKey: [%s, variables],\n", name)
+			ew.printf("    queryFn: () => send(%s) as Promise<%s>,\n", sendArgs(op), op.Data)
+		case typer.OperationMutation:
+			ew.printf("export function use%sMutation(options?: Omit<UseMutationOptions<%s, Error, %s>, \"mutationFn\">) {\n", op.Name, op.Data, op.Variables)
+			ew.printf("  return useMutation<%s, Error, %s>({\n", op.Data, op.Variables)
+			ew.printf("    mutationFn: (variables: %s) => send(%s) as Promise<%s>,\n", op.Variables, sendArgs(op), op.Data)
+		}
+		ew.println("    ...options,")
+		ew.println("  });")
+		ew.println("}")
+	}
+	return ew.err
+}
@@ -0,0 +1,109 @@
+package emit
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a TypeScript module that maps the source text of each operation, as
+// in QueryTypes, to its name, trusted document ID, and location, so that
+// transports may attach operation IDs to requests and logs, correlating
+// traces of the client with those of the gateway, and send each operation as
+// its Hints direct.
+type OperationRegistry struct {
+	// Group operations by kind, each group sorted by name, rather than in
+	// order of appearance.
+	Grouped bool
+	// The scheme of the IDs, as of trusted documents.
+	IDs DocumentIDs
+	// Rules attaching hints to operations, in order of increasing precedence.
+	Hints []HintRule
+}
+
+const operationRegistryRuntime = `export interface OperationMetadata {
+  // Null for anonymous operations.
+  name: string | null;
+  kind: "query" | "mutation" | "subscription";
+  // ID of the operation with every fragment it spreads inlined, as in trusted
+  // documents. By default, its hex SHA-256.
+  id: string;
+  // Input file and 1-based line of the operation.
+  file: string;
+  line: number;
+  // Tags of the operation, such as its feature area or owner, if tagged by
+  // the conventions of --tag.
+  tags?: Record<string, string>;
+  // Transport hints of the operation, if any hint rule matches it.
+  hints?: OperationHints;
+}
+
+// Returns the metadata of an operation by its source text, if generated.
+export function operationMetadata(query: string): OperationMetadata | undefined {
+  return Object.prototype.hasOwnProperty.call(operations, query) ? operations[query] : undefined;
+}
+`
+
+func (e *OperationRegistry) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	if err := e.IDs.Validate(); err != nil {
+		return err
+	}
+	for _, rule := range e.Hints {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	shared, err := sharedFragments(types)
+	if err != nil {
+		return err
+	}
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	ew.printf("%s", operationRegistryRuntime)
+	ew.println()
+	writeOperationHints(ew, e.Hints)
+	ew.println()
+	ew.println("export const operations: Record<string, OperationMetadata> = {")
+	var entries []typer.QueryType
+	// Trusted document IDs, by query.
+	ids := make(map[string]string)
+	for _, entry := range types.QueryMap {
+		if _, ok := ids[entry.Query]; ok || entry.Operation == typer.OperationFragment {
+			continue
+		}
+		document, err := inlinedDocument(entry, shared)
+		if err != nil {
+			return err
+		}
+		if document != "" {
+			entries = append(entries, entry)
+			ids[entry.Query] = e.IDs.ID(document)
+		}
+	}
+	writeQueryEntries(ew, "  ", entries, e.Grouped, func(entry typer.QueryType) {
+		name := "null"
+		if entry.Name != "" {
+			name = typer.StringToJSON(entry.Name)
+		}
+		var extra string
+		if len(entry.Tags) > 0 {
+			var fields []string
+			for name, value := range entry.Tags {
+				fields = append(fields, typer.StringToJSON(name)+": "+typer.StringToJSON(value))
+			}
+			sort.Strings(fields)
+			extra = ", tags: { " + strings.Join(fields, ", ") + " }"
+		}
+		if hints := operationHints(e.Hints, entry); hints != nil {
+			extra += ", hints: " + hintsObject(hints)
+		}
+		ew.printf("  %s: { name: %s, kind: %s, id: %s, file: %s, line: %d%s },\n",
+			typer.StringToJSON(entry.Query), name, typer.StringToJSON(string(entry.Operation)),
+			typer.StringToJSON(ids[entry.Query]), typer.StringToJSON(entry.Location.File), entry.Location.Line, extra)
+	})
+	ew.println("};")
+	return ew.err
+}
@@ -0,0 +1,29 @@
+package emit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationRegistry(t *testing.T) {
+	types := clientTestTypes(t,
+		`fragment UserName on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { ...UserName } }`,
+		`{ now }`,
+		`{ now }`,
+	)
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&OperationRegistry{}).Emit(&buf, types)) {
+		return
+	}
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "// GENERATED FILE. DO NOT EDIT.\n\nexport interface OperationMetadata {\n"))
+	assert.True(t, strings.HasSuffix(out, `export const operations: Record<string, OperationMetadata> = {
+  "query GetUser($id: ID!) { user(id: $id) { ...UserName } }": { name: "GetUser", kind: "query", id: "69bb213ccdbb6b7cc9d3e4c3e55ddaa6b37c7b7b8d05fa86729454c6e59204af", file: "ops.ts", line: 1 },
+  "{ now }": { name: null, kind: "query", id: "57aa5b858cc87b4a537c9adf5730273fd3281e884e4faf7da3013c1b93921402", file: "ops.ts", line: 1 },
+};
+`), out)
+}
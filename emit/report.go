@@ -0,0 +1,68 @@
+package emit
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Emits a report of each operation's depth, field count, referenced types,
+// deprecated usage, and location, for API owners auditing what clients query.
+// See typer.Report.
+type Report struct {
+	// The schema the types were generated against.
+	Schema *ast.Schema
+	// Emit an HTML page instead of JSON.
+	HTML bool
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>GraphQL operations</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.deprecated { color: #b00; }
+</style>
+</head>
+<body>
+<h1>GraphQL operations</h1>
+<table>
+<tr><th>Operation</th><th>Location</th><th>Depth</th><th>Fields</th><th>Types</th><th>Deprecated</th><th>Tags</th></tr>
+{{- range .}}
+<tr>
+<td>{{.Operation}} {{if .Name}}{{.Name}}{{else}}<i>anonymous</i>{{end}}</td>
+<td>{{.Location.File}}:{{.Location.Line}}:{{.Location.Column}}</td>
+<td>{{.Depth}}</td>
+<td>{{.FieldCount}}</td>
+<td>{{range $i, $t := .Types}}{{if $i}}, {{end}}{{$t}}{{end}}</td>
+<td class="deprecated">{{range $i, $d := .Deprecated}}{{if $i}}, {{end}}{{$d}}{{end}}</td>
+<td>{{range $k, $v := .Tags}}{{$k}}: {{$v}}<br>{{end}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+func (e *Report) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	reports, err := typer.Report(e.Schema, types)
+	if err != nil {
+		return err
+	}
+	if reports == nil {
+		reports = []typer.OperationReport{}
+	}
+	if e.HTML {
+		return reportTemplate.Execute(w, reports)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
@@ -0,0 +1,54 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestReport(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema})
+	types := clientTestTypes(t, `query GetUser($id: ID!) { user(id: $id) { name } }`)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Report{Schema: schema}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, `[
+  {
+    "operation": "query",
+    "name": "GetUser",
+    "location": {
+      "file": "ops.ts",
+      "line": 1,
+      "column": 1
+    },
+    "depth": 2,
+    "fieldCount": 2,
+    "types": [
+      "Query",
+      "User"
+    ],
+    "deprecated": []
+  }
+]
+`, buf.String())
+
+	buf.Reset()
+	if !assert.NoError(t, (&Report{Schema: schema, HTML: true}).Emit(&buf, types)) {
+		return
+	}
+	assert.Contains(t, buf.String(), `<tr>
+<td>query GetUser</td>
+<td>ops.ts:1:1</td>
+<td>2</td>
+<td>2</td>
+<td>Query, User</td>
+<td class="deprecated"></td>
+<td></td>
+</tr>
+</table>`)
+}
@@ -0,0 +1,68 @@
+package emit
+
+import (
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Emits a TypeScript module of lookups derived from the schema, rather than
+// from operations: TypenameToType, mapping the name of each object type to
+// the type of its objects; Typename; and PossibleTypes, mapping each
+// interface and union to its possible typenames, also as a value in the
+// format of Apollo Client's possibleTypes option. See typer.SchemaMaps.
+type SchemaMaps struct {
+	Schema *ast.Schema
+	// Translates leaf types, as for the operations.
+	Options typer.Options
+	// Module from which custom scalars are imported. Defaults to "./scalars".
+	ScalarsModule string
+}
+
+func (e *SchemaMaps) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	maps := typer.NewSchemaMaps(e.Schema, e.Options)
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	if len(maps.Scalars) > 0 {
+		writeScalarImports(ew, e.ScalarsModule, maps.Scalars)
+		ew.println()
+	}
+
+	ew.println("export type TypenameToType = {")
+	for _, object := range maps.Objects {
+		ew.printf("  %s: %s;\n", object.Name, typer.RenderType(object.Type))
+	}
+	ew.println("};")
+	ew.println()
+	ew.println("export type Typename = keyof TypenameToType;")
+	ew.println()
+
+	ew.println("export type PossibleTypes = {")
+	for _, possible := range maps.PossibleTypes {
+		typenames := "never"
+		if len(possible.Typenames) > 0 {
+			typenames = strings.Join(quoteTypenames(possible.Typenames), " | ")
+		}
+		ew.printf("  %s: %s;\n", possible.Name, typenames)
+	}
+	ew.println("};")
+	ew.println()
+
+	ew.println("export const possibleTypes: { [T in keyof PossibleTypes]: PossibleTypes[T][] } = {")
+	for _, possible := range maps.PossibleTypes {
+		ew.printf("  %s: [%s],\n", possible.Name, strings.Join(quoteTypenames(possible.Typenames), ", "))
+	}
+	ew.println("};")
+	return ew.err
+}
+
+func quoteTypenames(typenames []string) []string {
+	quoted := make([]string, len(typenames))
+	for i, typename := range typenames {
+		quoted[i] = typer.StringToJSON(typename)
+	}
+	return quoted
+}
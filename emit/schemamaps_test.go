@@ -0,0 +1,79 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSchemaMaps(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			scalar DateTime
+
+			enum Species { CAT DOG }
+
+			interface Node { id: ID! }
+
+			interface Named { name: String }
+
+			type User implements Node & Named {
+				id: ID!
+				name: String
+				pets(first: Int!): [Pet!]!
+				joined: DateTime!
+			}
+
+			type Pet implements Node & Named {
+				id: ID!
+				name: String
+				species: Species
+				owner: User
+			}
+
+			union SearchResult = User | Pet
+
+			type Query {
+				node(id: ID!): Node
+				search(text: String!): [SearchResult!]!
+			}
+		`,
+	})
+	var buf bytes.Buffer
+	emitter := &SchemaMaps{
+		Schema:  schema,
+		Options: typer.Options{Scalars: map[string]string{"DateTime": "string"}},
+	}
+	if !assert.NoError(t, emitter.Emit(&buf, typer.GeneratedTypes{})) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import type { Species } from "./scalars";
+
+export type TypenameToType = {
+  Pet: { __typename: "Pet"; id: string; name: (string | null); species: (Species | null); owner: (TypenameToType["User"] | null); };
+  Query: { __typename: "Query"; node: (TypenameToType[PossibleTypes["Node"]] | null); search: TypenameToType[PossibleTypes["SearchResult"]][]; };
+  User: { __typename: "User"; id: string; name: (string | null); pets: TypenameToType["Pet"][]; joined: string; };
+};
+
+export type Typename = keyof TypenameToType;
+
+export type PossibleTypes = {
+  Named: "Pet" | "User";
+  Node: "Pet" | "User";
+  SearchResult: "Pet" | "User";
+};
+
+export const possibleTypes: { [T in keyof PossibleTypes]: PossibleTypes[T][] } = {
+  Named: ["Pet", "User"],
+  Node: ["Pet", "User"],
+  SearchResult: ["Pet", "User"],
+};
+`, buf.String())
+}
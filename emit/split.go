@@ -0,0 +1,234 @@
+package emit
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits the TypeScript types as a directory rather than a single module, so
+// that the directory can be its own TypeScript project reference and be type
+// checked separately from the application that uses it.
+//
+// Each input file's declarations are in a module of their own, which imports
+// what it references from the others. The index module re-exports every
+// declaration and exports the QueryTypes map. A tsconfig.json makes the
+// directory a composite project, which must also contain, or reference, the
+// scalars module.
+type SplitTypeScript struct {
+	// Module from which custom scalars are imported, relative to the directory.
+	// Defaults to "./scalars".
+	ScalarsModule string
+
+	// Wrap variables types in Exact<>, as graphql-code-generator does.
+	ExactVariables bool
+
+	// Give imports between the generated modules explicit .ts extensions. See
+	// ESMSpecifier.
+	ESM bool
+
+	// Group the entries of QueryTypes by kind. See TypeScript.
+	GroupQueryTypes bool
+}
+
+// A file of a generated directory.
+type OutputFile struct {
+	// Path relative to the directory, such as index.ts.
+	Name    string
+	Content []byte
+}
+
+const splitTSConfig = `{
+  "compilerOptions": {
+    "composite": true,
+    "declaration": true,
+    "emitDeclarationOnly": true,
+    "isolatedModules": true,
+    "outDir": ".tsbuild",
+    "skipLibCheck": true,
+    "strict": true
+  },
+  "include": ["*.ts"]
+}
+`
+
+// Returns the files of the directory, the index module and tsconfig.json
+// last.
+func (e *SplitTypeScript) Files(types typer.GeneratedTypes) ([]OutputFile, error) {
+	scalars := make(map[string]bool)
+	for _, scalar := range types.Scalars {
+		scalars[scalar] = true
+	}
+	decls := typer.NewDeclarationSet(types.Declarations)
+	bySource := decls.BySource()
+	sources := make([]string, 0, len(bySource))
+	for source := range bySource {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	// Module of each declaration, by name.
+	modules := make(map[string]string)
+	taken := map[string]bool{"index": true, "scalars": true}
+	sourceModules := make([]string, len(sources))
+	for i, source := range sources {
+		module := splitModuleName(source)
+		for n := 2; taken[module]; n++ {
+			module = fmt.Sprintf("%s_%d", splitModuleName(source), n)
+		}
+		taken[module] = true
+		sourceModules[i] = module
+		for _, decl := range bySource[source] {
+			modules[decl.Name] = module
+		}
+	}
+
+	var files []OutputFile
+	for i, source := range sources {
+		module := sourceModules[i]
+		var refs []typer.Type
+		for _, decl := range bySource[source] {
+			refs = append(refs, decl.Type)
+		}
+		var buf bytes.Buffer
+		ew := &errWriter{w: &buf}
+		ew.println("// GENERATED FILE. DO NOT EDIT.")
+		ew.println()
+		e.writeImports(ew, module, refs, modules, scalars)
+		exact := false
+		for _, decl := range bySource[source] {
+			exact = exact || e.ExactVariables && decl.Kind == typer.DeclarationVariables
+		}
+		if exact {
+			ew.println(strings.TrimPrefix(exactDeclaration, "export "))
+		}
+		for _, decl := range bySource[source] {
+			if e.ExactVariables && decl.Kind == typer.DeclarationVariables {
+				ew.printf("export type %s = Exact<%s>;\n", decl.Name, typer.RenderType(decl.Type))
+				continue
+			}
+			ew.println(decl)
+		}
+		if ew.err != nil {
+			return nil, ew.err
+		}
+		files = append(files, OutputFile{Name: module + ".ts", Content: buf.Bytes()})
+	}
+
+	var buf bytes.Buffer
+	ew := &errWriter{w: &buf}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	var refs []typer.Type
+	for _, entry := range types.QueryMap {
+		refs = append(refs, entry.Data, entry.Variables)
+	}
+	e.writeImports(ew, "index", refs, modules, scalars)
+	if len(sourceModules) > 0 {
+		for _, module := range sourceModules {
+			ew.printf("export * from %s;\n", typer.StringToJSON(e.specifier(module)))
+		}
+		ew.println()
+	}
+	writeQueryTypes(ew, types, e.GroupQueryTypes, typer.RenderType)
+	if ew.err != nil {
+		return nil, ew.err
+	}
+	files = append(files,
+		OutputFile{Name: "index.ts", Content: buf.Bytes()},
+		OutputFile{Name: "tsconfig.json", Content: []byte(splitTSConfig)},
+	)
+	return files, nil
+}
+
+// Writes type imports of the scalars and the declarations of other modules
+// that types reference, followed by a blank line if there are any.
+func (e *SplitTypeScript) writeImports(ew *errWriter, module string, types []typer.Type, modules map[string]string, scalars map[string]bool) {
+	var usedScalars []string
+	imports := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, typ := range types {
+		visitNamedTypes(typ, func(name string) {
+			if seen[name] {
+				return
+			}
+			seen[name] = true
+			if scalars[name] {
+				usedScalars = append(usedScalars, name)
+			} else if other, ok := modules[name]; ok && other != module {
+				imports[other] = append(imports[other], name)
+			}
+		})
+	}
+	if len(usedScalars) == 0 && len(imports) == 0 {
+		return
+	}
+	if len(usedScalars) > 0 {
+		sort.Strings(usedScalars)
+		writeScalarImports(ew, e.ScalarsModule, usedScalars)
+	}
+	others := make([]string, 0, len(imports))
+	for other := range imports {
+		others = append(others, other)
+	}
+	sort.Strings(others)
+	for _, other := range others {
+		names := imports[other]
+		sort.Strings(names)
+		ew.printf("import type { %s } from %s;\n", strings.Join(names, ", "), typer.StringToJSON(e.specifier(other)))
+	}
+	ew.println()
+}
+
+func (e *SplitTypeScript) specifier(module string) string {
+	specifier := "./" + module
+	if e.ESM {
+		specifier = ESMSpecifier(specifier)
+	}
+	return specifier
+}
+
+// Returns a module name for the declarations of an input file, such as
+// src_App_tsx for src/App.tsx.
+func splitModuleName(source string) string {
+	var b strings.Builder
+	for _, r := range source {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+	if name == "" {
+		return "documents"
+	}
+	return name
+}
+
+// Calls fn with the name of each named type within typ.
+func visitNamedTypes(typ typer.Type, fn func(name string)) {
+	switch typ := typ.(type) {
+	case typer.NamedType:
+		fn(typ.Name)
+	case typer.ObjectType:
+		for _, field := range typ.Fields {
+			visitNamedTypes(field.Type, fn)
+		}
+	case typer.ArrayType:
+		visitNamedTypes(typ.Elem, fn)
+	case typer.NullableType:
+		visitNamedTypes(typ.Type, fn)
+	case typer.UnionType:
+		for _, member := range typ.Members {
+			visitNamedTypes(member, fn)
+		}
+	case typer.IntersectionType:
+		for _, member := range typ.Members {
+			visitNamedTypes(member, fn)
+		}
+	}
+}
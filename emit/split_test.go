@@ -0,0 +1,67 @@
+package emit
+
+import (
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSplitTypeScript(t *testing.T) {
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{
+			Name:  "schema.gql",
+			Input: clientTestSchema + "\nscalar Instant\nextend type Query { at: Instant! }",
+		}),
+	}
+	inputs := []struct{ file, query string }{
+		{"src/fragments.ts", `fragment UserName on User { name }`},
+		{"src/App.tsx", `query GetUser($id: ID!) { user(id: $id) { ...UserName } }`},
+		{"src/App.tsx", `{ at }`},
+	}
+	for _, in := range inputs {
+		tp.PrepareString(in.file, in.query)
+	}
+	for _, in := range inputs {
+		if _, _, err := tp.VisitString(in.file, in.query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	emitter := &SplitTypeScript{ESM: true}
+	files, err := emitter.Files(tp.GeneratedTypes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	contents := make(map[string]string)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+		contents[file.Name] = string(file.Content)
+	}
+	assert.Equal(t, []string{"src_App_tsx.ts", "src_fragments_ts.ts", "index.ts", "tsconfig.json"}, names)
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import type { Fragment_UserName_Data } from "./src_fragments_ts.ts";
+
+export type Query_GetUser_Data = { __typename: "Query"; user: (({ __typename: "User"; } & Fragment_UserName_Data) | null); };
+export type Query_GetUser_Variables = { id: string; };
+`, contents["src_App_tsx.ts"])
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import type { Instant } from "./scalars";
+import type { Query_GetUser_Data, Query_GetUser_Variables } from "./src_App_tsx.ts";
+import type { Fragment_UserName_Data, Fragment_UserName_Variables } from "./src_fragments_ts.ts";
+
+export * from "./src_App_tsx.ts";
+export * from "./src_fragments_ts.ts";
+
+export type QueryTypes = {
+  "fragment UserName on User { name }": { data: Fragment_UserName_Data; variables: Fragment_UserName_Variables; };
+  "query GetUser($id: ID!) { user(id: $id) { ...UserName } }": { data: Query_GetUser_Data; variables: Query_GetUser_Variables; };
+  "{ at }": { data: { __typename: "Query"; at: Instant; }; variables: { }; };
+}
+`, contents["index.ts"])
+	assert.Contains(t, contents["tsconfig.json"], `"composite": true`)
+}
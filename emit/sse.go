@@ -0,0 +1,89 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits helpers per named subscription and live query for servers streaming
+// results as server-sent events, as GraphQL Yoga and graphql-sse do: a
+// callback based subscribe_Name(url, variables, handlers), which returns a
+// function that unsubscribes. Requests are GETs with an EventSource, so
+// cookies are the only credentials sent.
+//
+// Also emits transports, mapping the name of every named operation to "sse"
+// if its results are streamed, or else "http", for applications that pick a
+// transport per operation at runtime.
+type SSEClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+const sseClientRuntime = `export type Handlers<Data> = {
+  next: (data: Data) => void;
+  error?: (error: unknown) => void;
+  complete?: () => void;
+};
+
+type ExecutionResult = { data?: unknown; errors?: { message: string }[] };
+
+function subscribe<Data>(url: string, operationName: string, query: string, variables: Record<string, unknown>, handlers: Handlers<Data>): () => void {
+  const params = new URLSearchParams({ query, operationName, variables: JSON.stringify(variables) });
+  const source = new EventSource(` + "`${url}${url.includes(\"?\") ? \"&\" : \"?\"}${params}`" + `, { withCredentials: true });
+  const onNext = (event: MessageEvent) => {
+    const result: ExecutionResult = JSON.parse(event.data);
+    if (result.errors?.length) {
+      handlers.error?.(new Error(result.errors.map((error) => error.message).join("\n")));
+      return;
+    }
+    handlers.next(result.data as Data);
+  };
+  // graphql-sse names events; others send unnamed messages.
+  source.addEventListener("next", onNext);
+  source.addEventListener("message", onNext);
+  source.addEventListener("complete", () => {
+    source.close();
+    handlers.complete?.();
+  });
+  source.onerror = (error) => {
+    if (source.readyState === EventSource.CLOSED) {
+      handlers.error?.(error);
+    }
+  };
+  return () => source.close();
+}
+`
+
+func (e *SSEClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, _ := clientOperations(types, typer.OperationQuery, typer.OperationMutation, typer.OperationSubscription)
+	var streamed []operation
+	var imports []string
+	for _, op := range ops {
+		if op.Streamed {
+			streamed = append(streamed, op)
+			imports = append(imports, op.Data, op.Variables)
+		}
+	}
+	ew := &errWriter{w: w}
+	writeClientHeader(ew, e.TypesModule, imports)
+	ew.println("export const transports = {")
+	for _, op := range ops {
+		transport := "http"
+		if op.Streamed {
+			transport = "sse"
+		}
+		ew.printf("  %s: %q,\n", op.Name, transport)
+	}
+	ew.println("} as const;")
+	ew.println()
+	ew.printf("%s", sseClientRuntime)
+	for _, op := range streamed {
+		ew.println()
+		ew.printf("export function subscribe_%s(url: string, %s, handlers: Handlers<%s>): () => void {\n", op.Name, variablesParam(op), op.Data)
+		ew.printf("  return subscribe(url, %s, %s, variables, handlers);\n", typer.StringToJSON(op.Name), typer.StringToJSON(op.Query))
+		ew.println("}")
+	}
+	return ew.err
+}
@@ -0,0 +1,68 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a helper per named query and mutation for SvelteKit load functions
+// and form actions, such as load_GetUser(fetch, variables). Helpers take the
+// fetch of the load event, so they work in both server and universal load
+// functions, and resolve to { data, variables }.
+type SvelteKitClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+const svelteKitRuntime = `// The fetch of a load event, or the global fetch.
+type Fetch = typeof fetch;
+
+export type Result<Data, Variables> = { data: Data; variables: Variables };
+
+let endpoint = "/graphql";
+
+// Sets the URL of the GraphQL server. Defaults to "/graphql".
+export function setEndpoint(url: string): void {
+  endpoint = url;
+}
+
+async function request<Data, Variables>(fetch: Fetch, query: string, variables: Variables): Promise<Result<Data, Variables>> {
+  const response = await fetch(endpoint, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ query, variables }),
+  });
+  if (!response.ok) {
+    throw error(response.status, ` + "`GraphQL request failed: ${response.statusText}`" + `);
+  }
+  const result = await response.json();
+  if (result.errors?.length) {
+    throw error(502, result.errors.map((e: { message: string }) => e.message).join("\n"));
+  }
+  return { data: result.data as Data, variables };
+}
+`
+
+func (e *SvelteKitClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, imports := clientOperations(types, typer.OperationQuery, typer.OperationMutation)
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	ew.println(`import { error } from "@sveltejs/kit";`)
+	writeTypeImports(ew, e.TypesModule, imports)
+	ew.println()
+	ew.printf("%s", svelteKitRuntime)
+	for _, op := range ops {
+		prefix := "load"
+		if op.Kind == typer.OperationMutation {
+			prefix = "mutate"
+		}
+		ew.println()
+		ew.printf("export function %s_%s(fetch: Fetch, %s): Promise<Result<%s, %s>> {\n", prefix, op.Name, variablesParam(op), op.Data, op.Variables)
+		ew.printf("  return request(fetch, %s, variables);\n", typer.StringToJSON(op.Query))
+		ew.println("}")
+	}
+	return ew.err
+}
@@ -0,0 +1,51 @@
+package emit
+
+import (
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a typed @urql/svelte store factory per named operation, such as
+// queryStore_GetUser(client, variables), so that components need not supply
+// type arguments at every call site.
+type SvelteURQLClient struct {
+	// Module from which generated types are imported. Defaults to
+	// "./types.generated".
+	TypesModule string
+}
+
+func (e *SvelteURQLClient) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	ops, imports := clientOperations(types, typer.OperationQuery, typer.OperationMutation, typer.OperationSubscription)
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	var stores []string
+	for _, kind := range []typer.OperationKind{typer.OperationQuery, typer.OperationMutation, typer.OperationSubscription} {
+		for _, op := range ops {
+			if op.Kind == kind {
+				stores = append(stores, string(kind)+"Store")
+				break
+			}
+		}
+	}
+	if len(stores) > 0 {
+		ew.printf("import { %s } from \"@urql/svelte\";\n", strings.Join(stores, ", "))
+	}
+	ew.println(`import type { Client } from "@urql/svelte";`)
+	writeTypeImports(ew, e.TypesModule, imports)
+	for _, op := range ops {
+		store := string(op.Kind) + "Store"
+		typeArgs := op.Data + ", " + op.Variables
+		if op.Kind == typer.OperationSubscription {
+			// The result type, accumulated by an optional handler, is the data.
+			typeArgs = op.Data + ", " + typeArgs
+		}
+		ew.println()
+		ew.printf("export function %s_%s(client: Client, %s) {\n", store, op.Name, variablesParam(op))
+		ew.printf("  return %s<%s>({ client, query: %s, variables });\n", store, typeArgs, typer.StringToJSON(op.Query))
+		ew.println("}")
+	}
+	return ew.err
+}
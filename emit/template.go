@@ -0,0 +1,66 @@
+package emit
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits the output of a user-supplied text/template, executed with a
+// TemplateData value. Lets teams produce bespoke artifacts without patching
+// the tool.
+type Template struct {
+	Template *template.Template
+}
+
+// The value a Template is executed with.
+type TemplateData struct {
+	// Custom scalars, without duplicates, in order of first use.
+	Scalars []string
+	// Declarations without duplicates, with dependencies first.
+	Declarations []typer.Declaration
+	QueryMap     []typer.QueryType
+	// The unprocessed generated types.
+	Types typer.GeneratedTypes
+}
+
+// Functions available to templates, in addition to the text/template
+// builtins.
+var TemplateFuncs = template.FuncMap{
+	// Quotes a string as a JSON, and so also TypeScript, string literal.
+	"json": typer.StringToJSON,
+	// Renders a typer.Type as TypeScript.
+	"render": typer.RenderType,
+	"join":   strings.Join,
+}
+
+// Parses the template file at path, with TemplateFuncs available.
+func ParseTemplateFile(path string) (*Template, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplate(filepath.Base(path), string(bs))
+}
+
+// Parses template text, with TemplateFuncs available.
+func ParseTemplate(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{Template: tmpl}, nil
+}
+
+func (e *Template) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	return e.Template.Execute(w, TemplateData{
+		Scalars:      uniqueScalars(types),
+		Declarations: typer.NewDeclarationSet(types.Declarations).Ordered(),
+		QueryMap:     types.QueryMap,
+		Types:        types,
+	})
+}
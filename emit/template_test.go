@@ -0,0 +1,47 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestTemplate(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				now: Instant!
+			}
+
+			scalar Instant
+		`,
+	})
+	tp := &typer.Typer{
+		Schema: schema,
+	}
+	if _, _, err := tp.VisitString("", `query Clock { now }`); !assert.NoError(t, err) {
+		return
+	}
+
+	emitter, err := ParseTemplate("test", `scalars: {{ join .Scalars ", " }}
+{{ range .Declarations }}{{ .Name }} = {{ render .Type }}
+{{ end }}{{ range .QueryMap }}{{ json .Query }}: {{ .Render }}
+{{ end }}`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	if !assert.NoError(t, emitter.Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Equal(t, `scalars: Instant
+Query_Clock_Data = { __typename: "Query"; now: Instant; }
+Query_Clock_Variables = { }
+"query Clock { now }": { data: Query_Clock_Data; variables: Query_Clock_Variables; }
+`, buf.String())
+}
@@ -0,0 +1,254 @@
+package emit
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// An operation as a server that accepts only trusted documents receives it:
+// normalized, with every fragment it spreads, directly or not, inlined.
+type TrustedDocument struct {
+	// Document's ID by the DocumentIDs scheme, by default its hex SHA-256.
+	ID string
+	// Empty for anonymous operations.
+	Name      string
+	Operation typer.OperationKind
+	Document  string
+	// A file name for the document, unique among those returned together, such
+	// as GetUser.graphql.
+	Filename string
+}
+
+// Returns the trusted document of each operation, in order of appearance and
+// without duplicates, identified by ids. Fragments are found in the documents
+// of the operations that spread them, or else among the fragment documents of
+// types.
+func TrustedDocuments(types typer.GeneratedTypes, ids DocumentIDs) ([]TrustedDocument, error) {
+	if err := ids.Validate(); err != nil {
+		return nil, err
+	}
+	shared, err := sharedFragments(types)
+	if err != nil {
+		return nil, err
+	}
+	var docs []TrustedDocument
+	seen := make(map[string]bool)
+	filenames := make(map[string]bool)
+	for _, entry := range types.QueryMap {
+		if entry.Operation == typer.OperationFragment {
+			continue
+		}
+		document, err := inlinedDocument(entry, shared)
+		if err != nil {
+			return nil, err
+		}
+		if document == "" {
+			continue
+		}
+		id := ids.ID(document)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		// Prefixes may not be valid in file names.
+		filename := entry.Name
+		if filename == "" || filenames[filename] {
+			filename = ids.digest(document)
+		}
+		filenames[filename] = true
+		docs = append(docs, TrustedDocument{
+			ID:        id,
+			Name:      entry.Name,
+			Operation: entry.Operation,
+			Document:  document,
+			Filename:  filename + ".graphql",
+		})
+	}
+	return docs, nil
+}
+
+// Hash functions by which documents may be identified, by name.
+var DocumentHashes = []string{"sha256", "md5", "sha1"}
+
+// The scheme by which trusted documents are identified, to match whichever
+// persisted query server stores them. The zero value is the hex SHA-256 of
+// the document, as Apollo and GraphQL Yoga expect.
+type DocumentIDs struct {
+	// One of DocumentHashes, such as md5 for Relay's persisted queries.
+	// Defaults to sha256.
+	Hash string
+	// Prepended to each hex digest, such as sha256: for servers that expect
+	// the algorithm to be named.
+	Prefix string
+}
+
+// Returns an error if the hash is unknown.
+func (ids DocumentIDs) Validate() error {
+	switch ids.Hash {
+	case "", "sha256", "md5", "sha1":
+		return nil
+	}
+	return fmt.Errorf("unknown document hash %q, expected one of: %s", ids.Hash, strings.Join(DocumentHashes, ", "))
+}
+
+// Returns the ID of document. The hash must be valid.
+func (ids DocumentIDs) ID(document string) string {
+	return ids.Prefix + ids.digest(document)
+}
+
+// Returns the hex digest of document, without the prefix.
+func (ids DocumentIDs) digest(document string) string {
+	var sum []byte
+	switch ids.Hash {
+	case "md5":
+		digest := md5.Sum([]byte(document))
+		sum = digest[:]
+	case "sha1":
+		digest := sha1.Sum([]byte(document))
+		sum = digest[:]
+	default:
+		digest := sha256.Sum256([]byte(document))
+		sum = digest[:]
+	}
+	return hex.EncodeToString(sum)
+}
+
+// Returns the hex SHA-256 of document, as the hash of automatic persisted
+// queries, which is the same whatever the DocumentIDs.
+func documentID(document string) string {
+	return DocumentIDs{}.ID(document)
+}
+
+// Returns the fragment definitions of the fragment documents in types, by
+// name. Where names collide, the first definition wins.
+func sharedFragments(types typer.GeneratedTypes) (map[string]*ast.FragmentDefinition, error) {
+	shared := make(map[string]*ast.FragmentDefinition)
+	for _, entry := range types.QueryMap {
+		if entry.Operation != typer.OperationFragment {
+			continue
+		}
+		doc, err := parser.ParseQuery(&ast.Source{Input: sentDocument(entry)})
+		if err != nil {
+			return nil, fmt.Errorf("parsing fragment %s: %w", entry.Name, err)
+		}
+		for _, fragment := range doc.Fragments {
+			if _, ok := shared[fragment.Name]; !ok {
+				shared[fragment.Name] = fragment
+			}
+		}
+	}
+	return shared, nil
+}
+
+// Returns the source text of entry's document as sent, after any transforms.
+func sentDocument(entry typer.QueryType) string {
+	if entry.Sent != "" {
+		return entry.Sent
+	}
+	return entry.Query
+}
+
+// Returns the normalized document of the operation of entry, as sent,
+// followed by every fragment it spreads, directly or not, once each. Returns
+// the empty string if the document does not contain exactly one operation.
+func inlinedDocument(entry typer.QueryType, shared map[string]*ast.FragmentDefinition) (string, error) {
+	doc, parseErr := parser.ParseQuery(&ast.Source{Input: sentDocument(entry)})
+	if parseErr != nil {
+		return "", fmt.Errorf("parsing operation %s: %w", entry.Name, parseErr)
+	}
+	if len(doc.Operations) != 1 {
+		return "", nil
+	}
+	op := doc.Operations[0]
+	fragments, err := inlineFragments(op, doc.Fragments, shared)
+	if err != nil {
+		return "", fmt.Errorf("operation %s: %w", entry.Name, err)
+	}
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(&ast.QueryDocument{
+		Operations: ast.OperationList{op},
+		Fragments:  fragments,
+	})
+	return buf.String(), nil
+}
+
+// Returns the fragments spread by op, directly or not, sorted by name.
+// Fragments defined locally take precedence over shared ones.
+func inlineFragments(op *ast.OperationDefinition, local ast.FragmentDefinitionList, shared map[string]*ast.FragmentDefinition) (ast.FragmentDefinitionList, error) {
+	needed := make(map[string]*ast.FragmentDefinition)
+	var err error
+	var visit func(set ast.SelectionSet)
+	visit = func(set ast.SelectionSet) {
+		for _, selection := range set {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				visit(selection.SelectionSet)
+			case *ast.InlineFragment:
+				visit(selection.SelectionSet)
+			case *ast.FragmentSpread:
+				if _, ok := needed[selection.Name]; ok {
+					continue
+				}
+				fragment := local.ForName(selection.Name)
+				if fragment == nil {
+					fragment = shared[selection.Name]
+				}
+				if fragment == nil {
+					if err == nil {
+						err = fmt.Errorf("unknown fragment %s", selection.Name)
+					}
+					continue
+				}
+				needed[selection.Name] = fragment
+				visit(fragment.SelectionSet)
+			}
+		}
+	}
+	visit(op.SelectionSet)
+	if err != nil {
+		return nil, err
+	}
+
+	fragments := make(ast.FragmentDefinitionList, 0, len(needed))
+	for _, fragment := range needed {
+		fragments = append(fragments, fragment)
+	}
+	sort.Slice(fragments, func(i, j int) bool {
+		return fragments[i].Name < fragments[j].Name
+	})
+	return fragments, nil
+}
+
+// Emits a JSON object mapping the ID of each trusted document to the document,
+// for servers to check incoming operations against. See TrustedDocuments.
+type TrustedDocumentsAllowlist struct {
+	IDs DocumentIDs
+}
+
+func (e *TrustedDocumentsAllowlist) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	docs, err := TrustedDocuments(types, e.IDs)
+	if err != nil {
+		return err
+	}
+	allowlist := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		allowlist[doc.ID] = doc.Document
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(allowlist)
+}
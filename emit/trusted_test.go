@@ -0,0 +1,82 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedDocuments(t *testing.T) {
+	types := clientTestTypes(t,
+		`fragment UserName on User { name }`,
+		"#graphql\nfragment UserFields on User { id ...UserName }",
+		`query GetUser($id: ID!) { user(id: $id) { ...UserFields } }`,
+		`{ now }`,
+		`query Now { now }`,
+		`query  Now  { now }`,
+	)
+	docs, err := TrustedDocuments(types, DocumentIDs{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []TrustedDocument{
+		{
+			ID:        "f8b88c2f7bfbc30f240bda94820f7000bb78a6ca5d2437883f96ecd172c75e45",
+			Name:      "GetUser",
+			Operation: typer.OperationQuery,
+			Document:  "query GetUser ($id: ID!) {\n\tuser(id: $id) {\n\t\t... UserFields\n\t}\n}\nfragment UserFields on User {\n\tid\n\t... UserName\n}\nfragment UserName on User {\n\tname\n}\n",
+			Filename:  "GetUser.graphql",
+		},
+		{
+			ID:        "57aa5b858cc87b4a537c9adf5730273fd3281e884e4faf7da3013c1b93921402",
+			Operation: typer.OperationQuery,
+			Document:  "query {\n\tnow\n}\n",
+			Filename:  "57aa5b858cc87b4a537c9adf5730273fd3281e884e4faf7da3013c1b93921402.graphql",
+		},
+		{
+			ID:        "e2b911f89125614e8bd8be9ec2508c90f1444f3766eef9359c77e0120f45e062",
+			Name:      "Now",
+			Operation: typer.OperationQuery,
+			Document:  "query Now {\n\tnow\n}\n",
+			Filename:  "Now.graphql",
+		},
+	}, docs)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&TrustedDocumentsAllowlist{}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, `{
+  "57aa5b858cc87b4a537c9adf5730273fd3281e884e4faf7da3013c1b93921402": "query {\n\tnow\n}\n",
+  "e2b911f89125614e8bd8be9ec2508c90f1444f3766eef9359c77e0120f45e062": "query Now {\n\tnow\n}\n",
+  "f8b88c2f7bfbc30f240bda94820f7000bb78a6ca5d2437883f96ecd172c75e45": "query GetUser ($id: ID!) {\n\tuser(id: $id) {\n\t\t... UserFields\n\t}\n}\nfragment UserFields on User {\n\tid\n\t... UserName\n}\nfragment UserName on User {\n\tname\n}\n"
+}
+`, buf.String())
+}
+
+func TestDocumentIDs(t *testing.T) {
+	document := "query {\n\tnow\n}\n"
+	assert.Equal(t, "57aa5b858cc87b4a537c9adf5730273fd3281e884e4faf7da3013c1b93921402", DocumentIDs{}.ID(document))
+	assert.Equal(t, "6c2b9ecf66051d2f478fd5c1440e12a4", DocumentIDs{Hash: "md5"}.ID(document))
+	assert.Equal(t, "sha1:98d111746f02d2395d413d6cdd0e41cd9f5d83be", DocumentIDs{Hash: "sha1", Prefix: "sha1:"}.ID(document))
+	assert.Error(t, DocumentIDs{Hash: "crc32"}.Validate())
+
+	types := clientTestTypes(t, `{ now }`)
+	var buf bytes.Buffer
+	if assert.NoError(t, (&ApolloManifest{IDs: DocumentIDs{Hash: "md5"}}).Emit(&buf, types)) {
+		assert.Contains(t, buf.String(), `"signature": "6c2b9ecf66051d2f478fd5c1440e12a4"`)
+	}
+	buf.Reset()
+	if assert.NoError(t, (&OperationRegistry{IDs: DocumentIDs{Hash: "md5"}}).Emit(&buf, types)) {
+		assert.Contains(t, buf.String(), `id: "6c2b9ecf66051d2f478fd5c1440e12a4"`)
+	}
+	assert.Error(t, (&TrustedDocumentsAllowlist{IDs: DocumentIDs{Hash: "crc32"}}).Emit(&buf, types))
+
+	docs, err := TrustedDocuments(types, DocumentIDs{Hash: "md5", Prefix: "md5:"})
+	if assert.NoError(t, err) && assert.Len(t, docs, 1) {
+		assert.Equal(t, "md5:6c2b9ecf66051d2f478fd5c1440e12a4", docs[0].ID)
+		assert.Equal(t, "6c2b9ecf66051d2f478fd5c1440e12a4.graphql", docs[0].Filename)
+	}
+}
@@ -0,0 +1,198 @@
+package emit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Emits a TypeScript module declaring the generated types and the QueryTypes
+// map from query strings to their data and variables types.
+type TypeScript struct {
+	// Module from which custom scalars are imported. Defaults to "./scalars".
+	ScalarsModule string
+
+	// Wrap variables types in Exact<>, as graphql-code-generator does.
+	ExactVariables bool
+
+	// If set, rather than exporting QueryTypes, add its entries to the
+	// QueryTypes interface of this module by module augmentation. A generic
+	// helper typed by that interface then infers the types of documents
+	// without importing anything generated.
+	AugmentModule string
+
+	// Group the entries of QueryTypes by kind, each group sorted by name,
+	// rather than in order of appearance.
+	GroupQueryTypes bool
+
+	// Shorten the output, per typer.Optimize, omitting parentheses that
+	// precedence does not require.
+	Optimize bool
+
+	// Also export the default values of each named operation's variables as
+	// a constant, such as Query_GetUsers_Defaults. See typer.VariableDefaults.
+	VariableDefaults bool
+
+	// Sections of the output to write, of TypeScriptSections, for consumers
+	// that want only some, such as only QueryTypes. All if empty. Sections
+	// still refer to the names of those left out.
+	Sections []string
+}
+
+// Sections of TypeScript output, in order: imports of custom scalars;
+// helper types, such as Exact; declarations of named types; constants, such
+// as variable defaults; and QueryTypes or its augmentation.
+var TypeScriptSections = []string{"scalar-imports", "helpers", "declarations", "constants", "query-types"}
+
+// Checks that each of sections is one of TypeScriptSections.
+func ValidateSections(sections []string) error {
+	for _, section := range sections {
+		known := false
+		for _, name := range TypeScriptSections {
+			known = known || section == name
+		}
+		if !known {
+			return fmt.Errorf("unknown section %q, expected one of: %s", section, strings.Join(TypeScriptSections, ", "))
+		}
+	}
+	return nil
+}
+
+const exactDeclaration = "export type Exact<T extends { [key: string]: unknown }> = { [K in keyof T]: T[K] };"
+
+func (e *TypeScript) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	render := typer.RenderType
+	if e.Optimize {
+		types = typer.Optimize(types)
+		render = typer.RenderCompact
+	}
+	if err := ValidateSections(e.Sections); err != nil {
+		return err
+	}
+	sections := make(map[string]bool)
+	for _, section := range e.Sections {
+		sections[section] = true
+	}
+	enabled := func(section string) bool {
+		return len(sections) == 0 || sections[section]
+	}
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+
+	// Whether any export or import has been written, making this a module.
+	module := false
+	scalars := uniqueScalars(types)
+	if len(scalars) > 0 && enabled("scalar-imports") {
+		writeScalarImports(ew, e.ScalarsModule, scalars)
+		ew.println()
+		module = true
+	}
+
+	decls := typer.NewDeclarationSet(types.Declarations)
+	if decls.Len() > 0 && e.ExactVariables && enabled("helpers") {
+		ew.println(exactDeclaration)
+		if !enabled("declarations") {
+			ew.println()
+		}
+		module = true
+	}
+	if decls.Len() > 0 && enabled("declarations") {
+		for _, decl := range decls.Ordered() {
+			if e.ExactVariables && decl.Kind == typer.DeclarationVariables {
+				ew.printf("export type %s = Exact<%s>;\n", decl.Name, render(decl.Type))
+				continue
+			}
+			ew.printf("export type %s = %s;\n", decl.Name, render(decl.Type))
+		}
+		ew.println()
+		module = true
+	}
+
+	if e.VariableDefaults && enabled("constants") {
+		if writeVariableDefaults(ew, types) > 0 {
+			module = true
+		}
+	}
+
+	if !enabled("query-types") {
+		return ew.err
+	}
+	if e.AugmentModule != "" {
+		if !module {
+			// Augmentations are only allowed in modules.
+			ew.println("export {};")
+			ew.println()
+		}
+		writeQueryTypesAugmentation(ew, e.AugmentModule, types, e.GroupQueryTypes, render)
+	} else {
+		writeQueryTypes(ew, types, e.GroupQueryTypes, render)
+	}
+	return ew.err
+}
+
+// Writes a constant of the variables' default values of each entry that has
+// them, as const so that their types are literal. Returns how many.
+func writeVariableDefaults(ew *errWriter, types typer.GeneratedTypes) int {
+	seen := make(map[string]bool)
+	for _, entry := range types.QueryMap {
+		if entry.Defaults == nil || seen[entry.Defaults.Name] {
+			continue
+		}
+		seen[entry.Defaults.Name] = true
+		values := make([]string, len(entry.Defaults.Values))
+		for i, value := range entry.Defaults.Values {
+			values[i] = value.Name + ": " + value.Value
+		}
+		ew.printf("export const %s = { %s } as const;\n", entry.Defaults.Name, strings.Join(values, ", "))
+	}
+	if len(seen) > 0 {
+		ew.println()
+	}
+	return len(seen)
+}
+
+func writeScalarImports(ew *errWriter, scalarsModule string, scalars []string) {
+	if scalarsModule == "" {
+		scalarsModule = "./scalars"
+	}
+	ew.printf("import type { %s } from %s;\n", strings.Join(scalars, ", "), typer.StringToJSON(scalarsModule))
+}
+
+func writeQueryTypes(ew *errWriter, types typer.GeneratedTypes, grouped bool, render func(typer.Type) string) {
+	ew.println("export type QueryTypes = {")
+	writeQueryEntries(ew, "  ", types.QueryMap, grouped, func(entry typer.QueryType) {
+		ew.printf("  %s: %s;\n", typer.StringToJSON(entry.Query), entry.RenderWith(render))
+	})
+	writeFailures(ew, "  ", types.Failures)
+	ew.println("}")
+}
+
+func writeQueryTypesAugmentation(ew *errWriter, module string, types typer.GeneratedTypes, grouped bool, render func(typer.Type) string) {
+	ew.printf("declare module %s {\n", typer.StringToJSON(module))
+	ew.println("  interface QueryTypes {")
+	writeQueryEntries(ew, "    ", types.QueryMap, grouped, func(entry typer.QueryType) {
+		ew.printf("    %s: %s;\n", typer.StringToJSON(entry.Query), entry.RenderWith(render))
+	})
+	writeFailures(ew, "    ", types.Failures)
+	ew.println("  }")
+	ew.println("}")
+}
+
+// Writes a placeholder entry of QueryTypes for each document that failed to
+// type, commented with its problems. Data and variables are any, so that code
+// using the document still type-checks.
+func writeFailures(ew *errWriter, indent string, failures []typer.FailedDocument) {
+	if len(failures) == 0 {
+		return
+	}
+	ew.printf("%s// Placeholders for documents that failed to type.\n", indent)
+	for _, failure := range failures {
+		for _, line := range strings.Split(failure.Error, "\n") {
+			ew.printf("%s// ERROR: %s\n", indent, line)
+		}
+		ew.printf("%s%s: { data: any; variables: any; };\n", indent, typer.StringToJSON(failure.Query))
+	}
+}
@@ -0,0 +1,233 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestTypeScript(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				now: Instant!
+				later: Instant!
+				id: UUID!
+			}
+
+			scalar Instant
+			scalar UUID
+		`,
+	})
+	tp := &typer.Typer{
+		Schema: schema,
+	}
+	for _, query := range []string{`query Clock { now later }`, `{ id }`} {
+		if _, _, err := tp.VisitString("", query); !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	emitter := &TypeScript{
+		ScalarsModule: "../scalars",
+	}
+	if !assert.NoError(t, emitter.Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import type { Instant, UUID } from "../scalars";
+
+export type Query_Clock_Data = { __typename: "Query"; later: Instant; now: Instant; };
+export type Query_Clock_Variables = { };
+
+export type QueryTypes = {
+  "query Clock { now later }": { data: Query_Clock_Data; variables: Query_Clock_Variables; };
+  "{ id }": { data: { __typename: "Query"; id: UUID; }; variables: { }; };
+}
+`, buf.String())
+}
+
+func TestTypeScriptCodegenCompatible(t *testing.T) {
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema}),
+		Options: typer.Options{
+			Naming: typer.CodegenNaming,
+		},
+	}
+	if _, _, err := tp.VisitString("", `query GetUser($id: ID!) { user(id: $id) { name } }`); !assert.NoError(t, err) {
+		return
+	}
+
+	var buf bytes.Buffer
+	emitter := &TypeScript{
+		ExactVariables: true,
+	}
+	if !assert.NoError(t, emitter.Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export type Exact<T extends { [key: string]: unknown }> = { [K in keyof T]: T[K] };
+export type GetUserQuery = { __typename: "Query"; user: (({ __typename: "User"; name: string; }) | null); };
+export type GetUserQueryVariables = Exact<{ id: string; }>;
+
+export type QueryTypes = {
+  "query GetUser($id: ID!) { user(id: $id) { name } }": { data: GetUserQuery; variables: GetUserQueryVariables; };
+}
+`, buf.String())
+}
+
+func TestTypeScriptAugmentModule(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := &TypeScript{
+		AugmentModule: "$lib/graphql",
+	}
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, `query Now { now }`))) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export type Query_Now_Data = { __typename: "Query"; now: string; };
+export type Query_Now_Variables = { };
+
+declare module "$lib/graphql" {
+  interface QueryTypes {
+    "query Now { now }": { data: Query_Now_Data; variables: Query_Now_Variables; };
+  }
+}
+`, buf.String())
+
+	buf.Reset()
+	if !assert.NoError(t, emitter.Emit(&buf, clientTestTypes(t, `{ now }`))) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export {};
+
+declare module "$lib/graphql" {
+  interface QueryTypes {
+    "{ now }": { data: { __typename: "Query"; now: string; }; variables: { }; };
+  }
+}
+`, buf.String())
+}
+
+func TestTypeScriptGroupQueryTypes(t *testing.T) {
+	types := clientTestTypes(t,
+		`query Now { now }`,
+		`mutation Delete($id: ID!) { deleteUser(id: $id) }`,
+		`{ now }`,
+		`fragment UserName on User { name }`,
+		`query GetUser($id: ID!) { user(id: $id) { ...UserName } }`,
+	)
+	var buf bytes.Buffer
+	emitter := &TypeScript{GroupQueryTypes: true}
+	if !assert.NoError(t, emitter.Emit(&buf, types)) {
+		return
+	}
+	out := buf.String()
+	assert.Contains(t, out, `export type QueryTypes = {
+  // Queries
+  "query GetUser($id: ID!) { user(id: $id) { ...UserName } }": { data: Query_GetUser_Data; variables: Query_GetUser_Variables; };
+  "query Now { now }": { data: Query_Now_Data; variables: Query_Now_Variables; };
+  "{ now }": { data: { __typename: "Query"; now: string; }; variables: { }; };
+
+  // Mutations
+  "mutation Delete($id: ID!) { deleteUser(id: $id) }": { data: Mutation_Delete_Data; variables: Mutation_Delete_Variables; };
+
+  // Fragments
+  "fragment UserName on User { name }": { data: Fragment_UserName_Data; variables: Fragment_UserName_Variables; };
+}
+`)
+}
+
+func TestTypeScriptVariableDefaults(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				users(first: Int, order: Order, filter: Filter, tags: [String!]): [String!]!
+			}
+
+			enum Order { ASC DESC }
+
+			input Filter { active: Boolean, name: String }
+		`,
+	})
+	tp := &typer.Typer{
+		Schema: schema,
+	}
+	queries := []string{
+		`query Users($first: Int = 20, $order: Order = DESC, $filter: Filter = { active: true, name: null }, $tags: [String!] = ["a", "b"]) { users(first: $first, order: $order, filter: $filter, tags: $tags) }`,
+		`query NoDefaults($first: Int) { users(first: $first) }`,
+		`query ($first: Int = 10) { users(first: $first) }`,
+	}
+	for _, query := range queries {
+		if _, _, err := tp.VisitString("", query); !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&TypeScript{VariableDefaults: true}).Emit(&buf, tp.GeneratedTypes)) {
+		return
+	}
+	assert.Contains(t, buf.String(), `
+export const Query_Users_Defaults = { first: 20, order: "DESC", filter: { active: true, name: null }, tags: ["a", "b"] } as const;
+
+export type QueryTypes = {
+`)
+	assert.NotContains(t, buf.String(), "NoDefaults_Defaults")
+}
+
+func TestTypeScriptSections(t *testing.T) {
+	tp := &typer.Typer{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema}),
+		Options: typer.Options{
+			Naming: typer.CodegenNaming,
+		},
+	}
+	if _, _, err := tp.VisitString("", `query GetUser($id: ID!) { user(id: $id) { name } }`); !assert.NoError(t, err) {
+		return
+	}
+	output := func(emitter *TypeScript) string {
+		var buf bytes.Buffer
+		assert.NoError(t, emitter.Emit(&buf, tp.GeneratedTypes))
+		return buf.String()
+	}
+
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export type QueryTypes = {
+  "query GetUser($id: ID!) { user(id: $id) { name } }": { data: GetUserQuery; variables: GetUserQueryVariables; };
+}
+`, output(&TypeScript{ExactVariables: true, Sections: []string{"query-types"}}))
+
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export type Exact<T extends { [key: string]: unknown }> = { [K in keyof T]: T[K] };
+
+`, output(&TypeScript{ExactVariables: true, Sections: []string{"helpers"}}))
+
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+export {};
+
+declare module "$lib/graphql" {
+  interface QueryTypes {
+    "query GetUser($id: ID!) { user(id: $id) { name } }": { data: GetUserQuery; variables: GetUserQueryVariables; };
+  }
+}
+`, output(&TypeScript{AugmentModule: "$lib/graphql", Sections: []string{"query-types"}}))
+
+	err := (&TypeScript{Sections: []string{"imports"}}).Emit(&bytes.Buffer{}, tp.GeneratedTypes)
+	assert.EqualError(t, err, `unknown section "imports", expected one of: scalar-imports, helpers, declarations, constants, query-types`)
+}
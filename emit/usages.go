@@ -0,0 +1,26 @@
+package emit
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Emits, as JSON, where each operation, fragment, and schema field is used in
+// the source files. See typer.UsageMap.
+type Usages struct {
+	// The schema the types were generated against.
+	Schema *ast.Schema
+}
+
+func (e *Usages) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	usages, err := typer.UsageMap(e.Schema, types)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(usages)
+}
@@ -0,0 +1,46 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestUsages(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.gql", Input: clientTestSchema})
+	types := clientTestTypes(t, `query Now { now }`)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Usages{Schema: schema}).Emit(&buf, types)) {
+		return
+	}
+	assert.Equal(t, `{
+  "operations": [
+    {
+      "operation": "query",
+      "name": "Now",
+      "definitions": [
+        {
+          "file": "ops.ts",
+          "line": 1,
+          "column": 1
+        }
+      ]
+    }
+  ],
+  "fragments": [],
+  "fields": {
+    "Query.now": [
+      {
+        "file": "ops.ts",
+        "line": 1,
+        "column": 1
+      }
+    ]
+  }
+}
+`, buf.String())
+}
@@ -0,0 +1,291 @@
+package emit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Validator modules are like the output of TypeScript, except that each
+// declaration is a runtime validator, from which its static type is derived
+// so that the two cannot drift. QuerySchemas maps each query string to the
+// validators of its data and variables, for checking responses from untrusted
+// servers. Custom scalars are not validated.
+
+// Constructors for the validator libraries selectable by name, as with the
+// --validators flag of the extractgqlts command.
+var Validators = map[string]func() Emitter{
+	"zod":     func() Emitter { return &Zod{} },
+	"valibot": func() Emitter { return &Valibot{} },
+}
+
+// Returns the sorted names of all validator libraries.
+func ValidatorNames() []string {
+	names := make([]string, 0, len(Validators))
+	for name := range Validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func NewValidators(name string) (Emitter, error) {
+	newEmitter, ok := Validators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown validators %q, expected one of: %s", name, strings.Join(ValidatorNames(), ", "))
+	}
+	return newEmitter(), nil
+}
+
+// Emits a validator module using Zod.
+type Zod struct {
+	// Module from which custom scalars are imported. Defaults to "./scalars".
+	ScalarsModule string
+}
+
+func (e *Zod) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	return emitValidators(w, types, e.ScalarsModule, zodDialect{})
+}
+
+// Emits a validator module using Valibot, whose modular API suits bundle-size
+// sensitive frontends.
+type Valibot struct {
+	// Module from which custom scalars are imported. Defaults to "./scalars".
+	ScalarsModule string
+}
+
+func (e *Valibot) Emit(w io.Writer, types typer.GeneratedTypes) error {
+	return emitValidators(w, types, e.ScalarsModule, valibotDialect{})
+}
+
+// The syntax of a validator library. Arguments are validator expressions.
+type validatorDialect interface {
+	importLine() string
+	// Returns the static type of a declared validator.
+	infer(name string) string
+	keyword(name string) (string, bool)
+	// Accepts any value as the given TypeScript type.
+	custom(typ string) string
+	literal(value string) string
+	object(fields []string, validators []string) string
+	array(elem string) string
+	nullable(inner string, undefined bool) string
+	union(members []string) string
+	intersection(members []string) string
+}
+
+func emitValidators(w io.Writer, types typer.GeneratedTypes, scalarsModule string, dialect validatorDialect) error {
+	vw := &validatorWriter{
+		dialect: dialect,
+		scalars: make(map[string]bool, len(types.Scalars)),
+	}
+	for _, scalar := range types.Scalars {
+		vw.scalars[scalar] = true
+	}
+
+	ew := &errWriter{w: w}
+	ew.println("// GENERATED FILE. DO NOT EDIT.")
+	ew.println()
+	ew.println(dialect.importLine())
+	if scalars := uniqueScalars(types); len(scalars) > 0 {
+		writeScalarImports(ew, scalarsModule, scalars)
+	}
+	ew.println()
+
+	if decls := typer.NewDeclarationSet(types.Declarations); decls.Len() > 0 {
+		for _, decl := range decls.Ordered() {
+			ew.printf("export const %s = %s;\n", decl.Name, vw.validator(decl.Type))
+			ew.printf("export type %s = %s;\n", decl.Name, dialect.infer(decl.Name))
+		}
+		ew.println()
+	}
+
+	writeQueryTypes(ew, types, false, typer.RenderType)
+	ew.println()
+	ew.println("export const QuerySchemas = {")
+	for _, entry := range types.QueryMap {
+		ew.printf("  %s: { data: %s, variables: %s },\n", typer.StringToJSON(entry.Query), vw.validator(entry.Data), vw.validator(entry.Variables))
+	}
+	ew.println("};")
+	return ew.err
+}
+
+type validatorWriter struct {
+	dialect validatorDialect
+	scalars map[string]bool
+}
+
+// Returns a validator expression for typ. Named types other than keywords are
+// custom scalars, which are not validated, or declarations, whose validators
+// are declared beforehand under the same name.
+func (vw *validatorWriter) validator(typ typer.Type) string {
+	d := vw.dialect
+	switch typ := typ.(type) {
+	case typer.NamedType:
+		if validator, ok := d.keyword(typ.Name); ok {
+			return validator
+		}
+		if vw.scalars[typ.Name] {
+			return d.custom(typ.Name)
+		}
+		return typ.Name
+	case typer.StringLiteralType:
+		return d.literal(typer.StringToJSON(typ.Value))
+	case typer.RawType:
+		return d.custom(typ.Text)
+	case typer.ObjectType:
+		names := make([]string, len(typ.Fields))
+		validators := make([]string, len(typ.Fields))
+		for i, field := range typ.Fields {
+			names[i] = field.Name
+			validators[i] = vw.validator(field.Type)
+		}
+		return d.object(names, validators)
+	case typer.ArrayType:
+		return d.array(vw.validator(typ.Elem))
+	case typer.NullableType:
+		return d.nullable(vw.validator(typ.Type), typ.Undefined)
+	case typer.UnionType:
+		switch len(typ.Members) {
+		case 0:
+			v, _ := d.keyword("never")
+			return v
+		case 1:
+			return vw.validator(typ.Members[0])
+		default:
+			return d.union(vw.validators(typ.Members))
+		}
+	case typer.IntersectionType:
+		switch len(typ.Members) {
+		case 0:
+			v, _ := d.keyword("never")
+			return v
+		case 1:
+			return vw.validator(typ.Members[0])
+		default:
+			return d.intersection(vw.validators(typ.Members))
+		}
+	case nil:
+		v, _ := d.keyword("unknown")
+		return v
+	default:
+		panic(fmt.Errorf("unexpected type: %T", typ))
+	}
+}
+
+func (vw *validatorWriter) validators(types []typer.Type) []string {
+	res := make([]string, len(types))
+	for i, typ := range types {
+		res[i] = vw.validator(typ)
+	}
+	return res
+}
+
+func writeObjectFields(b *strings.Builder, names, validators []string) {
+	b.WriteString("{ ")
+	for i, name := range names {
+		b.WriteString(name)
+		b.WriteString(": ")
+		b.WriteString(validators[i])
+		b.WriteString(", ")
+	}
+	b.WriteString("}")
+}
+
+var validatorKeywords = map[string]bool{
+	"string":  true,
+	"number":  true,
+	"boolean": true,
+	"unknown": true,
+	"never":   true,
+}
+
+type zodDialect struct{}
+
+func (zodDialect) importLine() string { return `import { z } from "zod";` }
+
+func (zodDialect) infer(name string) string { return fmt.Sprintf("z.infer<typeof %s>", name) }
+
+func (zodDialect) keyword(name string) (string, bool) {
+	return "z." + name + "()", validatorKeywords[name]
+}
+
+func (zodDialect) custom(typ string) string { return fmt.Sprintf("z.custom<%s>()", typ) }
+
+func (zodDialect) literal(value string) string { return fmt.Sprintf("z.literal(%s)", value) }
+
+func (zodDialect) object(names, validators []string) string {
+	var b strings.Builder
+	b.WriteString("z.object(")
+	writeObjectFields(&b, names, validators)
+	b.WriteString(")")
+	return b.String()
+}
+
+func (zodDialect) array(elem string) string { return fmt.Sprintf("z.array(%s)", elem) }
+
+func (zodDialect) nullable(inner string, undefined bool) string {
+	if undefined {
+		return inner + ".nullish()"
+	}
+	return inner + ".nullable()"
+}
+
+func (zodDialect) union(members []string) string {
+	return "z.union([" + strings.Join(members, ", ") + "])"
+}
+
+func (zodDialect) intersection(members []string) string {
+	var b strings.Builder
+	b.WriteString(members[0])
+	for _, member := range members[1:] {
+		b.WriteString(".and(")
+		b.WriteString(member)
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+type valibotDialect struct{}
+
+func (valibotDialect) importLine() string { return `import * as v from "valibot";` }
+
+func (valibotDialect) infer(name string) string {
+	return fmt.Sprintf("v.InferOutput<typeof %s>", name)
+}
+
+func (valibotDialect) keyword(name string) (string, bool) {
+	return "v." + name + "()", validatorKeywords[name]
+}
+
+func (valibotDialect) custom(typ string) string { return fmt.Sprintf("v.custom<%s>(() => true)", typ) }
+
+func (valibotDialect) literal(value string) string { return fmt.Sprintf("v.literal(%s)", value) }
+
+func (valibotDialect) object(names, validators []string) string {
+	var b strings.Builder
+	b.WriteString("v.object(")
+	writeObjectFields(&b, names, validators)
+	b.WriteString(")")
+	return b.String()
+}
+
+func (valibotDialect) array(elem string) string { return fmt.Sprintf("v.array(%s)", elem) }
+
+func (valibotDialect) nullable(inner string, undefined bool) string {
+	if undefined {
+		return fmt.Sprintf("v.nullish(%s)", inner)
+	}
+	return fmt.Sprintf("v.nullable(%s)", inner)
+}
+
+func (valibotDialect) union(members []string) string {
+	return "v.union([" + strings.Join(members, ", ") + "])"
+}
+
+func (valibotDialect) intersection(members []string) string {
+	return "v.intersect([" + strings.Join(members, ", ") + "])"
+}
@@ -0,0 +1,91 @@
+package emit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func validatorTestTypes(t *testing.T) typer.GeneratedTypes {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				now: Instant!
+				tags(first: Int): [String!]
+			}
+
+			scalar Instant
+		`,
+	})
+	tp := &typer.Typer{
+		Schema: schema,
+		Options: typer.Options{
+			Scalars: map[string]string{"Int": "bigint"},
+		},
+	}
+	for _, query := range []string{`query Tags($first: Int) { tags(first: $first) }`, `{ now }`} {
+		if _, _, err := tp.VisitString("", query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tp.GeneratedTypes
+}
+
+func TestZod(t *testing.T) {
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Zod{}).Emit(&buf, validatorTestTypes(t))) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import { z } from "zod";
+import type { Instant } from "./scalars";
+
+export const Query_Tags_Data = z.object({ __typename: z.literal("Query"), tags: z.array(z.string()).nullable(), });
+export type Query_Tags_Data = z.infer<typeof Query_Tags_Data>;
+export const Query_Tags_Variables = z.object({ first: z.custom<bigint>().nullable(), });
+export type Query_Tags_Variables = z.infer<typeof Query_Tags_Variables>;
+
+export type QueryTypes = {
+  "query Tags($first: Int) { tags(first: $first) }": { data: Query_Tags_Data; variables: Query_Tags_Variables; };
+  "{ now }": { data: { __typename: "Query"; now: Instant; }; variables: { }; };
+}
+
+export const QuerySchemas = {
+  "query Tags($first: Int) { tags(first: $first) }": { data: Query_Tags_Data, variables: Query_Tags_Variables },
+  "{ now }": { data: z.object({ __typename: z.literal("Query"), now: z.custom<Instant>(), }), variables: z.object({ }) },
+};
+`, buf.String())
+}
+
+func TestValibot(t *testing.T) {
+	var buf bytes.Buffer
+	if !assert.NoError(t, (&Valibot{}).Emit(&buf, validatorTestTypes(t))) {
+		return
+	}
+	assert.Equal(t, `// GENERATED FILE. DO NOT EDIT.
+
+import * as v from "valibot";
+import type { Instant } from "./scalars";
+
+export const Query_Tags_Data = v.object({ __typename: v.literal("Query"), tags: v.nullable(v.array(v.string())), });
+export type Query_Tags_Data = v.InferOutput<typeof Query_Tags_Data>;
+export const Query_Tags_Variables = v.object({ first: v.nullable(v.custom<bigint>(() => true)), });
+export type Query_Tags_Variables = v.InferOutput<typeof Query_Tags_Variables>;
+
+export type QueryTypes = {
+  "query Tags($first: Int) { tags(first: $first) }": { data: Query_Tags_Data; variables: Query_Tags_Variables; };
+  "{ now }": { data: { __typename: "Query"; now: Instant; }; variables: { }; };
+}
+
+export const QuerySchemas = {
+  "query Tags($first: Int) { tags(first: $first) }": { data: Query_Tags_Data, variables: Query_Tags_Variables },
+  "{ now }": { data: v.object({ __typename: v.literal("Query"), now: v.custom<Instant>(() => true), }), variables: v.object({ }) },
+};
+`, buf.String())
+}
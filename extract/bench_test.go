@@ -0,0 +1,49 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// A source file of roughly size bytes, containing the given queries in
+// template literals amongst ordinary code.
+func syntheticSource(size int, queries []string) []byte {
+	var b strings.Builder
+	for _, query := range queries {
+		fmt.Fprintf(&b, "const q = `#graphql\n%s\n`;\n", query)
+	}
+	filler := "export const f = (x: number): string => `${x}` + 'filler';\n"
+	for b.Len() < size {
+		b.WriteString(filler)
+	}
+	return []byte(b.String())
+}
+
+func BenchmarkExtractLargeFileWithoutQueries(b *testing.B) {
+	src := syntheticSource(4<<20, nil)
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := QueriesFromBytes(src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExtractLargeFileWithQueries(b *testing.B) {
+	queries := make([]string, 100)
+	for i := range queries {
+		queries[i] = fmt.Sprintf("query Q%d { t0 { id name next { id name next { id } } } }", i)
+	}
+	src := syntheticSource(4<<20, queries)
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := QueriesFromBytes(src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
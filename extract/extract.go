@@ -0,0 +1,216 @@
+// Package extract finds GraphQL documents embedded in source files, such as
+// TypeScript or Svelte, without typing them. A document is the contents of a
+// template literal that begins with #graphql.
+package extract
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func QueriesFromString(s string) ([]string, error) {
+	return QueriesFromBytes([]byte(s))
+}
+
+var marker = []byte("#graphql")
+var newline = []byte("\n")
+
+// Finds the offset of the next backtick that begins a GraphQL template, or -1.
+func indexStart(bs []byte) int {
+	offset := 0
+	for {
+		i := bytes.IndexByte(bs[offset:], '`')
+		if i < 0 {
+			return -1
+		}
+		offset += i + 1
+		if bytes.HasPrefix(bs[offset:], marker) {
+			return offset - 1
+		}
+	}
+}
+
+// Returns the text of each document in bs. Faster than Documents, but records
+// nothing else.
+func QueriesFromBytes(bs []byte) ([]string, error) {
+	var res []string
+	for len(bs) > 0 {
+		start := indexStart(bs)
+		if start < 0 {
+			break
+		}
+		bs = bs[start+1:]
+
+		// Scan until the end of the string. Backticks are ASCII, so there is no
+		// need to decode runes.
+		// TODO: Handle nested string templates, etc.
+		end := bytes.IndexByte(bs, '`')
+		if end < 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		res = append(res, string(bs[:end]))
+		bs = bs[end+1:]
+	}
+	return res, nil
+}
+
+// Reports a GraphQL template that is never closed. Is io.ErrUnexpectedEOF.
+type UnterminatedTemplateError struct {
+	Offset       int
+	Line, Column int
+}
+
+func (e *UnterminatedTemplateError) Error() string {
+	return fmt.Sprintf("unterminated template starting at %d:%d", e.Line, e.Column)
+}
+
+func (e *UnterminatedTemplateError) Unwrap() error {
+	return io.ErrUnexpectedEOF
+}
+
+// A GraphQL document found in a template literal.
+type Document struct {
+	// Byte offset of the template's contents, just past the opening backtick.
+	Offset int
+	// 1-based line and byte column of Offset.
+	Line, Column int
+	Text         string
+	// The identifier immediately before the opening backtick, such as gql or
+	// graphql, if the template is tagged.
+	Tag string
+	// Whether the template contains ${...} substitutions, in which case Text
+	// is not the document seen at runtime.
+	Interpolated bool
+}
+
+// Like DocumentsFromReader, but for text already in memory.
+func DocumentsFromString(s string) ([]Document, error) {
+	return DocumentsFromReader(strings.NewReader(s))
+}
+
+// Reads r incrementally and returns each document, with where it was found.
+// Memory use is bounded by the read buffer plus the size of the largest
+// GraphQL template, regardless of the size of the input.
+func DocumentsFromReader(r io.Reader) ([]Document, error) {
+	br := bufio.NewReader(r)
+	var res []Document
+	var template bytes.Buffer
+	offset := 0
+	line, lineStart := 1, 0
+	advance := func(bs []byte) {
+		if n := bytes.Count(bs, newline); n > 0 {
+			line += n
+			lineStart = offset + bytes.LastIndexByte(bs, '\n') + 1
+		}
+		offset += len(bs)
+	}
+	// Trailing identifier bytes of the code skipped so far, for tags.
+	var name []byte
+	skipName := func(bs []byte) {
+		i := len(bs)
+		for i > 0 && IsNameByte(bs[i-1]) {
+			i--
+		}
+		if i > 0 {
+			name = name[:0]
+		}
+		name = append(name, bs[i:]...)
+	}
+	for {
+		// Skip to the next backtick.
+		skipped, err := br.ReadSlice('`')
+		advance(skipped)
+		if err != nil {
+			if errors.Is(err, bufio.ErrBufferFull) {
+				skipName(skipped)
+				continue
+			}
+			if err == io.EOF {
+				return res, nil
+			}
+			return nil, err
+		}
+		skipName(skipped[:len(skipped)-1])
+		tag := string(name)
+		name = name[:0]
+		if prefix, _ := br.Peek(len(marker)); !bytes.Equal(prefix, marker) {
+			continue
+		}
+
+		// Accumulate until the end of the string.
+		start := Document{
+			Offset: offset,
+			Line:   line,
+			Column: offset - lineStart + 1,
+			Tag:    tag,
+		}
+		template.Reset()
+		for {
+			chunk, err := br.ReadSlice('`')
+			advance(chunk)
+			if err == nil {
+				template.Write(chunk[:len(chunk)-1])
+				break
+			}
+			template.Write(chunk)
+			if errors.Is(err, bufio.ErrBufferFull) {
+				continue
+			}
+			if err == io.EOF {
+				return nil, &UnterminatedTemplateError{
+					Offset: start.Offset,
+					Line:   start.Line,
+					Column: start.Column,
+				}
+			}
+			return nil, err
+		}
+		start.Text = template.String()
+		start.Interpolated = strings.Contains(start.Text, "${")
+		res = append(res, start)
+	}
+}
+
+// Reports whether b may be part of an ASCII identifier, whether of
+// JavaScript, which allows $, or of GraphQL.
+func IsNameByte(b byte) bool {
+	return b == '_' || b == '$' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// Returns the names, such as identifiers and the words of strings and
+// comments, in s outside of GraphQL templates. Code reading a field of a
+// result names it, whether as obj.name, obj["name"], or { name } = obj.
+func Names(s string) (map[string]bool, error) {
+	docs, err := DocumentsFromString(s)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	addNames := func(code string) {
+		start := -1
+		for i := 0; i <= len(code); i++ {
+			if i < len(code) && IsNameByte(code[i]) {
+				if start < 0 {
+					start = i
+				}
+				continue
+			}
+			if start >= 0 {
+				names[code[start:i]] = true
+				start = -1
+			}
+		}
+	}
+	offset := 0
+	for _, doc := range docs {
+		addNames(s[offset:doc.Offset])
+		// Skip the template and its closing backtick.
+		offset = doc.Offset + len(doc.Text) + 1
+	}
+	addNames(s[offset:])
+	return names, nil
+}
@@ -0,0 +1,109 @@
+package extract
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected []string
+	}{
+		{
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			Input:    "`#graphql { hello }`",
+			Expected: []string{"#graphql { hello }"},
+		},
+		{
+			Input: "\n\n`#graphql { hello { goodbye } } `   \n  `#graphql fragment Foo {\n  bar\n}`",
+			Expected: []string{
+				"#graphql { hello { goodbye } } ",
+				"#graphql fragment Foo {\n  bar\n}",
+			},
+		},
+		{
+			Input:    "const a = `plain`; const b = `#graphql { hello }`; const c = `#graph`;",
+			Expected: []string{"#graphql { hello }"},
+		},
+	}
+	for _, test := range tests {
+		actual, err := QueriesFromString(test.Input)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.Expected, actual, "input: %s", test.Input)
+		}
+		templates, err := DocumentsFromReader(strings.NewReader(test.Input))
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.Expected, documentTexts(templates), "reader input: %s", test.Input)
+			for _, template := range templates {
+				assert.Equal(t, template.Text, test.Input[template.Offset:template.Offset+len(template.Text)])
+				before := test.Input[:template.Offset]
+				assert.Equal(t, strings.Count(before, "\n")+1, template.Line)
+				assert.Equal(t, len(before)-strings.LastIndex(before, "\n"), template.Column)
+			}
+		}
+	}
+
+	{
+		_, err := QueriesFromString("`#graphql")
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		_, err = DocumentsFromReader(strings.NewReader("`#graphql"))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	}
+
+	// Templates and input larger than the read buffer.
+	{
+		query := "#graphql {" + strings.Repeat(" hello", 20000) + " }"
+		input := strings.Repeat("const x = 1;\n", 20000) + "`" + query + "`"
+		actual, err := DocumentsFromReader(strings.NewReader(input))
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Document{{Offset: len(input) - len(query) - 1, Line: 20001, Column: 2, Text: query}}, actual)
+		}
+	}
+}
+
+func documentTexts(documents []Document) []string {
+	var texts []string
+	for _, doc := range documents {
+		texts = append(texts, doc.Text)
+	}
+	return texts
+}
+
+func TestDocumentMetadata(t *testing.T) {
+	tests := []struct {
+		Input        string
+		Tag          string
+		Interpolated bool
+	}{
+		{Input: "const q = `#graphql { a }`;"},
+		{Input: "const q = gql`#graphql { a }`;", Tag: "gql"},
+		{Input: "graphql.$tag_1`#graphql { a }`", Tag: "$tag_1"},
+		{Input: "x(`plain`)+graphql`#graphql { a }`", Tag: "graphql"},
+		{Input: "gql`#graphql { ...F }\n${fragment}`", Tag: "gql", Interpolated: true},
+	}
+	for _, test := range tests {
+		documents, err := DocumentsFromString(test.Input)
+		if assert.NoError(t, err) && assert.Len(t, documents, 1, "input: %s", test.Input) {
+			assert.Equal(t, test.Tag, documents[0].Tag, "input: %s", test.Input)
+			assert.Equal(t, test.Interpolated, documents[0].Interpolated, "input: %s", test.Input)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	names, err := Names("const q = gql`#graphql { user { name email } }`;\nconst { user } = data;\nrender(user['avatar_url'], user.$id);")
+	if assert.NoError(t, err) {
+		for _, name := range []string{"q", "gql", "user", "data", "render", "avatar_url", "$id"} {
+			assert.True(t, names[name], "name: %s", name)
+		}
+		assert.False(t, names["email"])
+		assert.False(t, names["graphql"])
+	}
+}
@@ -0,0 +1,38 @@
+//go:build go1.18
+
+package extract
+
+import (
+	"testing"
+)
+
+// Run with go test ./extract -fuzz FuzzDocuments.
+func FuzzDocuments(f *testing.F) {
+	f.Add("`#graphql { hello }`")
+	f.Add("const q = gql`#graphql\nquery ${name} { hello }`;\n`#graphql")
+	f.Add("`plain` `#graph` `#graphql fragment F on T { x }`")
+	f.Fuzz(func(t *testing.T, s string) {
+		queries, queriesErr := QueriesFromString(s)
+		docs, err := DocumentsFromString(s)
+		if (err == nil) != (queriesErr == nil) {
+			t.Fatalf("DocumentsFromString error %v, but QueriesFromString error %v", err, queriesErr)
+		}
+		if err != nil {
+			return
+		}
+		if len(docs) != len(queries) {
+			t.Fatalf("%d documents, but %d queries", len(docs), len(queries))
+		}
+		for i, doc := range docs {
+			if doc.Text != queries[i] {
+				t.Fatalf("document %d is %q, but query is %q", i, doc.Text, queries[i])
+			}
+			if doc.Offset+len(doc.Text) > len(s) || s[doc.Offset:doc.Offset+len(doc.Text)] != doc.Text {
+				t.Fatalf("document %d is not at its offset %d", i, doc.Offset)
+			}
+		}
+		if _, err := Names(s); err != nil {
+			t.Fatalf("Names: %v", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package generate
+
+import (
+	"errors"
+
+	"github.com/deref/extractgqlts/extract"
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Every diagnostic has a code identifying the kind of problem, such as
+// TYPE001 for a field that does not exist. Codes are stable: once assigned, a
+// code is never reused for a different kind of problem, so suppressions and CI
+// policies may rely on them. New kinds of problems get new codes.
+
+const (
+	CodeUnterminatedTemplate = "EXTR001"
+	CodeUnreadableInput      = "EXTR002"
+	CodeInvalidPattern       = "EXTR003"
+	CodeSyntax               = "SYNT001"
+	CodeNoDefinitions        = "DOCS001"
+	CodeMultipleDefinitions  = "DOCS002"
+	CodeDuplicateName        = "DOCS003"
+	CodeDuplicateDocument    = "DOCS004"
+	CodeOverlappingOperation = "DOCS005"
+	CodeAnonymousOperation   = "LINT004"
+	CodeScalarsModule        = "SCLR001"
+	CodeUnmappedScalar       = "SCLR002"
+	CodeBreakingChange       = "SCHM001"
+	CodeDeprecatedOnlyType   = "SCHM002"
+	CodePackageJSON          = "TAGS001"
+	CodeCache                = "GENR001"
+	// Problems of no more specific kind.
+	CodeOther = "GENR000"
+)
+
+// Codes of problems found by validation and lint rules, by rule name.
+var ruleCodes = map[string]string{
+	"FieldsOnCorrectType":          "TYPE001",
+	"FragmentsOnCompositeTypes":    "TYPE002",
+	"KnownArgumentNames":           "TYPE003",
+	"KnownDirectives":              "TYPE004",
+	"KnownFragmentNames":           "TYPE005",
+	"KnownTypeNames":               "TYPE006",
+	"LoneAnonymousOperation":       "TYPE007",
+	"NoFragmentCycles":             "TYPE008",
+	"NoUndefinedVariables":         "TYPE009",
+	"NoUnusedFragments":            "TYPE010",
+	"NoUnusedVariables":            "TYPE011",
+	"PossibleFragmentSpreads":      "TYPE012",
+	"ProvidedRequiredArguments":    "TYPE013",
+	"OverlappingFieldsCanBeMerged": "TYPE014",
+	"ScalarLeafs":                  "TYPE015",
+	"SingleFieldSubscriptions":     "TYPE016",
+	"UniqueArgumentNames":          "TYPE017",
+	"UniqueDirectivesPerLocation":  "TYPE018",
+	"UniqueFragmentNames":          "TYPE019",
+	"UniqueInputFieldNames":        "TYPE020",
+	"UniqueOperationNames":         "TYPE021",
+	"UniqueVariableNames":          "TYPE022",
+	"ValuesOfCorrectType":          "TYPE023",
+	"VariablesAreInputTypes":       "TYPE024",
+	"VariablesInAllowedPosition":   "TYPE025",
+
+	"NoDeprecated":           "LINT001",
+	"no-deprecated":          "LINT001",
+	"max-depth":              "LINT002",
+	"require-id":             "LINT003",
+	"require-operation-name": CodeAnonymousOperation,
+	"max-type-size":          "LINT005",
+	"max-union-width":        "LINT006",
+}
+
+// Returns the code of the problem that err reports.
+func ErrorCode(err error) string {
+	var extractionErr *ExtractionError
+	var unterminated *extract.UnterminatedTemplateError
+	var multiple *typer.MultipleDefinitionsError
+	var duplicate *typer.DuplicateNameError
+	var repeated *typer.DuplicateDocumentError
+	var overlapping *typer.OverlappingOperationError
+	var scalarsErr *ScalarsModuleError
+	var unmapped *typer.UnmappedScalarError
+	var deprecatedOnly *typer.DeprecatedOnlyTypeError
+	var packageJSON *PackageJSONError
+	var breaking typer.BreakingChange
+	var list gqlerror.List
+	var gqlErr *gqlerror.Error
+	switch {
+	case errors.As(err, &unterminated):
+		return CodeUnterminatedTemplate
+	case errors.As(err, &extractionErr):
+		return CodeUnreadableInput
+	case errors.Is(err, typer.ErrNoDefinitions):
+		return CodeNoDefinitions
+	case errors.As(err, &multiple):
+		return CodeMultipleDefinitions
+	case errors.As(err, &duplicate):
+		return CodeDuplicateName
+	case errors.As(err, &repeated):
+		return CodeDuplicateDocument
+	case errors.As(err, &overlapping):
+		return CodeOverlappingOperation
+	case errors.As(err, &scalarsErr):
+		return CodeScalarsModule
+	case errors.As(err, &unmapped):
+		return CodeUnmappedScalar
+	case errors.As(err, &breaking):
+		return CodeBreakingChange
+	case errors.As(err, &deprecatedOnly):
+		return CodeDeprecatedOnlyType
+	case errors.As(err, &packageJSON):
+		return CodePackageJSON
+	case errors.As(err, &list) && len(list) > 0:
+		return gqlErrorCode(list[0])
+	case errors.As(err, &gqlErr):
+		return gqlErrorCode(gqlErr)
+	}
+	return CodeOther
+}
+
+func gqlErrorCode(err *gqlerror.Error) string {
+	if err.Rule == "" {
+		// Only the parser reports errors without a rule.
+		return CodeSyntax
+	}
+	if code, ok := ruleCodes[err.Rule]; ok {
+		return code
+	}
+	return CodeOther
+}
@@ -0,0 +1,82 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/deref/extractgqlts/extract"
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorCode(t *testing.T) {
+	gqlErr := func(rule string) error {
+		return &typer.ValidationError{Errors: gqlerror.List{{Message: "x", Rule: rule}}}
+	}
+	assert.Equal(t, CodeUnterminatedTemplate, ErrorCode(newExtractionError("a.ts", &extract.UnterminatedTemplateError{Offset: 3})))
+	assert.Equal(t, CodeUnreadableInput, ErrorCode(newExtractionError("a.ts", io.ErrClosedPipe)))
+	assert.Equal(t, CodeSyntax, ErrorCode(gqlErr("")))
+	assert.Equal(t, "TYPE001", ErrorCode(gqlErr("FieldsOnCorrectType")))
+	assert.Equal(t, "TYPE014", ErrorCode(&DocumentError{Err: gqlErr("OverlappingFieldsCanBeMerged")}))
+	assert.Equal(t, "LINT002", ErrorCode(&gqlerror.Error{Rule: "max-depth"}))
+	assert.Equal(t, CodeNoDefinitions, ErrorCode(&DocumentError{Err: typer.ErrNoDefinitions}))
+	assert.Equal(t, CodeMultipleDefinitions, ErrorCode(&typer.MultipleDefinitionsError{Kind: "operation", Count: 2}))
+	assert.Equal(t, CodeDuplicateName, ErrorCode(&typer.DuplicateNameError{}))
+	assert.Equal(t, CodeDuplicateDocument, ErrorCode(&typer.DuplicateDocumentError{}))
+	assert.Equal(t, CodeOverlappingOperation, ErrorCode(&typer.OverlappingOperationError{}))
+	assert.Equal(t, CodeUnmappedScalar, ErrorCode(&typer.UnmappedScalarError{}))
+	assert.Equal(t, CodeDeprecatedOnlyType, ErrorCode(&typer.DeprecatedOnlyTypeError{}))
+	assert.Equal(t, CodeOther, ErrorCode(errors.New("something else")))
+	assert.Equal(t, CodeOther, ErrorCode(gqlErr("SomeFutureRule")))
+
+	for _, rule := range typer.LintRules {
+		assert.Contains(t, ruleCodes, rule.Name, "lint rule %s has no code", rule.Name)
+	}
+	seen := make(map[string]string)
+	for rule, code := range ruleCodes {
+		if rule == "NoDeprecated" {
+			// Reported by --warn-deprecated, the forerunner of no-deprecated.
+			continue
+		}
+		if other, ok := seen[code]; ok {
+			t.Errorf("rules %s and %s share code %s", rule, other, code)
+		}
+		seen[code] = rule
+	}
+}
+
+func TestDiagnosticFormats(t *testing.T) {
+	d := Diagnostic{
+		Severity: SeverityWarning,
+		File:     "a.ts",
+		Err: &DocumentError{
+			Document: Document{File: "a.ts", Line: 3, Column: 11, Source: "#graphql\nquery A {\n  nope\n}"},
+			Err: &gqlerror.Error{
+				Message:   `Cannot query field "nope" on type "Query".`,
+				Locations: []gqlerror.Location{{Line: 3, Column: 3}},
+				Rule:      "FieldsOnCorrectType",
+			},
+		},
+	}
+	assert.Equal(t, `warning[TYPE001]: a.ts:5:3: in query A: Cannot query field "nope" on type "Query".`, fmt.Sprint(d))
+
+	bs, err := json.Marshal(d)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"severity": "warning",
+		"code": "TYPE001",
+		"file": "a.ts",
+		"line": 5,
+		"column": 3,
+		"definition": "query A",
+		"message": "Cannot query field \"nope\" on type \"Query\"."
+	}`, string(bs))
+
+	bs, err = json.Marshal(Diagnostic{Severity: SeverityError, Code: CodeCache, Err: errors.New("saving cache: denied")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"severity": "error", "code": "GENR001", "message": "saving cache: denied"}`, string(bs))
+}
@@ -0,0 +1,145 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/deref/extractgqlts/extract"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Returned when the schema cannot be read or parsed.
+type SchemaLoadError struct {
+	// Empty if the schema was given in memory.
+	Path string
+	Err  error
+}
+
+func (e *SchemaLoadError) Error() string {
+	return fmt.Sprintf("loading schema: %v", e.Err)
+}
+
+func (e *SchemaLoadError) Unwrap() error {
+	return e.Err
+}
+
+// Reported when GraphQL documents cannot be extracted from an input. Is
+// io.ErrUnexpectedEOF for templates that are never closed.
+type ExtractionError struct {
+	File string
+	// Byte offset of the problem within File, or -1 if unknown.
+	Offset int
+	Err    error
+}
+
+func newExtractionError(file string, err error) *ExtractionError {
+	res := &ExtractionError{
+		File:   file,
+		Offset: -1,
+		Err:    err,
+	}
+	var unterminated *extract.UnterminatedTemplateError
+	if errors.As(err, &unterminated) {
+		res.Offset = unterminated.Offset
+	}
+	return res
+}
+
+func (e *ExtractionError) Error() string {
+	return fmt.Sprintf("extracting queries from %q: %v", e.File, e.Err)
+}
+
+func (e *ExtractionError) Unwrap() error {
+	return e.Err
+}
+
+// Reported for errors and warnings of a GraphQL document embedded in an
+// input. Unwraps to the typer's error, whose locations are relative to the
+// document; Error locates each problem within the input and names the
+// operation or fragment it is in.
+type DocumentError struct {
+	Document Document
+	Err      error
+}
+
+func (e *DocumentError) Error() string {
+	var list gqlerror.List
+	var gqlErr *gqlerror.Error
+	switch {
+	case errors.As(e.Err, &list):
+	case errors.As(e.Err, &gqlErr):
+		list = gqlerror.List{gqlErr}
+	default:
+		return fmt.Sprintf("%s:%d:%d: %v", e.Document.File, e.Document.Line, e.Document.Column, e.Err)
+	}
+	lines := make([]string, len(list))
+	for i, gqlErr := range list {
+		line, column, definition := e.locate(gqlErr)
+		var in string
+		if definition != "" {
+			in = "in " + definition + ": "
+		}
+		lines[i] = fmt.Sprintf("%s:%d:%d: %s%s", e.Document.File, line, column, in, gqlErr.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns the position within the input of a problem in the document, and
+// the operation or fragment it is in, if known.
+func (e *DocumentError) locate(gqlErr *gqlerror.Error) (line, column int, definition string) {
+	line, column = 1, 1
+	if len(gqlErr.Locations) > 0 {
+		line, column = gqlErr.Locations[0].Line, gqlErr.Locations[0].Column
+	}
+	for _, def := range documentDefinitions(e.Document.Source) {
+		if def.line < line || def.line == line && def.column <= column {
+			definition = def.name
+		}
+	}
+	if line == 1 {
+		column += e.Document.Column - 1
+	}
+	line += e.Document.Line - 1
+	return line, column, definition
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+type documentDefinition struct {
+	// Such as "query GetUser", "anonymous query", or "fragment UserFields".
+	name         string
+	line, column int
+}
+
+// Returns the definitions of a document in order of position, or none if it
+// cannot be parsed.
+func documentDefinitions(source string) []documentDefinition {
+	doc, err := parser.ParseQuery(&ast.Source{Input: source})
+	if err != nil {
+		return nil
+	}
+	var defs []documentDefinition
+	for _, op := range doc.Operations {
+		name := string(op.Operation) + " " + op.Name
+		if op.Name == "" {
+			name = "anonymous " + string(op.Operation)
+		}
+		defs = append(defs, documentDefinition{name: name, line: op.Position.Line, column: op.Position.Column})
+	}
+	for _, fragment := range doc.Fragments {
+		defs = append(defs, documentDefinition{name: "fragment " + fragment.Name, line: fragment.Position.Line, column: fragment.Position.Column})
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].line != defs[j].line {
+			return defs[i].line < defs[j].line
+		}
+		return defs[i].column < defs[j].column
+	})
+	return defs
+}
@@ -0,0 +1,406 @@
+// Package generate runs the complete extractgqlts pipeline: it expands input
+// patterns, extracts GraphQL documents from the matching files, types them
+// against a schema, and emits the result.
+package generate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/deref/extractgqlts/emit"
+	"github.com/deref/extractgqlts/extract"
+	"github.com/deref/extractgqlts/internal"
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// Errors are wrapped in the exported error types of this package and of the
+// typer package, so that callers may distinguish them with errors.As.
+//
+// Generation stops early when its context is done, between inputs and between
+// documents. It then fails with the context's error, leaving the cache and the
+// output untouched.
+
+// A Generator produces output for a set of inputs. It keeps the parsed
+// schema and the types of each input warm between calls to Generate, so
+// long-lived callers should reuse a single Generator. A Generator must not
+// be used concurrently.
+type Generator struct {
+	// Path to the GraphQL schema. Reparsed only when its contents change.
+	SchemaPath string
+
+	// An already parsed schema, used instead of SchemaPath if set. Assign a
+	// new schema, rather than modifying this one, between calls to Generate;
+	// types cached against the old schema are then discarded.
+	Schema *ast.Schema
+
+	Options typer.Options
+
+	// Defaults to TypeScript.
+	Emitter emit.Emitter
+
+	// If set, types of unchanged inputs are persisted here between processes.
+	CachePath string
+	// Identifies, along with Options.Digest, the options that generated the
+	// cached types, so that changing them invalidates the cache. Set it from
+	// whatever chooses options that Options.Digest leaves out, such as
+	// Options.Naming.
+	CacheKey string
+
+	// Number of inputs to type concurrently. Defaults to the number of CPUs.
+	Jobs int
+
+	// Extensions of the files that directory inputs contain. Defaults to
+	// DefaultExtensions.
+	Extensions []string
+
+	Hooks Hooks
+
+	// Whether to warn of custom scalars that operations use but
+	// Options.Scalars does not map.
+	WarnUnmappedScalars bool
+	// Whether to warn of schema types that operations use only through
+	// deprecated fields.
+	WarnDeprecatedOnlyTypes bool
+	// Whether to warn of operations whose selections are a subset of another
+	// operation's, which might be consolidated.
+	WarnOverlappingOperations bool
+
+	// Record documents that fail to type in the Failures of the generated
+	// types, so that emitters write placeholders for them and the rest of an
+	// application still type-checks.
+	EmitPartial bool
+
+	// Conventions by which to tag operations according to where their input
+	// files are.
+	Tags []TagConvention
+
+	schema       *ast.Schema
+	schemaDigest string
+	cache        *internal.Cache
+}
+
+// The outcome of a call to Generate.
+type Result struct {
+	Types       typer.GeneratedTypes
+	Diagnostics []Diagnostic
+}
+
+// State of a single call to Generate.
+type generation struct {
+	*Generator
+	ctx         context.Context
+	typer       typer.Typer
+	inputs      []*input
+	diagnostics []Diagnostic
+	// The suppression comments of each input, by path.
+	suppressions map[string]*Suppressions
+}
+
+type input struct {
+	path      string
+	digest    string
+	documents []Document
+}
+
+// Generates types for the inputs matching patterns and writes them to w.
+// Problems with individual inputs are reported as diagnostics, rather than
+// failing generation as a whole.
+func (g *Generator) Generate(ctx context.Context, w io.Writer, patterns []string) (*Result, error) {
+	if err := g.LoadSchema(ctx); err != nil {
+		return nil, err
+	}
+	gen := g.newGeneration(ctx)
+	inputPaths, errs := ExpandPatterns(patterns, g.Extensions)
+	for _, err := range errs {
+		gen.report(Diagnostic{Severity: SeverityError, Code: CodeInvalidPattern, Err: err})
+	}
+	for _, inputPath := range inputPaths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		gen.readInput(inputPath)
+	}
+	return gen.finish(w)
+}
+
+// An input supplied in memory, rather than read from the file system.
+type Source struct {
+	// Separated by slashes, or by the platform's separator.
+	Path string `json:"path"`
+	Text string `json:"text"`
+}
+
+// Like Generate, but types the given sources instead of reading files.
+func (g *Generator) GenerateSources(ctx context.Context, w io.Writer, sources []Source) (*Result, error) {
+	if err := g.LoadSchema(ctx); err != nil {
+		return nil, err
+	}
+	gen := g.newGeneration(ctx)
+	for _, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		gen.extractInput(filepath.ToSlash(source.Path), strings.NewReader(source.Text))
+	}
+	return gen.finish(w)
+}
+
+func (g *Generator) newGeneration(ctx context.Context) *generation {
+	gen := &generation{
+		Generator:    g,
+		ctx:          ctx,
+		suppressions: make(map[string]*Suppressions),
+	}
+	gen.typer.Schema = g.schema
+	gen.typer.Options = g.Options
+	return gen
+}
+
+func (gen *generation) finish(w io.Writer) (*Result, error) {
+	g := gen.Generator
+
+	// Shared fragments affect the types of the documents that spread them, and
+	// options the types of every document.
+	cacheDigest := internal.Digest([]byte(g.schemaDigest + gen.typer.FragmentsDigest() + g.Options.Digest() + g.CacheKey))
+	switch {
+	case g.cache != nil:
+		g.cache.Renew(cacheDigest)
+	case g.CachePath != "":
+		var err error
+		g.cache, err = internal.LoadCache(g.CachePath, cacheDigest)
+		if err != nil {
+			return nil, fmt.Errorf("loading cache: %w", err)
+		}
+	default:
+		g.cache = internal.NewCache(cacheDigest)
+	}
+
+	if err := gen.visitInputs(); err != nil {
+		return nil, err
+	}
+	if g.EmitPartial {
+		gen.typer.GeneratedTypes.Failures = failedDocuments(gen.typer.GeneratedTypes, gen.diagnostics)
+	}
+	for _, err := range typer.DuplicateNames(gen.typer.GeneratedTypes) {
+		gen.report(Diagnostic{Severity: SeverityError, File: err.Locations[0].File, Err: err})
+	}
+	for _, err := range typer.DuplicateDocuments(gen.typer.GeneratedTypes) {
+		gen.report(Diagnostic{Severity: SeverityWarning, File: err.Locations[0].File, Err: err})
+	}
+	if g.WarnUnmappedScalars {
+		errs, err := typer.UnmappedScalars(g.schema, gen.typer.GeneratedTypes, g.Options.Scalars)
+		if err != nil {
+			return nil, fmt.Errorf("finding unmapped scalars: %w", err)
+		}
+		for _, err := range errs {
+			gen.report(Diagnostic{Severity: SeverityWarning, File: err.Locations[0].File, Err: err})
+		}
+	}
+	if g.WarnDeprecatedOnlyTypes {
+		errs, err := typer.DeprecatedOnlyTypes(g.schema, gen.typer.GeneratedTypes)
+		if err != nil {
+			return nil, fmt.Errorf("finding types used only through deprecated fields: %w", err)
+		}
+		for _, err := range errs {
+			gen.report(Diagnostic{Severity: SeverityWarning, File: err.Locations[0].File, Err: err})
+		}
+	}
+
+	if g.WarnOverlappingOperations {
+		errs, err := typer.OverlappingOperations(g.schema, gen.typer.GeneratedTypes)
+		if err != nil {
+			return nil, fmt.Errorf("finding overlapping operations: %w", err)
+		}
+		for _, err := range errs {
+			gen.report(Diagnostic{Severity: SeverityWarning, File: err.Location.File, Err: err})
+		}
+	}
+
+	for _, err := range TagOperations(&gen.typer.GeneratedTypes, g.Tags) {
+		gen.report(Diagnostic{Severity: SeverityError, File: err.Path, Err: err})
+	}
+
+	if g.CachePath != "" {
+		if err := g.cache.Save(g.CachePath); err != nil {
+			gen.report(Diagnostic{Severity: SeverityError, Code: CodeCache, Err: fmt.Errorf("saving cache: %w", err)})
+		}
+	} else {
+		g.cache.Prune()
+	}
+
+	emitter := g.Emitter
+	if emitter == nil {
+		emitter = &emit.TypeScript{}
+	}
+	if err := emitter.Emit(w, gen.typer.GeneratedTypes); err != nil {
+		return nil, fmt.Errorf("writing output: %w", err)
+	}
+	return &Result{
+		Types:       gen.typer.GeneratedTypes,
+		Diagnostics: gen.diagnostics,
+	}, nil
+}
+
+func (g *Generator) jobs() int {
+	if g.Jobs > 0 {
+		return g.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// Parses the schema, unless it is unchanged since it was last loaded.
+// Generate calls this itself; call it beforehand to report problems with the
+// schema early.
+func (g *Generator) LoadSchema(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.loadSchema(); err != nil {
+		return &SchemaLoadError{Path: g.SchemaPath, Err: err}
+	}
+	return nil
+}
+
+// Returns a Typer for the current schema and Options, for typing individual
+// documents outside of Generate.
+func (g *Generator) Typer(ctx context.Context) (*typer.Typer, error) {
+	if err := g.LoadSchema(ctx); err != nil {
+		return nil, err
+	}
+	return &typer.Typer{
+		Schema:  g.schema,
+		Options: g.Options,
+	}, nil
+}
+
+func (g *Generator) loadSchema() error {
+	if g.Schema != nil {
+		if g.Schema != g.schema {
+			var buf bytes.Buffer
+			formatter.NewFormatter(&buf).FormatSchema(g.Schema)
+			g.schema = g.Schema
+			g.schemaDigest = internal.Digest(buf.Bytes())
+		}
+		return nil
+	}
+	schemaBuf, err := ioutil.ReadFile(g.SchemaPath)
+	if err != nil {
+		return fmt.Errorf("reading: %w", err)
+	}
+	digest := internal.Digest(schemaBuf)
+	if digest == g.schemaDigest {
+		return nil
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{
+		Name:  g.SchemaPath,
+		Input: string(schemaBuf),
+	})
+	if gqlErr != nil {
+		return gqlErr
+	}
+	g.schema = schema
+	g.schemaDigest = digest
+	return nil
+}
+
+// Reads and extracts documents from an input, preparing them for typing.
+func (gen *generation) readInput(inputPath string) {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		gen.report(Diagnostic{Severity: SeverityError, Code: CodeUnreadableInput, File: inputPath, Err: fmt.Errorf("reading %q: %w", inputPath, err)})
+		return
+	}
+	defer f.Close()
+	// Stream the input, rather than reading it whole, in case it is huge.
+	gen.extractInput(inputPath, f)
+}
+
+func (gen *generation) extractInput(inputPath string, r io.Reader) {
+	digester, digest := internal.NewDigester()
+	var scanner suppressionScanner
+	extracted, err := extract.DocumentsFromReader(io.TeeReader(&contextReader{gen.ctx, r}, io.MultiWriter(digester, &scanner)))
+	if err != nil {
+		if gen.ctx.Err() != nil {
+			// Cancellation is reported by the caller, not as a diagnostic.
+			return
+		}
+		gen.report(Diagnostic{Severity: SeverityError, File: inputPath, Err: newExtractionError(inputPath, err)})
+		return
+	}
+	in := &input{
+		path:   inputPath,
+		digest: digest(),
+	}
+	gen.suppressions[inputPath] = scanner.finish()
+	for _, found := range extracted {
+		doc := Document{
+			File:   inputPath,
+			Offset: found.Offset,
+			Line:   found.Line,
+			Column: found.Column,
+			Source: found.Text,
+		}
+		if gen.Hooks.OnDocumentExtracted != nil {
+			gen.Hooks.OnDocumentExtracted(doc)
+		}
+		gen.typer.PrepareString(inputPath, doc.Source)
+		in.documents = append(in.documents, doc)
+	}
+	gen.inputs = append(gen.inputs, in)
+}
+
+// Fails reads once a context is done, so that extraction from a huge input
+// stops promptly.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// Returns the documents of the error diagnostics, in order, each with its
+// problems, except those typed nonetheless, as when repeated elsewhere.
+func failedDocuments(types typer.GeneratedTypes, diagnostics []Diagnostic) []typer.FailedDocument {
+	var res []typer.FailedDocument
+	// Index in res of each failed document, by source text.
+	index := make(map[string]int)
+	for _, entry := range types.QueryMap {
+		index[entry.Query] = -1
+	}
+	for _, d := range diagnostics {
+		var docErr *DocumentError
+		if d.Severity != SeverityError || !errors.As(d.Err, &docErr) {
+			continue
+		}
+		doc := docErr.Document
+		if i, ok := index[doc.Source]; ok {
+			if i >= 0 {
+				res[i].Error += "\n" + docErr.Error()
+			}
+			continue
+		}
+		index[doc.Source] = len(res)
+		res = append(res, typer.FailedDocument{
+			Query:    doc.Source,
+			Location: typer.Location{File: doc.File, Line: doc.Line, Column: doc.Column},
+			Error:    docErr.Error(),
+		})
+	}
+	return res
+}
@@ -0,0 +1,267 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/deref/extractgqlts/emit"
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testSchema = `
+type Query {
+	hello: String
+}
+`
+
+// Writes files, keyed by name, to a temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestHooks(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"schema.gql": testSchema,
+		"a.ts":       "const a = `#graphql\nquery A { hello }`;",
+		"b.ts":       "const b = `#graphql\nquery B { goodbye }`;",
+	})
+
+	var extracted, typed []Document
+	var diagnostics []Diagnostic
+	g := &Generator{
+		SchemaPath: filepath.Join(dir, "schema.gql"),
+		Emitter:    &emit.JSON{},
+		Jobs:       2,
+		Hooks: Hooks{
+			OnDocumentExtracted: func(doc Document) {
+				extracted = append(extracted, doc)
+			},
+			OnOperationTyped: func(doc Document, types typer.GeneratedTypes) {
+				typed = append(typed, doc)
+				if doc.File == filepath.Join(dir, "a.ts") {
+					assert.Len(t, types.QueryMap, 1)
+				}
+			},
+			OnDiagnostic: func(d Diagnostic) {
+				diagnostics = append(diagnostics, d)
+			},
+		},
+	}
+	var out bytes.Buffer
+	res, err := g.Generate(context.Background(), &out, []string{filepath.Join(dir, "*.ts")})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	expected := []Document{
+		{File: filepath.Join(dir, "a.ts"), Offset: 11, Line: 1, Column: 12, Source: "#graphql\nquery A { hello }"},
+		{File: filepath.Join(dir, "b.ts"), Offset: 11, Line: 1, Column: 12, Source: "#graphql\nquery B { goodbye }"},
+	}
+	assert.Equal(t, expected, extracted)
+	assert.Equal(t, expected, typed)
+	assert.Equal(t, res.Diagnostics, diagnostics)
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, SeverityWarning, diagnostics[0].Severity)
+		assert.Equal(t, filepath.Join(dir, "b.ts"), diagnostics[0].File)
+	}
+	if assert.Len(t, res.Types.QueryMap, 2) {
+		entry := res.Types.QueryMap[0]
+		assert.Equal(t, typer.OperationQuery, entry.Operation)
+		assert.Equal(t, "A", entry.Name)
+		assert.Equal(t, typer.Location{File: filepath.Join(dir, "a.ts"), Line: 2, Column: 1}, entry.Location)
+	}
+	assert.NotEmpty(t, out.String())
+
+	// Unchanged inputs without diagnostics are not retyped.
+	typed = nil
+	_, err = g.Generate(context.Background(), &out, []string{filepath.Join(dir, "*.ts")})
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected[1:], typed)
+	}
+}
+
+func TestInMemorySchema(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.ts": "const a = `#graphql\nquery A { hello }`;",
+	})
+	g := &Generator{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Input: testSchema}),
+	}
+	var out bytes.Buffer
+	res, err := g.Generate(context.Background(), &out, []string{filepath.Join(dir, "a.ts")})
+	if assert.NoError(t, err) {
+		assert.Empty(t, res.Diagnostics)
+		assert.Contains(t, out.String(), "export type Query_A_Data = { __typename: \"Query\"; hello: (string | null); };")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"schema.gql": "type Query {",
+		"a.ts":       "const a = `#graphql\nquery A { hello }`;",
+		"b.ts":       "const b = `#graphql\nquery B {`;",
+		"c.ts":       "const c = `#graphql\n{ hello }",
+	})
+	g := &Generator{
+		SchemaPath: filepath.Join(dir, "schema.gql"),
+	}
+	var out bytes.Buffer
+	_, err := g.Generate(context.Background(), &out, []string{filepath.Join(dir, "*.ts")})
+	var schemaErr *SchemaLoadError
+	if assert.ErrorAs(t, err, &schemaErr) {
+		assert.Equal(t, filepath.Join(dir, "schema.gql"), schemaErr.Path)
+	}
+
+	g = &Generator{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Input: testSchema}),
+	}
+	res, err := g.Generate(context.Background(), &out, []string{filepath.Join(dir, "*.ts")})
+	if !assert.NoError(t, err) || !assert.Len(t, res.Diagnostics, 2) {
+		return
+	}
+	var validationErr *typer.ValidationError
+	assert.ErrorAs(t, res.Diagnostics[1].Err, &validationErr)
+	var extractionErr *ExtractionError
+	if assert.ErrorAs(t, res.Diagnostics[0].Err, &extractionErr) {
+		assert.Equal(t, filepath.Join(dir, "c.ts"), extractionErr.File)
+		assert.Equal(t, 11, extractionErr.Offset)
+		assert.ErrorIs(t, extractionErr, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.ts": "const a = `#graphql\nquery A { hello }`;\nconst b = `#graphql\nquery B { hello ...F }\nfragment F on Query {\n  goodbye\n}`;\nconst c = `#graphql\n  { hi }`;",
+	})
+	g := &Generator{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Input: testSchema}),
+	}
+	var out bytes.Buffer
+	res, err := g.Generate(context.Background(), &out, []string{filepath.Join(dir, "*.ts")})
+	if !assert.NoError(t, err) || !assert.NotEmpty(t, res.Diagnostics) {
+		return
+	}
+	path := filepath.Join(dir, "a.ts")
+	assert.EqualError(t, res.Diagnostics[0].Err, path+`:6:3: in fragment F: Cannot query field "goodbye" on type "Query".`)
+	last := res.Diagnostics[len(res.Diagnostics)-1]
+	assert.EqualError(t, last.Err, path+`:9:5: in anonymous query: Cannot query field "hi" on type "Query".`)
+	var docErr *DocumentError
+	if assert.ErrorAs(t, last.Err, &docErr) {
+		assert.Equal(t, 8, docErr.Document.Line)
+	}
+}
+
+func TestEmitPartial(t *testing.T) {
+	g := &Generator{
+		Schema:      gqlparser.MustLoadSchema(&ast.Source{Input: testSchema}),
+		EmitPartial: true,
+	}
+	var out bytes.Buffer
+	res, err := g.GenerateSources(context.Background(), &out, []Source{
+		{Path: "a.ts", Text: "`#graphql\nquery A { hello }`;\n`#graphql\nquery B { hello(x: 1, y: 2) }`;"},
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, res.Types.Failures, 1) {
+		return
+	}
+	assert.Equal(t, typer.FailedDocument{
+		Query:    "#graphql\nquery B { hello(x: 1, y: 2) }",
+		Location: typer.Location{File: "a.ts", Line: 3, Column: 2},
+		Error:    "a.ts:4:11: in query B: Unknown argument \"x\" on field \"Query.hello\".\na.ts:4:11: in query B: Unknown argument \"y\" on field \"Query.hello\".",
+	}, res.Types.Failures[0])
+	assert.Contains(t, out.String(), `  "#graphql\nquery A { hello }": { data: Query_A_Data; variables: Query_A_Variables; };
+  // Placeholders for documents that failed to type.
+  // ERROR: a.ts:4:11: in query B: Unknown argument "x" on field "Query.hello".
+  // ERROR: a.ts:4:11: in query B: Unknown argument "y" on field "Query.hello".
+  "#graphql\nquery B { hello(x: 1, y: 2) }": { data: any; variables: any; };
+}
+`)
+}
+
+func TestDuplicateNames(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.ts": "const a = `#graphql\nquery A { hello }`;",
+		"b.ts": "const b = `#graphql\nquery A { __typename }`;",
+		"c.ts": "const c = `#graphql\nquery A { hello }`;",
+	})
+	g := &Generator{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Input: testSchema}),
+	}
+	var out bytes.Buffer
+	res, err := g.Generate(context.Background(), &out, []string{filepath.Join(dir, "*.ts")})
+	if !assert.NoError(t, err) || !assert.Len(t, res.Diagnostics, 2) {
+		return
+	}
+	d := res.Diagnostics[0]
+	assert.Equal(t, SeverityError, d.Severity)
+	assert.Equal(t, filepath.Join(dir, "a.ts"), d.File)
+	assert.EqualError(t, d.Err, filepath.Join(dir, "a.ts")+`:2:1: operation "A" is also defined at `+filepath.Join(dir, "b.ts")+":2:1")
+	d = res.Diagnostics[1]
+	assert.Equal(t, SeverityWarning, d.Severity)
+	assert.EqualError(t, d.Err, filepath.Join(dir, "a.ts")+`:2:1: the document of query A is repeated verbatim at `+filepath.Join(dir, "c.ts")+":2:1")
+}
+
+func TestCancel(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.ts": "const a = `#graphql\nquery A { hello }`;",
+	})
+	cachePath := filepath.Join(dir, "cache.json")
+	g := &Generator{
+		Schema:    gqlparser.MustLoadSchema(&ast.Source{Input: testSchema}),
+		CachePath: cachePath,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	_, err := g.Generate(ctx, &out, []string{filepath.Join(dir, "*.ts")})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, out.String())
+	assert.NoFileExists(t, cachePath)
+
+	_, err = g.GenerateSources(ctx, &out, []Source{{Path: "a.ts", Text: "`#graphql\n{ hello }`"}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCacheOptions(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.ts": "const a = `#graphql\nquery A { hello }`;",
+	})
+	cachePath := filepath.Join(dir, "cache.json")
+	generate := func(g *Generator) string {
+		g.Schema = gqlparser.MustLoadSchema(&ast.Source{Input: testSchema})
+		g.CachePath = cachePath
+		var out bytes.Buffer
+		_, err := g.Generate(context.Background(), &out, []string{filepath.Join(dir, "*.ts")})
+		assert.NoError(t, err)
+		return out.String()
+	}
+
+	assert.Contains(t, generate(&Generator{}), "hello: (string | null); }")
+	assert.FileExists(t, cachePath)
+
+	// Each generator stands for another process, sharing only the cache.
+	assert.Contains(t, generate(&Generator{Options: typer.Options{
+		Nullability: typer.NullabilityNullOrUndefined,
+	}}), "hello: (string | null | undefined); }")
+	assert.Contains(t, generate(&Generator{Options: typer.Options{
+		Scalars: map[string]string{"String": "Text"},
+	}}), "hello: (Text | null); }")
+	assert.Contains(t, generate(&Generator{
+		Options: typer.Options{
+			Naming: func(kind, name, part string) string { return name + kind + part },
+		},
+		CacheKey: "naming=suffixed",
+	}), "export type AQueryData =")
+}
@@ -0,0 +1,99 @@
+// Package generatetest guards generated output against unintended changes,
+// such as those from upgrading extractgqlts, by comparing it to golden files.
+//
+//	func TestGenerated(t *testing.T) {
+//		g := &generate.Generator{SchemaPath: "testdata/schema.gql"}
+//		generatetest.Check(t, g, "testdata/src", "testdata/types.generated.ts")
+//	}
+//
+// Run tests with -update-generated to rewrite golden files.
+package generatetest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deref/extractgqlts/generate"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var update = flag.Bool("update-generated", false, "rewrite golden files of generated types instead of comparing against them")
+
+// Generates output with g for every file in dir, and compares it to the
+// golden file. Diagnostics and differences fail t, with differences shown as
+// a unified diff. The golden file is not an input, even if it is within dir.
+//
+// Inputs are named by their slash-separated paths relative to dir, so that
+// output does not depend on where the tests are run.
+func Check(t testing.TB, g *generate.Generator, dir, golden string) {
+	t.Helper()
+	sources, err := readSources(dir, golden)
+	if err != nil {
+		t.Fatalf("reading fixtures: %v", err)
+	}
+	var out bytes.Buffer
+	res, err := g.GenerateSources(context.Background(), &out, sources)
+	if err != nil {
+		t.Fatalf("generating: %v", err)
+	}
+	for _, d := range res.Diagnostics {
+		t.Errorf("%v", d)
+	}
+
+	if *update {
+		if err := ioutil.WriteFile(golden, out.Bytes(), 0644); err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run with -update-generated to create it)", err)
+	}
+	if bytes.Equal(expected, out.Bytes()) {
+		return
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(out.String()),
+		FromFile: golden,
+		ToFile:   "generated",
+		Context:  3,
+	})
+	t.Errorf("generated output differs from %s (run with -update-generated to accept):\n%s", golden, diff)
+}
+
+func readSources(dir, golden string) ([]generate.Source, error) {
+	goldenAbs, err := filepath.Abs(golden)
+	if err != nil {
+		return nil, err
+	}
+	var sources []generate.Source
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		if abs, err := filepath.Abs(path); err != nil || abs == goldenAbs {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, generate.Source{
+			Path: filepath.ToSlash(rel),
+			Text: string(bs),
+		})
+		return nil
+	})
+	return sources, err
+}
@@ -0,0 +1,91 @@
+package generatetest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/deref/extractgqlts/emit"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// Runs Check on its own goroutine, so that it may stop early, and returns
+// its failures.
+func check(t *testing.T, g *generate.Generator, dir, golden string) []string {
+	rec := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Check(rec, g, dir, golden)
+	}()
+	<-done
+	return rec.failures
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "src", "a.ts")
+	golden := filepath.Join(dir, "src", "types.json")
+	if err := ioutil.WriteFile(input, []byte("`#graphql\nquery A { hello }`"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	target, err := emit.NewTarget("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &generate.Generator{
+		Schema:  gqlparser.MustLoadSchema(&ast.Source{Input: "type Query { hello: String }"}),
+		Emitter: target,
+	}
+
+	failures := check(t, g, filepath.Join(dir, "src"), golden)
+	if assert.Len(t, failures, 1) {
+		assert.Contains(t, failures[0], "-update-generated")
+	}
+
+	*update = true
+	failures = check(t, g, filepath.Join(dir, "src"), golden)
+	*update = false
+	assert.Empty(t, failures)
+	bs, err := ioutil.ReadFile(golden)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(bs), `"file": "a.ts"`)
+	}
+
+	assert.Empty(t, check(t, g, filepath.Join(dir, "src"), golden))
+
+	if err := ioutil.WriteFile(input, []byte("`#graphql\nquery B { hello }`"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	failures = check(t, g, filepath.Join(dir, "src"), golden)
+	if assert.Len(t, failures, 1) {
+		assert.Contains(t, failures[0], "-      \"name\": \"A\",")
+		assert.Contains(t, failures[0], "+      \"name\": \"B\",")
+	}
+}
@@ -0,0 +1,94 @@
+package generate
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// Extensions of the files that directory inputs contain, unless others are
+// given.
+var DefaultExtensions = []string{".ts", ".tsx", ".svelte"}
+
+// Expands input patterns concurrently, returning the deduplicated union of
+// their matches in sorted order. Errors are returned in pattern order.
+//
+// A pattern naming a directory matches the files within it, recursively, that
+// end with one of extensions, or DefaultExtensions if none are given, but for
+// those in node_modules and hidden directories.
+//
+// Patterns may separate directories with slashes or backslashes on every
+// platform, so that scripts written on Windows work elsewhere and vice versa;
+// metacharacters are escaped with brackets, as in [*], rather than
+// backslashes. Matches are slash-separated on every platform, so that
+// diagnostics, output, and cache keys are too.
+func ExpandPatterns(patterns []string, extensions []string) (paths []string, errs []error) {
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+	matches := make([][]string, len(patterns))
+	patternErrs := make([]error, len(patterns))
+	var wg sync.WaitGroup
+	for i, pattern := range patterns {
+		wg.Add(1)
+		go func(i int, pattern string) {
+			defer wg.Done()
+			pattern = strings.ReplaceAll(pattern, `\`, "/")
+			if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+				matches[i], patternErrs[i] = walkInputs(pattern, extensions)
+				return
+			}
+			matches[i], patternErrs[i] = doublestar.Glob(pattern)
+		}(i, pattern)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for i, pattern := range patterns {
+		if err := patternErrs[i]; err != nil {
+			errs = append(errs, fmt.Errorf("expanding filepath pattern %q: %w", pattern, err))
+			continue
+		}
+		for _, path := range matches[i] {
+			path = filepath.ToSlash(filepath.Clean(path))
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, errs
+}
+
+// Returns the files in dir, recursively, that end with one of extensions.
+func walkInputs(dir string, extensions []string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != dir && (name == "node_modules" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, ext := range extensions {
+			if strings.HasSuffix(name, ext) {
+				paths = append(paths, path)
+				break
+			}
+		}
+		return nil
+	})
+	return paths, err
+}
@@ -0,0 +1,41 @@
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPatterns(t *testing.T) {
+	dir := filepath.ToSlash(t.TempDir())
+	for _, name := range []string{"src/a.ts", "src/lib/b.ts", "src/lib/c.js"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, nil, 0644))
+	}
+	expected := []string{dir + "/src/a.ts", dir + "/src/lib/b.ts"}
+	for _, pattern := range []string{dir + "/src/**/*.ts", dir + `/src\**\*.ts`} {
+		paths, errs := ExpandPatterns([]string{pattern}, nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, expected, paths, "pattern: %s", pattern)
+	}
+}
+
+func TestExpandPatternsDirectory(t *testing.T) {
+	dir := filepath.ToSlash(t.TempDir())
+	for _, name := range []string{"src/a.ts", "src/b.svelte", "src/c.js", "src/lib/d.tsx", "src/node_modules/e.ts", "src/.cache/f.ts"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, nil, 0644))
+	}
+	paths, errs := ExpandPatterns([]string{dir + "/src"}, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{dir + "/src/a.ts", dir + "/src/b.svelte", dir + "/src/lib/d.tsx"}, paths)
+
+	paths, errs = ExpandPatterns([]string{dir + `\src\`, dir + "/src/a.ts"}, []string{".js", ".ts"})
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{dir + "/src/a.ts", dir + "/src/c.js"}, paths)
+}
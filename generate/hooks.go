@@ -0,0 +1,112 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Hooks observe a Generator as it runs. Each hook is optional and is called
+// on the goroutine that called Generate, in input order, regardless of how
+// many inputs are typed concurrently.
+type Hooks struct {
+	// Called for each GraphQL document found in an input file.
+	OnDocumentExtracted func(doc Document)
+
+	// Called after a document is typed, with the types it contributed.
+	// Documents of inputs whose types are cached are not retyped, so this is
+	// not called for them.
+	OnOperationTyped func(doc Document, types typer.GeneratedTypes)
+
+	// Called for each diagnostic as it is reported.
+	OnDiagnostic func(d Diagnostic)
+}
+
+// A GraphQL document embedded in an input file.
+type Document struct {
+	File string
+	// Byte offset of the document within File.
+	Offset int
+	// 1-based line and byte column of Offset.
+	Line, Column int
+	Source       string
+}
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// A problem encountered while generating.
+type Diagnostic struct {
+	Severity Severity
+	// Identifies the kind of problem. See ErrorCode. Reported diagnostics
+	// always have one; if empty, String and MarshalJSON derive it from Err.
+	Code string
+	// The input the diagnostic concerns, if any.
+	File string
+	Err  error
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s[%s]: %v", d.Severity, d.code(), d.Err)
+}
+
+func (d Diagnostic) code() string {
+	if d.Code != "" {
+		return d.Code
+	}
+	return ErrorCode(d.Err)
+}
+
+type diagnosticJSON struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	File     string   `json:"file,omitempty"`
+	// 1-based, or omitted if unknown.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Such as "query GetUser", for problems within a document.
+	Definition string `json:"definition,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Encodes the diagnostic as an object with its severity, code, file, message,
+// and, for problems within documents, the line, column, and definition.
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	res := diagnosticJSON{
+		Severity: d.Severity,
+		Code:     d.code(),
+		File:     d.File,
+	}
+	var docErr *DocumentError
+	var gqlErr *gqlerror.Error
+	if errors.As(d.Err, &docErr) && errors.As(docErr.Err, &gqlErr) {
+		res.Line, res.Column, res.Definition = docErr.locate(gqlErr)
+		res.Message = gqlErr.Message
+	} else if d.Err != nil {
+		res.Message = d.Err.Error()
+	}
+	return json.Marshal(res)
+}
+
+// Reports d unless it is a warning silenced by a comment in its input.
+// Returns whether it was reported.
+func (gen *generation) report(d Diagnostic) bool {
+	if d.Code == "" {
+		d.Code = ErrorCode(d.Err)
+	}
+	if d.Severity == SeverityWarning && gen.suppressions[d.File].Suppresses(d.Code, diagnosticLine(d)) {
+		return false
+	}
+	gen.diagnostics = append(gen.diagnostics, d)
+	if gen.Hooks.OnDiagnostic != nil {
+		gen.Hooks.OnDiagnostic(d)
+	}
+	return true
+}
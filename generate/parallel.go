@@ -0,0 +1,127 @@
+package generate
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Inputs are typed concurrently, each by a forked Typer, and their results
+// are merged in input order so that output is independent of scheduling.
+
+type inputResult struct {
+	types       typer.GeneratedTypes
+	documents   []typedDocument // Empty if the types were cached.
+	diagnostics []Diagnostic
+}
+
+type typedDocument struct {
+	doc   Document
+	types typer.GeneratedTypes
+}
+
+func (gen *generation) visitInputs() error {
+	results := make([]*inputResult, len(gen.inputs))
+	var pending []int
+	for i, in := range gen.inputs {
+		if types, ok := gen.cache.Lookup(in.path, in.digest); ok {
+			results[i] = &inputResult{types: types}
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	workers := gen.jobs()
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fork := gen.typer.Fork()
+			for i := range indexes {
+				results[i] = typeInput(gen.ctx, fork, gen.inputs[i])
+			}
+		}()
+	}
+	for _, i := range pending {
+		if gen.ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if err := gen.ctx.Err(); err != nil {
+		return err
+	}
+
+	for i, res := range results {
+		gen.typer.GeneratedTypes.Append(res.types)
+		if gen.Hooks.OnOperationTyped != nil {
+			for _, typed := range res.documents {
+				gen.Hooks.OnOperationTyped(typed.doc, typed.types)
+			}
+		}
+		reported := false
+		for _, diagnostic := range res.diagnostics {
+			if gen.report(diagnostic) {
+				reported = true
+			}
+		}
+		// Inputs with diagnostics are retyped on every run so that their
+		// diagnostics continue to be reported.
+		if !reported {
+			in := gen.inputs[i]
+			gen.cache.Store(in.path, in.digest, res.types)
+		}
+	}
+	return nil
+}
+
+func typeInput(ctx context.Context, t *typer.Typer, in *input) *inputResult {
+	res := &inputResult{}
+	t.GeneratedTypes = typer.GeneratedTypes{}
+	for _, doc := range in.documents {
+		if ctx.Err() != nil {
+			// The caller discards results once cancelled.
+			break
+		}
+		mark := t.GeneratedTypes
+		_, warnings, err := t.VisitString(in.path, doc.Source)
+		types := t.GeneratedTypes.Since(mark)
+		// Locations are relative to the document, not its input file.
+		for i := range types.QueryMap {
+			loc := &types.QueryMap[i].Location
+			if loc.Line == 1 {
+				loc.Column += doc.Column - 1
+			}
+			loc.Line += doc.Line - 1
+		}
+		res.documents = append(res.documents, typedDocument{
+			doc:   doc,
+			types: types,
+		})
+		for _, warning := range warnings {
+			res.diagnostics = append(res.diagnostics, Diagnostic{Severity: SeverityWarning, File: in.path, Err: &DocumentError{Document: doc, Err: warning}})
+		}
+		// Each problem is a diagnostic of its own, with its own code.
+		var validationErr *typer.ValidationError
+		if errors.As(err, &validationErr) && len(validationErr.Errors) > 1 {
+			for _, gqlErr := range validationErr.Errors {
+				single := &typer.ValidationError{Errors: gqlerror.List{gqlErr}}
+				res.diagnostics = append(res.diagnostics, Diagnostic{Severity: SeverityError, File: in.path, Err: &DocumentError{Document: doc, Err: single}})
+			}
+		} else if err != nil {
+			res.diagnostics = append(res.diagnostics, Diagnostic{Severity: SeverityError, File: in.path, Err: &DocumentError{Document: doc, Err: err}})
+		}
+	}
+	res.types = t.GeneratedTypes
+	return res
+}
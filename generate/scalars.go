@@ -0,0 +1,152 @@
+package generate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Reported when the module that generated types import custom scalars from
+// is missing, or does not export some of them.
+type ScalarsModuleError struct {
+	Module string
+	// Empty if the module was not found.
+	Path string
+	// Scalars not exported, in order. Every scalar if the module was not found.
+	Missing []string
+}
+
+func (e *ScalarsModuleError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("scalars module %q not found; it must export %s", e.Module, strings.Join(e.Missing, ", "))
+	}
+	return fmt.Sprintf("scalars module %q (%s) does not export %s; declare them, such as with `export type %s = string;`",
+		e.Module, e.Path, strings.Join(e.Missing, ", "), e.Missing[0])
+}
+
+// Extensions tried, in order, when resolving a module specifier without one.
+var scalarsModuleExtensions = []string{".ts", ".tsx", ".d.ts", ".mts", ".d.mts", ".js", ".js.flow", ".mjs"}
+
+// Verifies that the module, a relative specifier such as ./scalars resolved
+// against dir, exists and exports each of the scalars. Exports are found by
+// scanning the source, so the check is best-effort: modules that re-export
+// everything from outside the project are assumed to export every scalar.
+// Other specifiers, such as package names, are not checked.
+func VerifyScalarsModule(dir, module string, scalars []string) error {
+	if len(scalars) == 0 || !strings.HasPrefix(module, "./") && !strings.HasPrefix(module, "../") {
+		return nil
+	}
+	unique := make(map[string]bool)
+	for _, scalar := range scalars {
+		unique[scalar] = true
+	}
+	path := resolveModule(filepath.Join(dir, filepath.FromSlash(module)))
+	exports := make(map[string]bool)
+	if path != "" {
+		var complete bool
+		var err error
+		exports, complete, err = moduleExports(path, make(map[string]bool))
+		if err != nil {
+			return err
+		}
+		if !complete {
+			return nil
+		}
+	}
+	var missing []string
+	for scalar := range unique {
+		if !exports[scalar] {
+			missing = append(missing, scalar)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return &ScalarsModuleError{Module: module, Path: path, Missing: missing}
+}
+
+// Returns the file that a module path refers to, or "" if there is none.
+func resolveModule(path string) string {
+	candidates := []string{path}
+	// An explicit .js extension may name a .ts file, as with --esm.
+	if trimmed := strings.TrimSuffix(path, ".js"); trimmed != path {
+		candidates = append(candidates, trimmed)
+	}
+	var withExtensions []string
+	for _, candidate := range candidates {
+		for _, ext := range scalarsModuleExtensions {
+			withExtensions = append(withExtensions, candidate+ext)
+		}
+		for _, ext := range scalarsModuleExtensions {
+			withExtensions = append(withExtensions, filepath.Join(candidate, "index"+ext))
+		}
+	}
+	for _, candidate := range append(candidates, withExtensions...) {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+var (
+	exportDeclarationPattern = regexp.MustCompile(`(?m)^\s*export\s+(?:declare\s+)?(?:opaque\s+)?(?:type|interface|class|enum|const|let|var|function|abstract\s+class)\s+([A-Za-z_$][\w$]*)`)
+	exportListPattern        = regexp.MustCompile(`export\s+(?:type\s+)?\{([^}]*)\}`)
+	exportAllPattern         = regexp.MustCompile(`export\s+(?:type\s+)?\*\s+from\s+['"]([^'"]+)['"]`)
+)
+
+// Returns the names a module exports, following relative re-exports of
+// everything, and whether they are known to be all of them.
+func moduleExports(path string, visited map[string]bool) (exports map[string]bool, complete bool, err error) {
+	exports = make(map[string]bool)
+	if visited[path] {
+		return exports, true, nil
+	}
+	visited[path] = true
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading scalars module: %w", err)
+	}
+	src := string(bs)
+	for _, match := range exportDeclarationPattern.FindAllStringSubmatch(src, -1) {
+		exports[match[1]] = true
+	}
+	for _, match := range exportListPattern.FindAllStringSubmatch(src, -1) {
+		for _, item := range strings.Split(match[1], ",") {
+			fields := strings.Fields(item)
+			if len(fields) > 0 && fields[0] == "type" {
+				fields = fields[1:]
+			}
+			if len(fields) > 0 {
+				exports[fields[len(fields)-1]] = true
+			}
+		}
+	}
+	complete = true
+	for _, match := range exportAllPattern.FindAllStringSubmatch(src, -1) {
+		specifier := match[1]
+		if !strings.HasPrefix(specifier, "./") && !strings.HasPrefix(specifier, "../") {
+			complete = false
+			continue
+		}
+		target := resolveModule(filepath.Join(filepath.Dir(path), filepath.FromSlash(specifier)))
+		if target == "" {
+			complete = false
+			continue
+		}
+		more, ok, err := moduleExports(target, visited)
+		if err != nil {
+			return nil, false, err
+		}
+		complete = complete && ok
+		for name := range more {
+			exports[name] = true
+		}
+	}
+	return exports, complete, nil
+}
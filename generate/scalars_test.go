@@ -0,0 +1,32 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyScalarsModule(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"scalars.ts": "export type Instant = Date;\nexport * from './more';\n",
+		"more.ts":    "type Json = unknown;\nexport { type Json, URL as Url };\n",
+		"open.ts":    "export type Instant = Date;\nexport * from 'scalars-package';\n",
+	})
+
+	assert.NoError(t, VerifyScalarsModule(dir, "./scalars", []string{"Instant", "Json", "Url", "Instant"}))
+	assert.NoError(t, VerifyScalarsModule(dir, "./scalars.js", []string{"Instant"}))
+	assert.NoError(t, VerifyScalarsModule(dir, "./open", []string{"Instant", "Unknowable"}))
+	assert.NoError(t, VerifyScalarsModule(dir, "scalars-package", []string{"Instant"}))
+	assert.NoError(t, VerifyScalarsModule(dir, "./missing", nil))
+
+	err := VerifyScalarsModule(dir, "./scalars", []string{"Upload", "Instant", "Decimal"})
+	assert.Equal(t, &ScalarsModuleError{
+		Module:  "./scalars",
+		Path:    filepath.Join(dir, "scalars.ts"),
+		Missing: []string{"Decimal", "Upload"},
+	}, err)
+
+	err = VerifyScalarsModule(dir, "./missing", []string{"Instant"})
+	assert.EqualError(t, err, `scalars module "./missing" not found; it must export Instant`)
+}
@@ -0,0 +1,161 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Warnings may be silenced by comments in the inputs that they concern:
+//
+//	# extractgqlts-disable-next-line TYPE001, LINT003
+//
+// within a GraphQL document silences warnings on the following line, and
+//
+//	// extractgqlts-disable LINT004
+//
+// in the input silences warnings anywhere in it. Each comment is on a line of
+// its own, and without codes, silences warnings of every kind. Errors cannot
+// be silenced, since the documents they concern are not typed.
+type Suppressions struct {
+	suppressions []suppression
+}
+
+type suppression struct {
+	// The line of the warnings silenced, or 0 for the whole input.
+	line int
+	// Empty to silence warnings of every kind.
+	codes []string
+}
+
+const (
+	disableNextLineDirective = "# extractgqlts-disable-next-line"
+	disableDirective         = "// extractgqlts-disable"
+)
+
+// Returns the suppression comments of an input's text.
+func ScanSuppressions(text string) *Suppressions {
+	s := &Suppressions{}
+	for i, line := range strings.Split(text, "\n") {
+		s.scanLine(i+1, []byte(line))
+	}
+	return s
+}
+
+func (s *Suppressions) scanLine(n int, line []byte) {
+	line = bytes.TrimSpace(line)
+	var sup suppression
+	var rest []byte
+	switch {
+	case bytes.HasPrefix(line, []byte(disableNextLineDirective)):
+		sup.line = n + 1
+		rest = line[len(disableNextLineDirective):]
+	case bytes.HasPrefix(line, []byte(disableDirective)):
+		rest = line[len(disableDirective):]
+	default:
+		return
+	}
+	if len(rest) > 0 && rest[0] != ' ' && rest[0] != '\t' {
+		// Some other word, such as extractgqlts-disabled.
+		return
+	}
+	for _, code := range strings.FieldsFunc(string(rest), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		sup.codes = append(sup.codes, code)
+	}
+	s.suppressions = append(s.suppressions, sup)
+}
+
+// Whether a warning of the given code on the given 1-based line is silenced.
+// Warnings whose line is unknown, given as 0, are silenced only throughout
+// the input.
+func (s *Suppressions) Suppresses(code string, line int) bool {
+	if s == nil {
+		return false
+	}
+	for _, sup := range s.suppressions {
+		if sup.line != 0 && sup.line != line {
+			continue
+		}
+		if len(sup.codes) == 0 {
+			return true
+		}
+		for _, c := range sup.codes {
+			if c == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Scans the lines written to it for suppression comments, so that an input
+// may be scanned as it is streamed.
+type suppressionScanner struct {
+	suppressions Suppressions
+	// The line being written, truncated, since comments are short.
+	line    []byte
+	lineNum int
+}
+
+const maxSuppressionLine = 1024
+
+func (w *suppressionScanner) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		chunk := p
+		if i >= 0 {
+			chunk = p[:i]
+		}
+		if room := maxSuppressionLine - len(w.line); room > 0 {
+			if len(chunk) > room {
+				chunk = chunk[:room]
+			}
+			w.line = append(w.line, chunk...)
+		}
+		if i < 0 {
+			break
+		}
+		w.endLine()
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (w *suppressionScanner) endLine() {
+	w.lineNum++
+	w.suppressions.scanLine(w.lineNum, w.line)
+	w.line = w.line[:0]
+}
+
+// Scans the last line, which has no line break, and returns the comments.
+func (w *suppressionScanner) finish() *Suppressions {
+	w.endLine()
+	return &w.suppressions
+}
+
+// Returns the line of the input that a diagnostic concerns, or 0 if unknown.
+func diagnosticLine(d Diagnostic) int {
+	var docErr *DocumentError
+	var gqlErr *gqlerror.Error
+	var duplicate *typer.DuplicateDocumentError
+	var unmapped *typer.UnmappedScalarError
+	var deprecatedOnly *typer.DeprecatedOnlyTypeError
+	switch {
+	case errors.As(d.Err, &docErr) && errors.As(docErr.Err, &gqlErr):
+		line, _, _ := docErr.locate(gqlErr)
+		return line
+	case errors.As(d.Err, &duplicate):
+		return duplicate.Locations[0].Line
+	case errors.As(d.Err, &unmapped):
+		return unmapped.Locations[0].Line
+	case errors.As(d.Err, &deprecatedOnly):
+		return deprecatedOnly.Locations[0].Line
+	}
+	return 0
+}
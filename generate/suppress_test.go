@@ -0,0 +1,47 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestScanSuppressions(t *testing.T) {
+	s := ScanSuppressions("// extractgqlts-disable LINT004\nconst q = `#graphql\n  # extractgqlts-disable-next-line TYPE001, LINT003\n  { a }`;\n// extractgqlts-disabled\n")
+	assert.True(t, s.Suppresses("LINT004", 0))
+	assert.True(t, s.Suppresses("LINT004", 7))
+	assert.True(t, s.Suppresses("TYPE001", 4))
+	assert.True(t, s.Suppresses("LINT003", 4))
+	assert.False(t, s.Suppresses("TYPE001", 5))
+	assert.False(t, s.Suppresses("LINT002", 4))
+
+	s = ScanSuppressions("# extractgqlts-disable-next-line\n")
+	assert.True(t, s.Suppresses("LINT002", 2))
+	assert.False(t, s.Suppresses("LINT002", 3))
+
+	var scanner suppressionScanner
+	_, _ = scanner.Write([]byte("# extractgqlts-disable-"))
+	_, _ = scanner.Write([]byte("next-line TYPE001\n// extractgqlts-disable"))
+	s = scanner.finish()
+	assert.True(t, s.Suppresses("TYPE001", 2))
+	assert.True(t, s.Suppresses("TYPE014", 9))
+}
+
+func TestSuppressedDiagnostics(t *testing.T) {
+	g := &Generator{
+		Schema: gqlparser.MustLoadSchema(&ast.Source{Input: testSchema}),
+	}
+	var out bytes.Buffer
+	res, err := g.GenerateSources(context.Background(), &out, []Source{
+		{Path: "a.ts", Text: "const a = `#graphql\nquery A {\n  # extractgqlts-disable-next-line TYPE001\n  goodbye\n  farewell\n}`;"},
+		{Path: "b.ts", Text: "// extractgqlts-disable\nconst b = `#graphql\nquery B { goodbye }`;"},
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, res.Diagnostics, 1) {
+		return
+	}
+	assert.EqualError(t, res.Diagnostics[0].Err, `a.ts:5:3: in query A: Cannot query field "farewell" on type "Query".`)
+}
@@ -0,0 +1,145 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// A convention by which operations are tagged, such as with their feature
+// area or owning team, according to where their input files are, so that
+// analytics and safelists can be segmented without annotating documents.
+type TagConvention struct {
+	Tag string
+	// If set, the value is the name of the directory directly under Dir, which
+	// is slash-separated, that contains the input file.
+	Dir string
+	// Otherwise, the value is this field of the nearest package.json above the
+	// input file, as a dotted path such as author.name.
+	PackageField string
+}
+
+// Reported when a package.json by which operations are tagged cannot be read
+// or parsed.
+type PackageJSONError struct {
+	Path string
+	Err  error
+}
+
+func (e *PackageJSONError) Error() string {
+	return fmt.Sprintf("reading %s for tags: %v", e.Path, e.Err)
+}
+
+func (e *PackageJSONError) Unwrap() error {
+	return e.Err
+}
+
+// Sets the Tags of each entry of types by the conventions, which are applied
+// in order, later ones overriding earlier ones of the same tag. Entries of
+// inputs that a convention does not apply to are not given its tag.
+func TagOperations(types *typer.GeneratedTypes, conventions []TagConvention) []*PackageJSONError {
+	if len(conventions) == 0 {
+		return nil
+	}
+	t := &tagger{packages: make(map[string]*packageJSON)}
+	for i := range types.QueryMap {
+		entry := &types.QueryMap[i]
+		tags := make(map[string]string)
+		for _, convention := range conventions {
+			if value, ok := t.tag(convention, entry.Location.File); ok {
+				tags[convention.Tag] = value
+			}
+		}
+		if len(tags) > 0 {
+			entry.Tags = tags
+		} else {
+			entry.Tags = nil
+		}
+	}
+	return t.errs
+}
+
+type tagger struct {
+	// Nearest package.json, by directory. Nil if there is none.
+	packages map[string]*packageJSON
+	errs     []*PackageJSONError
+}
+
+type packageJSON struct {
+	path   string
+	fields map[string]interface{}
+}
+
+func (t *tagger) tag(convention TagConvention, file string) (string, bool) {
+	if convention.Dir != "" {
+		return dirTag(convention.Dir, file)
+	}
+	dir, err := filepath.Abs(filepath.Dir(filepath.FromSlash(file)))
+	if err != nil {
+		return "", false
+	}
+	pkg := t.nearestPackage(dir)
+	if pkg == nil {
+		return "", false
+	}
+	var value interface{} = pkg.fields
+	for _, key := range strings.Split(convention.PackageField, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		value = object[key]
+	}
+	switch value := value.(type) {
+	case string:
+		return value, value != ""
+	case float64, bool:
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+func dirTag(dir, file string) (string, bool) {
+	dir, rel := path.Clean(dir), path.Clean(file)
+	if dir != "." {
+		if !strings.HasPrefix(rel, dir+"/") {
+			return "", false
+		}
+		rel = rel[len(dir)+1:]
+	}
+	slash := strings.IndexByte(rel, '/')
+	if slash <= 0 {
+		return "", false
+	}
+	return rel[:slash], true
+}
+
+func (t *tagger) nearestPackage(dir string) *packageJSON {
+	if pkg, ok := t.packages[dir]; ok {
+		return pkg
+	}
+	var pkg *packageJSON
+	p := filepath.Join(dir, "package.json")
+	bs, err := ioutil.ReadFile(p)
+	switch {
+	case err == nil:
+		pkg = &packageJSON{path: filepath.ToSlash(p)}
+		if err := json.Unmarshal(bs, &pkg.fields); err != nil {
+			t.errs = append(t.errs, &PackageJSONError{Path: pkg.path, Err: err})
+		}
+	case !os.IsNotExist(err):
+		t.errs = append(t.errs, &PackageJSONError{Path: filepath.ToSlash(p), Err: err})
+	default:
+		if parent := filepath.Dir(dir); parent != dir {
+			pkg = t.nearestPackage(parent)
+		}
+	}
+	t.packages[dir] = pkg
+	return pkg
+}
@@ -0,0 +1,52 @@
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagOperations(t *testing.T) {
+	dir := filepath.ToSlash(t.TempDir())
+	files := map[string]string{
+		"package.json":                       `{"name": "app", "author": {"name": "Web"}}`,
+		"src/features/billing/package.json":  `{"name": "@app/billing", "author": {"name": "Payments"}}`,
+		"src/features/billing/ui/invoice.ts": "",
+		"src/features/users/profile.ts":      "",
+		"src/index.ts":                       "",
+		"broken/package.json":                `{`,
+		"broken/index.ts":                    "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	types := typer.GeneratedTypes{
+		QueryMap: []typer.QueryType{
+			{Name: "Invoice", Location: typer.Location{File: dir + "/src/features/billing/ui/invoice.ts"}},
+			{Name: "Profile", Location: typer.Location{File: dir + "/src/features/users/profile.ts"}},
+			{Name: "Index", Location: typer.Location{File: dir + "/src/index.ts"}},
+			{Name: "Broken", Location: typer.Location{File: dir + "/broken/index.ts"}},
+		},
+	}
+	errs := TagOperations(&types, []TagConvention{
+		{Tag: "area", Dir: dir + "/src/features"},
+		{Tag: "package", PackageField: "name"},
+		{Tag: "owner", PackageField: "author.name"},
+	})
+	assert.Equal(t, map[string]string{"area": "billing", "package": "@app/billing", "owner": "Payments"}, types.QueryMap[0].Tags)
+	assert.Equal(t, map[string]string{"area": "users", "package": "app", "owner": "Web"}, types.QueryMap[1].Tags)
+	assert.Equal(t, map[string]string{"package": "app", "owner": "Web"}, types.QueryMap[2].Tags)
+	assert.Nil(t, types.QueryMap[3].Tags)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, dir+"/broken/package.json", errs[0].Path)
+		assert.Equal(t, CodePackageJSON, ErrorCode(errs[0]))
+	}
+
+	assert.Equal(t, `{ data: unknown; variables: unknown; tags: { area: "billing"; owner: "Payments"; package: "@app/billing"; }; }`, types.QueryMap[0].Render())
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Cache records the types contributed by each input file, keyed by a digest
+// of the file's contents, so that unchanged inputs need not be retyped on
+// subsequent runs.
+type Cache struct {
+	SchemaDigest string                 `json:"schemaDigest"`
+	Inputs       map[string]*CacheEntry `json:"inputs"`
+
+	visited map[string]bool
+}
+
+type CacheEntry struct {
+	Digest string               `json:"digest"`
+	Types  typer.GeneratedTypes `json:"types"`
+}
+
+func Digest(bs []byte) string {
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns a writer that digests everything written to it, and a function
+// returning the same result as Digest would for those bytes.
+func NewDigester() (w io.Writer, sum func() string) {
+	h := sha256.New()
+	return h, func() string {
+		return hex.EncodeToString(h.Sum(nil))
+	}
+}
+
+func NewCache(schemaDigest string) *Cache {
+	return &Cache{
+		SchemaDigest: schemaDigest,
+		Inputs:       make(map[string]*CacheEntry),
+		visited:      make(map[string]bool),
+	}
+}
+
+// Loads a cache from path. A missing file or a cache built against a
+// different schema yields an empty cache.
+func LoadCache(path string, schemaDigest string) (*Cache, error) {
+	c := NewCache(schemaDigest)
+	bs, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored Cache
+	if err := json.Unmarshal(bs, &stored); err != nil {
+		// A corrupt cache is not fatal; it is simply rebuilt.
+		return c, nil
+	}
+	if stored.SchemaDigest == schemaDigest && stored.Inputs != nil {
+		c.Inputs = stored.Inputs
+	}
+	return c, nil
+}
+
+// Returns the previously generated types for an input, if its digest is
+// unchanged.
+func (c *Cache) Lookup(inputPath, digest string) (types typer.GeneratedTypes, ok bool) {
+	c.visited[inputPath] = true
+	entry := c.Inputs[inputPath]
+	if entry == nil || entry.Digest != digest {
+		return typer.GeneratedTypes{}, false
+	}
+	return entry.Types, true
+}
+
+func (c *Cache) Store(inputPath, digest string, types typer.GeneratedTypes) {
+	c.visited[inputPath] = true
+	c.Inputs[inputPath] = &CacheEntry{
+		Digest: digest,
+		Types:  types,
+	}
+}
+
+// Prepares an in-memory cache for another run. Entries are discarded if the
+// schema digest has changed.
+func (c *Cache) Renew(schemaDigest string) {
+	if c.SchemaDigest != schemaDigest {
+		c.SchemaDigest = schemaDigest
+		c.Inputs = make(map[string]*CacheEntry)
+	}
+	c.visited = make(map[string]bool)
+}
+
+// Drops entries for inputs that were not visited during this run.
+func (c *Cache) Prune() {
+	for inputPath := range c.Inputs {
+		if !c.visited[inputPath] {
+			delete(c.Inputs, inputPath)
+		}
+	}
+}
+
+// Writes the pruned cache to path.
+func (c *Cache) Save(path string) error {
+	c.Prune()
+	bs, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bs, 0644)
+}
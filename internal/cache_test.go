@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/deref/extractgqlts/typer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	types := typer.GeneratedTypes{
+		Declarations: []typer.Declaration{
+			{
+				Name: "Query_Q_Data",
+				Kind: typer.DeclarationData,
+				Type: typer.ArrayType{Elem: typer.IntersectionType{Members: []typer.Type{
+					typer.RawType{Text: "Date"},
+					typer.NamedType{Name: "Fragment_F_Data"},
+				}}},
+			},
+		},
+		QueryMap: []typer.QueryType{
+			{
+				Query:     "{ hello }",
+				Operation: typer.OperationQuery,
+				Location:  typer.Location{File: "a.ts", Line: 1, Column: 1},
+				Data: typer.ObjectType{
+					Fields: []typer.Field{
+						{Name: "hello", Type: typer.NullableType{Type: typer.NamedType{Name: "string"}}},
+						{Name: "kind", Type: typer.UnionType{Members: []typer.Type{
+							typer.StringLiteralType{Value: "A"},
+							typer.StringLiteralType{Value: "B"},
+						}}},
+					},
+				},
+				Variables: typer.ObjectType{},
+			},
+		},
+	}
+
+	cache, err := LoadCache(path, "schema1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, ok := cache.Lookup("a.ts", "digest1")
+	assert.False(t, ok)
+	cache.Store("a.ts", "digest1", types)
+	cache.Store("b.ts", "digest2", types)
+	if !assert.NoError(t, cache.Save(path)) {
+		return
+	}
+
+	// Unchanged and changed inputs.
+	cache, err = LoadCache(path, "schema1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	actual, ok := cache.Lookup("a.ts", "digest1")
+	assert.True(t, ok)
+	assert.Equal(t, types, actual)
+	_, ok = cache.Lookup("b.ts", "changed")
+	assert.False(t, ok)
+
+	// Schema change invalidates everything.
+	cache, err = LoadCache(path, "schema2")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, ok = cache.Lookup("a.ts", "digest1")
+	assert.False(t, ok)
+}
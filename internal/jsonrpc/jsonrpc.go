@@ -0,0 +1,170 @@
+// Package jsonrpc implements JSON-RPC 2.0 over a stream, framed with
+// Content-Length headers as in the Language Server Protocol.
+package jsonrpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Standard error codes.
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	InternalError  = -32603
+)
+
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func Errorf(code int, message string, v ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(message, v...)}
+}
+
+// A request, or a notification if ID is empty.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *Error          `json:"error"`
+}
+
+type Conn struct {
+	r  *bufio.Reader
+	mu sync.Mutex // Serializes writes.
+	w  io.Writer
+}
+
+func NewConn(r io.Reader, w io.Writer) *Conn {
+	return &Conn{
+		r: bufio.NewReader(r),
+		w: w,
+	}
+}
+
+// Reads the next request. Returns io.EOF when the stream ends cleanly.
+func (c *Conn) Read() (*Request, error) {
+	header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return &req, Errorf(ParseError, "%v", err)
+	}
+	return &req, nil
+}
+
+// Writes a message, framed with a Content-Length header.
+func (c *Conn) Write(v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+// Sends a notification, which receives no response.
+func (c *Conn) Notify(method string, params interface{}) error {
+	bs, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.Write(Request{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  bs,
+	})
+}
+
+// Handles a request, returning its result. Errors other than *Error are
+// reported to the client as internal errors.
+type Handler func(method string, params json.RawMessage) (interface{}, error)
+
+// Serves requests sequentially until the stream ends.
+func (c *Conn) Serve(handle Handler) error {
+	for {
+		req, err := c.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			if err := c.Write(errorResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpcErr}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		result, err := handle(req.Method, req.Params)
+		if len(req.ID) == 0 {
+			continue
+		}
+		if err != nil {
+			if !errors.As(err, &rpcErr) {
+				rpcErr = &Error{Code: InternalError, Message: err.Error()}
+			}
+			err = c.Write(errorResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr})
+		} else {
+			err = c.Write(response{JSONRPC: "2.0", ID: req.ID, Result: result})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Decodes params into v, reporting failure as InvalidParams.
+func DecodeParams(params json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return Errorf(InvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func frame(body string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestServe(t *testing.T) {
+	in := frame(`{"jsonrpc":"2.0","id":1,"method":"echo","params":{"x":1}}`) +
+		frame(`{"jsonrpc":"2.0","method":"notify"}`) +
+		frame(`{"jsonrpc":"2.0","id":"two","method":"missing"}`) +
+		frame(`{"jsonrpc":"2.0","id":3,"method":"fail"}`)
+	var out bytes.Buffer
+	var notified bool
+	conn := NewConn(strings.NewReader(in), &out)
+	err := conn.Serve(func(method string, params json.RawMessage) (interface{}, error) {
+		switch method {
+		case "echo":
+			return params, nil
+		case "notify":
+			notified = true
+			return nil, nil
+		case "fail":
+			return nil, errors.New("failed")
+		default:
+			return nil, Errorf(MethodNotFound, "unknown method: %s", method)
+		}
+	})
+	assert.NoError(t, err)
+	assert.True(t, notified)
+	assert.Equal(t, frame(`{"jsonrpc":"2.0","id":1,"result":{"x":1}}`)+
+		frame(`{"jsonrpc":"2.0","id":"two","error":{"code":-32601,"message":"unknown method: missing"}}`)+
+		frame(`{"jsonrpc":"2.0","id":3,"error":{"code":-32603,"message":"failed"}}`), out.String())
+}
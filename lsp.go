@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/deref/extractgqlts/extract"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/internal/jsonrpc"
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// The lsp command is a Language Server for GraphQL embedded in source files.
+// It publishes diagnostics for open files as they change, and shows the
+// generated type of the document under the cursor on hover. Shared fragments
+// are found in the files matching the input patterns, which are rescanned
+// whenever a file is saved.
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"` // In UTF-16 code units.
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code,omitempty"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+const (
+	lspSeverityError   = 1
+	lspSeverityWarning = 2
+)
+
+type lspTextDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type lspTextDocumentParams struct {
+	TextDocument   lspTextDocument `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+	Position lspPosition `json:"position"`
+}
+
+type lspServer struct {
+	conn      *jsonrpc.Conn
+	generator *generate.Generator
+	patterns  []string
+	// Text of open files, by URI.
+	open map[string]string
+	// Fragment definitions found in project files, by absolute, slash-separated
+	// path.
+	projectFragments map[string][]string
+	shutdown         bool
+}
+
+func serveLSP() error {
+	if schemaPath == "" {
+		return fmt.Errorf("usage: extractgqlts lsp --schema=/path/to/schema.gql <input ...>")
+	}
+	g, err := newGenerator()
+	if err != nil {
+		return err
+	}
+	g.Options.WarnDeprecated = true
+	s := &lspServer{
+		conn:      jsonrpc.NewConn(os.Stdin, os.Stdout),
+		generator: g,
+		patterns:  inputArgs(),
+		open:      make(map[string]string),
+	}
+	s.scanProject()
+	return s.conn.Serve(s.handle)
+}
+
+func (s *lspServer) handle(method string, params json.RawMessage) (interface{}, error) {
+	var doc lspTextDocumentParams
+	if strings.HasPrefix(method, "textDocument/") {
+		if err := jsonrpc.DecodeParams(params, &doc); err != nil {
+			return nil, err
+		}
+	}
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": map[string]interface{}{
+					"openClose": true,
+					"change":    1, // Full text.
+					"save":      true,
+				},
+				"hoverProvider": true,
+			},
+			"serverInfo": map[string]interface{}{
+				"name": "extractgqlts",
+			},
+		}, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "exit":
+		if s.shutdown {
+			os.Exit(0)
+		}
+		os.Exit(1)
+	case "textDocument/didOpen":
+		s.open[doc.TextDocument.URI] = doc.TextDocument.Text
+		return nil, s.publishDiagnostics(doc.TextDocument.URI)
+	case "textDocument/didChange":
+		if n := len(doc.ContentChanges); n > 0 {
+			s.open[doc.TextDocument.URI] = doc.ContentChanges[n-1].Text
+		}
+		return nil, s.publishDiagnostics(doc.TextDocument.URI)
+	case "textDocument/didSave":
+		s.scanProject()
+		for uri := range s.open {
+			if err := s.publishDiagnostics(uri); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		delete(s.open, doc.TextDocument.URI)
+		return nil, s.conn.Notify("textDocument/publishDiagnostics", map[string]interface{}{
+			"uri":         doc.TextDocument.URI,
+			"diagnostics": []lspDiagnostic{},
+		})
+	case "textDocument/hover":
+		return s.hover(doc.TextDocument.URI, doc.Position)
+	}
+	if len(method) > 0 && method[0] == '$' {
+		// Optional notifications, such as $/cancelRequest.
+		return nil, nil
+	}
+	return nil, jsonrpc.Errorf(jsonrpc.MethodNotFound, "unknown method: %s", method)
+}
+
+// Collects fragment definitions from files matching the input patterns.
+func (s *lspServer) scanProject() {
+	s.projectFragments = make(map[string][]string)
+	paths, _ := generate.ExpandPatterns(s.patterns, s.generator.Extensions)
+	for _, path := range paths {
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		documents, err := extractDocuments(string(bs))
+		if err != nil {
+			continue
+		}
+		path = absPath(path)
+		for _, doc := range documents {
+			if strings.Contains(doc.Text, "fragment") {
+				s.projectFragments[path] = append(s.projectFragments[path], doc.Text)
+			}
+		}
+	}
+}
+
+// Returns path as absolute and slash-separated, as paths of URIs are, so that
+// patterns relative to the working directory match open files.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.ToSlash(path)
+}
+
+// Returns a typer prepared with the project's shared fragments, except those
+// of path, which the caller prepares from the open file's current text.
+func (s *lspServer) typer(path string) (*typer.Typer, error) {
+	t, err := s.generator.Typer(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for fragmentPath, documents := range s.projectFragments {
+		if fragmentPath == path {
+			continue
+		}
+		for _, doc := range documents {
+			t.PrepareString(fragmentPath, doc)
+		}
+	}
+	return t, nil
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	// On Windows, file:///C:/src/a.ts is C:/src/a.ts.
+	if len(u.Path) >= 3 && u.Path[0] == '/' && u.Path[2] == ':' {
+		return absPath(u.Path[1:])
+	}
+	return absPath(u.Path)
+}
+
+func (s *lspServer) publishDiagnostics(uri string) error {
+	text := s.open[uri]
+	path := uriToPath(uri)
+	diagnostics := []lspDiagnostic{}
+	suppressions := generate.ScanSuppressions(text)
+	report := func(offset int, severity int, code string, err error) {
+		if severity == lspSeverityWarning && suppressions.Suppresses(code, offsetToPosition(text, offset).Line+1) {
+			return
+		}
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range:    wordRange(text, offset),
+			Severity: severity,
+			Code:     code,
+			Source:   "extractgqlts",
+			Message:  err.Error(),
+		})
+	}
+
+	documents, err := extractDocuments(text)
+	if err != nil {
+		report(len(text), lspSeverityError, generate.ErrorCode(err), err)
+	}
+	t, err := s.typer(path)
+	if err != nil {
+		// The schema is broken, so there is nothing to check documents against.
+		report(0, lspSeverityError, generate.ErrorCode(err), err)
+		documents = nil
+	}
+	for _, doc := range documents {
+		t.PrepareString(path, doc.Text)
+	}
+	for _, doc := range documents {
+		_, warnings, err := t.VisitString(path, doc.Text)
+		for _, warning := range warnings {
+			for _, gqlErr := range gqlErrors(warning) {
+				report(documentOffset(doc, gqlErr), lspSeverityWarning, errorCode(warning, gqlErr), messageOnly(gqlErr))
+			}
+		}
+		if err != nil {
+			for _, gqlErr := range gqlErrors(err) {
+				report(documentOffset(doc, gqlErr), lspSeverityError, errorCode(err, gqlErr), messageOnly(gqlErr))
+			}
+		}
+	}
+
+	return s.conn.Notify("textDocument/publishDiagnostics", map[string]interface{}{
+		"uri":         uri,
+		"diagnostics": diagnostics,
+	})
+}
+
+// Splits an error into its GraphQL errors. Other errors are wrapped as a
+// single GraphQL error without a location.
+func gqlErrors(err error) gqlerror.List {
+	var list gqlerror.List
+	if errors.As(err, &list) {
+		return list
+	}
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		return gqlerror.List{gqlErr}
+	}
+	return gqlerror.List{{Message: err.Error()}}
+}
+
+// Returns the code of one of the GraphQL errors that gqlErrors split err into.
+func errorCode(err error, gqlErr *gqlerror.Error) string {
+	var list gqlerror.List
+	var single *gqlerror.Error
+	if errors.As(err, &list) || errors.As(err, &single) {
+		return generate.ErrorCode(gqlErr)
+	}
+	return generate.ErrorCode(err)
+}
+
+// Omits the file and line prefix, since editors show the location.
+func messageOnly(err *gqlerror.Error) error {
+	return errors.New(err.Message)
+}
+
+// Returns the offset within the file of an error's location in a document.
+func documentOffset(doc rpcDocument, err *gqlerror.Error) int {
+	if len(err.Locations) == 0 {
+		return doc.Offset
+	}
+	loc := err.Locations[0]
+	offset := 0
+	for line := 1; line < loc.Line; line++ {
+		i := strings.IndexByte(doc.Text[offset:], '\n')
+		if i < 0 {
+			break
+		}
+		offset += i + 1
+	}
+	offset += loc.Column - 1
+	if offset > len(doc.Text) {
+		offset = len(doc.Text)
+	}
+	return doc.Offset + offset
+}
+
+// Returns the range of the identifier at offset, or an empty range if there
+// is none.
+func wordRange(text string, offset int) lspRange {
+	end := offset
+	for end < len(text) && extract.IsNameByte(text[end]) {
+		end++
+	}
+	return lspRange{
+		Start: offsetToPosition(text, offset),
+		End:   offsetToPosition(text, end),
+	}
+}
+
+func offsetToPosition(text string, offset int) lspPosition {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	var pos lspPosition
+	for _, r := range text[:offset] {
+		if r == '\n' {
+			pos.Line++
+			pos.Character = 0
+			continue
+		}
+		pos.Character += utf16.RuneLen(r)
+	}
+	return pos
+}
+
+func positionToOffset(text string, pos lspPosition) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		i := strings.IndexByte(text[offset:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		offset += i + 1
+	}
+	for character := 0; character < pos.Character && offset < len(text) && text[offset] != '\n'; {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		character += utf16.RuneLen(r)
+		offset += size
+	}
+	return offset
+}
+
+func (s *lspServer) hover(uri string, pos lspPosition) (interface{}, error) {
+	text := s.open[uri]
+	path := uriToPath(uri)
+	t, err := s.typer(path)
+	if err != nil {
+		return nil, err
+	}
+	res, err := hoverDocument(t, path, text, positionToOffset(text, pos))
+	if err != nil || res == nil {
+		return nil, err
+	}
+	var content strings.Builder
+	content.WriteString("```typescript\n")
+	for _, decl := range res.Declarations {
+		content.WriteString(decl)
+		content.WriteString("\n")
+	}
+	fmt.Fprintf(&content, "type Document = %s;\n", res.Type)
+	content.WriteString("```")
+	return map[string]interface{}{
+		"contents": map[string]interface{}{
+			"kind":  "markdown",
+			"value": content.String(),
+		},
+		"range": lspRange{
+			Start: offsetToPosition(text, res.Document.Offset),
+			End:   offsetToPosition(text, res.Document.Offset+len(res.Document.Text)),
+		},
+	}, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/internal/jsonrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLSPRelativePatterns(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	const schema = "type Query { me: User }\ntype User { id: ID! name: String }\n"
+	const onDisk = "const f = `#graphql\nfragment UserFields on User { name }`;\nconst q = `#graphql\nquery Me { me { ...UserFields } }`;\n"
+	assert.NoError(t, os.Mkdir("src", 0755))
+	assert.NoError(t, ioutil.WriteFile("schema.graphql", []byte(schema), 0644))
+	assert.NoError(t, ioutil.WriteFile("src/a.ts", []byte(onDisk), 0644))
+
+	var out bytes.Buffer
+	s := &lspServer{
+		conn:      jsonrpc.NewConn(&bytes.Buffer{}, &out),
+		generator: &generate.Generator{SchemaPath: "schema.graphql"},
+		patterns:  []string{"src"},
+		open:      make(map[string]string),
+	}
+	s.scanProject()
+
+	// Renaming the fragment in the editor, but not on disk, leaves the spread
+	// of its old name undefined.
+	uri := "file://" + filepath.ToSlash(filepath.Join(dir, "src", "a.ts"))
+	s.open[uri] = "const f = `#graphql\nfragment UserFieldz on User { name }`;\nconst q = `#graphql\nquery Me { me { ...UserFields } }`;\n"
+	if assert.NoError(t, s.publishDiagnostics(uri)) {
+		assert.Contains(t, out.String(), `Unknown fragment \"UserFields\"`)
+		assert.Contains(t, out.String(), `"code":"TYPE005"`)
+	}
+
+	out.Reset()
+	s.open[uri] = onDisk
+	if assert.NoError(t, s.publishDiagnostics(uri)) {
+		assert.Contains(t, out.String(), `"diagnostics":[]`)
+	}
+}
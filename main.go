@@ -1,138 +1,875 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
-
+	"os/signal"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
 
-	"github.com/bmatcuk/doublestar"
-	"github.com/deref/extractgqlts/internal"
-	"github.com/vektah/gqlparser/v2"
-	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/deref/extractgqlts/emit"
+	"github.com/deref/extractgqlts/extract"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/typer"
 )
 
 var schemaPath string
+var cachePath string
+var shareShapes bool
+var warnDeprecated bool
+var warnUnmappedScalars bool
+var warnDeprecatedOnlyTypes bool
+var warnOverlappingOperations bool
+var listenPath string
+var configPath string
+var jobs int
+var extensions string
+var timeout time.Duration
+var outputPath string
+var watch bool
+var nullability string
+var typename string
+var unknownDirectives string
+var naming string
+var augmentModule string
+var scalarMappings stringsFlag
+var lintRules stringsFlag
+var transforms stringsFlag
+var diagnosticsFormat string
+var target string
+var templatePath string
+var validators string
+var pluginCommands stringsFlag
+var pluginOut string
+var clientPath string
+var clientStyle string
+var clientTypesModule string
+var scalarsModule string
+var packageSpecs stringsFlag
+var verifyScalarsDir string
+var esm bool
+var groupQueryTypes bool
+var optimize bool
+var variableDefaults bool
+var sections string
+var emitPartial bool
+var tagSpecs stringsFlag
+var splitDir string
+var mocksPath string
+var fixturesPath string
+var goPath string
+var goPackage string
+var trustedDocumentsDir string
+var apolloManifestPath string
+var registryPath string
+var documentHash string
+var documentIDPrefix string
+var reportPath string
+var docsPath string
+var coveragePath string
+var usagesPath string
+var overFetchPath string
+var schemaMapsPath string
+var changesPath string
+var documentsPath string
+var documentsDir string
+var eslintDocumentsDir string
+var collectionPath string
+var collectionFormat string
+var collectionEndpoint string
 
 func init() {
+	flag.StringVar(&configPath, "config", "", "path to a config file of defaults for flags and inputs (defaults to "+defaultConfigPath+", if present)")
 	flag.StringVar(&schemaPath, "schema", "", "path to graphql schema")
-	flag.Parse()
+	flag.StringVar(&cachePath, "cache", "", "path to cache file for incremental regeneration")
+	flag.BoolVar(&shareShapes, "share-shapes", false, "declare repeated nested object types once as named types")
+	flag.BoolVar(&warnDeprecated, "warn-deprecated", false, "warn about uses of deprecated fields and arguments")
+	flag.BoolVar(&warnUnmappedScalars, "warn-unmapped-scalars", false, "warn about custom scalars that operations use but no --scalar maps")
+	flag.BoolVar(&warnDeprecatedOnlyTypes, "warn-deprecated-only-types", false, "warn about schema types that operations use only through deprecated fields")
+	flag.BoolVar(&warnOverlappingOperations, "warn-overlapping-operations", false, "warn about operations that select only fields another operation of the same root fields also selects")
+	flag.StringVar(&nullability, "nullability", "null", "representation of nullable types: null or null-or-undefined")
+	flag.StringVar(&typename, "typename", "always", "when to include __typename fields: always or selected")
+	flag.StringVar(&unknownDirectives, "unknown-directives", "error", "how to treat directives the schema does not declare: error, warning, or allow")
+	flag.StringVar(&augmentModule, "augment", "", "module whose QueryTypes interface to augment instead of exporting QueryTypes")
+	flag.StringVar(&naming, "naming", "default", "naming of declarations: default (Query_GetUser_Data) or graphql-codegen (GetUserQuery)")
+	flag.StringVar(&target, "target", "typescript", "output format: "+strings.Join(emit.TargetNames(), " or "))
+	flag.StringVar(&templatePath, "template", "", "path to a Go text/template to emit instead of a --target")
+	flag.StringVar(&validators, "validators", "", "emit runtime validators with types derived from them: "+strings.Join(emit.ValidatorNames(), " or "))
+	flag.Var(&pluginCommands, "plugin", "command of a plugin to run on the generation result (repeatable)")
+	flag.StringVar(&pluginOut, "plugin-out", ".", "directory to write plugin output files to")
+	flag.StringVar(&clientPath, "client", "", "path to write a typed client for named operations to, such as client.generated.ts")
+	flag.StringVar(&clientStyle, "client-style", "fetch", "style of --client: "+strings.Join(emit.ClientNames(), " or "))
+	flag.StringVar(&clientTypesModule, "client-types-module", "./types.generated", "module from which --client, --mocks, and --fixtures import the generated types")
+	flag.StringVar(&scalarsModule, "scalars-module", "./scalars", "module from which custom scalars are imported")
+	flag.Var(&packageSpecs, "package", "write the types of inputs matching a pattern to their own output, as Pattern=Output[,ScalarsModule] (repeatable)")
+	flag.StringVar(&verifyScalarsDir, "verify-scalars", "", "directory the types are written to, against which to check that the --scalars-module exports every custom scalar")
+	flag.BoolVar(&groupQueryTypes, "group-query-types", false, "group QueryTypes entries, and those of the --registry, by kind, sorted by name")
+	flag.BoolVar(&emitPartial, "emit-partial", false, "write placeholder QueryTypes entries, commented with their errors, for documents that fail to type")
+	flag.BoolVar(&variableDefaults, "variable-defaults", false, "also export the default values of each named operation's variables as a constant")
+	flag.StringVar(&sections, "sections", "", "comma-separated sections of the TypeScript output to write, of "+strings.Join(emit.TypeScriptSections, ", ")+" (defaults to all)")
+	flag.BoolVar(&optimize, "optimize", false, "shorten the generated TypeScript by aliasing repeated unions of string literals and identical variables types, and omitting needless parentheses")
+	flag.Var(&tagSpecs, "tag", "tag operations by where their inputs are, as Name=dir:Dir for the directory under Dir or Name=package:Field for a field of the nearest package.json (repeatable)")
+	flag.BoolVar(&esm, "esm", false, "give relative imports explicit .ts extensions, as Deno and Node ESM require")
+	flag.StringVar(&splitDir, "split-dir", "", "directory to also write the types to as a module per input file, an index, and a tsconfig.json for use as a project reference")
+	flag.StringVar(&mocksPath, "mocks", "", "path to write Mock Service Worker handlers for named operations to, such as mocks.generated.ts")
+	flag.StringVar(&fixturesPath, "fixtures", "", "path to write typed fixture factories for named operations and fragments to, such as fixtures.generated.ts")
+	flag.StringVar(&goPath, "go", "", "path to write Go structs for the generated types to, such as types.generated.go")
+	flag.StringVar(&goPackage, "go-package", "graphql", "package name of --go")
+	flag.StringVar(&trustedDocumentsDir, "trusted-documents", "", "directory to write an allowlist of operations and a .graphql file per operation to")
+	flag.StringVar(&apolloManifestPath, "apollo-manifest", "", "path to write an Apollo operation manifest to, such as operations.json")
+	flag.StringVar(&registryPath, "registry", "", "path to write a registry of operation names, IDs, and locations to, such as operations.generated.ts")
+	flag.StringVar(&documentHash, "document-hash", "sha256", "hash identifying operations in --trusted-documents, --documents-dir, --apollo-manifest, and --registry: "+strings.Join(emit.DocumentHashes, " or "))
+	flag.StringVar(&documentIDPrefix, "document-id-prefix", "", "prefix of operation IDs, such as sha256:")
+	flag.StringVar(&docsPath, "docs", "", "path to write a reference of all operations to, as HTML if it ends in .html, else Markdown")
+	flag.StringVar(&reportPath, "report", "", "path to write a report of each operation's complexity and usage to, as HTML if it ends in .html, else JSON")
+	flag.StringVar(&documentsPath, "documents", "", "path to write a TypeScript module with each named operation, fragments inlined, as a string constant to")
+	flag.StringVar(&documentsDir, "documents-dir", "", "directory to write each operation, fragments inlined, as a .graphql file to")
+	flag.StringVar(&eslintDocumentsDir, "eslint-documents", "", "directory to write each document, as written, to for graphql-eslint's operations setting")
+	flag.StringVar(&collectionPath, "collection", "", "path to write a collection of the named operations to, for replaying them in an API client")
+	flag.StringVar(&collectionFormat, "collection-format", "postman", "format of --collection: "+strings.Join(emit.CollectionFormats, " or "))
+	flag.StringVar(&collectionEndpoint, "collection-endpoint", "", "GraphQL endpoint URL of --collection requests (defaults to an endpoint environment variable)")
+	flag.StringVar(&schemaMapsPath, "schema-maps", "", "path to write TypeScript lookups of the schema's object types and possible types to, such as schema.generated.ts")
+	flag.StringVar(&coveragePath, "coverage", "", "path to write JSON listing which schema types and fields operations use to")
+	flag.StringVar(&usagesPath, "usages", "", "path to write JSON mapping operations, fragments, and fields to the source locations that use them to")
+	flag.StringVar(&changesPath, "changes", "", "path to JSON types of the last run, to summarize changes from and then overwrite")
+	flag.StringVar(&overFetchPath, "overfetch", "", "path to write JSON listing the fields each operation selects that no input names to")
+	flag.Var(&scalarMappings, "scalar", "map a scalar to a TypeScript type, as Name=Type (repeatable)")
+	flag.Var(&transforms, "transform", "rewrite documents before typing them, as the client does before sending them: "+strings.Join(typer.TransformNames(), " or ")+" (repeatable, applied in order)")
+	flag.Var(&lintRules, "lint", "enable a lint rule, as NAME[=SEVERITY[,LIMIT]] (repeatable)")
+	flag.StringVar(&diagnosticsFormat, "diagnostics-format", "text", "format of diagnostics written to stderr: text or json (a JSON object per line)")
+	flag.StringVar(&extensions, "extensions", strings.Join(generate.DefaultExtensions, ","), "comma-separated extensions of the files that directory inputs contain")
+	flag.IntVar(&jobs, "jobs", runtime.NumCPU(), "number of inputs to type concurrently")
+	flag.StringVar(&outputPath, "output", "", "path to write the generated types to, instead of standard output")
+	flag.BoolVar(&watch, "watch", false, "regenerate whenever the schema or an input changes, until interrupted (requires --output)")
+	flag.DurationVar(&timeout, "timeout", 0, "give up on generating after this long, such as 30s (0 for no limit)")
+	flag.StringVar(&listenPath, "listen", "extractgqlts.sock", "unix socket path to listen on in serve mode")
+}
+
+// Subcommands, selected by the first argument.
+var commands = map[string]func() error{
+	"serve":       serve,
+	"rpc":         serveRPC,
+	"lsp":         serveLSP,
+	"schema-diff": schemaDiff,
+	"doctor":      doctor,
 }
 
 func main() {
-	g := &generator{}
-	if err := g.run(); err != nil {
+	args := os.Args[1:]
+	var command func() error
+	if len(args) > 0 && commands[args[0]] != nil {
+		command, args = commands[args[0]], args[1:]
+	}
+	_ = flag.CommandLine.Parse(args)
+	if err := loadConfig(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
-	if g.errors > 0 {
-		os.Exit(1)
+
+	if command != nil {
+		if err := command(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		ok, err := run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
 	}
 }
 
-type generator struct {
-	typer  internal.Typer
-	errors int
+// Generates once, reporting whether there were no diagnostics.
+func run() (ok bool, err error) {
+	inputPatterns := inputArgs()
+	if schemaPath == "" || len(inputPatterns) == 0 {
+		return false, fmt.Errorf("usage: %s [serve|rpc|lsp|schema-diff|doctor] --schema=/path/to/schema.gql <input ...>", filepath.Base(os.Args[0]))
+	}
+	g, err := newGenerator()
+	if err != nil {
+		return false, err
+	}
+	if err := checkOutputFlags(); err != nil {
+		return false, err
+	}
+	g.CachePath = cachePath
+	g.CacheKey = "naming=" + naming
+	pkgs, err := parsePackages()
+	if err != nil {
+		return false, err
+	}
+	excludePackages(g, pkgs)
+
+	// Stop cleanly on interrupt, without writing partial output or cache.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	g.Hooks.OnDiagnostic = printDiagnostic
+	if watch {
+		if outputPath == "" {
+			return false, fmt.Errorf("--watch requires --output")
+		}
+		return true, watchInputs(ctx, g, pkgs, inputPatterns)
+	}
+	return generateOutputs(ctx, g, pkgs, inputPatterns)
 }
 
-func (g *generator) warnf(message string, v ...interface{}) {
-	fmt.Fprintf(os.Stderr, message+"\n", v...)
-	g.errors++
+// Generates the types of the inputs matching inputPatterns and writes them,
+// and every other output, as configured by flags. Reports whether there were
+// no problems.
+func generateOutputs(ctx context.Context, g *generate.Generator, pkgs []outputPackage, inputPatterns []string) (ok bool, err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var out bytes.Buffer
+	w := io.Writer(os.Stdout)
+	if outputPath != "" {
+		w = &out
+	}
+	res, err := g.Generate(ctx, w, inputPatterns)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false, fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		return false, err
+	}
+	if outputPath != "" {
+		if err := writeFileIfChanged(outputPath, out.Bytes()); err != nil {
+			return false, fmt.Errorf("writing output: %w", err)
+		}
+	}
+	ok = true
+	for _, d := range res.Diagnostics {
+		if failsRun(d) {
+			ok = false
+		}
+	}
+	if !verifyScalars(mainTypes(pkgs, res.Types)) {
+		ok = false
+	}
+	if complete, err := writePackages(pkgs, res.Types); err != nil {
+		return false, err
+	} else if !complete {
+		ok = false
+	}
+	if err := writeSplitTypes(res.Types); err != nil {
+		return false, err
+	}
+	if err := writeClient(res.Types); err != nil {
+		return false, err
+	}
+	if mocksPath != "" {
+		if err := writeOutput(mocksPath, &emit.MSWMocks{TypesModule: importSpecifier(clientTypesModule)}, res.Types); err != nil {
+			return false, fmt.Errorf("writing mocks: %w", err)
+		}
+	}
+	if fixturesPath != "" {
+		if err := writeOutput(fixturesPath, &emit.Fixtures{TypesModule: importSpecifier(clientTypesModule)}, res.Types); err != nil {
+			return false, fmt.Errorf("writing fixtures: %w", err)
+		}
+	}
+	if goPath != "" {
+		if err := writeOutput(goPath, &emit.Go{Package: goPackage}, res.Types); err != nil {
+			return false, fmt.Errorf("writing Go: %w", err)
+		}
+	}
+	if err := writeDocuments(res.Types); err != nil {
+		return false, err
+	}
+	if err := writeESLintDocuments(res.Types); err != nil {
+		return false, err
+	}
+	if err := writeTrustedDocuments(res.Types); err != nil {
+		return false, err
+	}
+	if apolloManifestPath != "" {
+		if err := writeOutput(apolloManifestPath, &emit.ApolloManifest{IDs: documentIDs()}, res.Types); err != nil {
+			return false, fmt.Errorf("writing Apollo manifest: %w", err)
+		}
+	}
+	if registryPath != "" {
+		if err := writeOutput(registryPath, &emit.OperationRegistry{Grouped: groupQueryTypes, IDs: documentIDs(), Hints: hintRules}, res.Types); err != nil {
+			return false, fmt.Errorf("writing operation registry: %w", err)
+		}
+	}
+	if docsPath != "" {
+		if err := writeOutput(docsPath, &emit.Docs{HTML: strings.HasSuffix(docsPath, ".html")}, res.Types); err != nil {
+			return false, fmt.Errorf("writing docs: %w", err)
+		}
+	}
+	if collectionPath != "" {
+		collection := &emit.Collection{Format: collectionFormat, Endpoint: collectionEndpoint}
+		if err := writeOutput(collectionPath, collection, res.Types); err != nil {
+			return false, fmt.Errorf("writing collection: %w", err)
+		}
+	}
+	if err := writeSchemaReports(ctx, g, inputPatterns, res.Types); err != nil {
+		return false, err
+	}
+	if err := writeChanges(res.Types); err != nil {
+		return false, err
+	}
+	if err := runPlugins(ctx, res.Types); err != nil {
+		return false, err
+	}
+	return ok, nil
 }
 
-func (g *generator) run() error {
-	flag.Parse()
-	inputPatterns := flag.Args()
-	if schemaPath == "" || len(inputPatterns) == 0 {
-		return fmt.Errorf("usage: %s --schema=/path/to/schema.gql <input ...>", filepath.Base(os.Args[0]))
+// Reports whether a diagnostic fails the run, as all do but for warnings of
+// require-operation-name that were not asked for with --lint. The rule warns
+// by default, and projects with anonymous operations should not start failing
+// for it.
+func failsRun(d generate.Diagnostic) bool {
+	if d.Severity != generate.SeverityWarning || d.Code != generate.CodeAnonymousOperation {
+		return true
+	}
+	for _, spec := range lintRules {
+		if name, _, err := typer.ParseLint(spec); err == nil && name == "require-operation-name" {
+			return true
+		}
 	}
+	return false
+}
 
-	if err := g.loadSchema(); err != nil {
-		return fmt.Errorf("loading schema: %w", err)
+// Checks, if --verify-scalars is set, that the scalars module exports every
+// custom scalar that the types, and any --split-dir, import from it. Reports
+// whether it does.
+func verifyScalars(types typer.GeneratedTypes) bool {
+	if verifyScalarsDir == "" {
+		return true
+	}
+	dirs := []string{verifyScalarsDir}
+	if splitDir != "" {
+		dirs = append(dirs, splitDir)
+	}
+	ok := true
+	for _, dir := range dirs {
+		if err := generate.VerifyScalarsModule(dir, scalarsModule, types.Scalars); err != nil {
+			printDiagnostic(generate.Diagnostic{Severity: generate.SeverityError, Err: err})
+			ok = false
+		}
 	}
+	return ok
+}
 
-	for _, inputPattern := range inputPatterns {
-		inputPaths, err := doublestar.Glob(inputPattern)
-		if err != nil {
-			g.warnf("error expanding filepath pattern %q: %w", inputPattern, err)
+// Writes a diagnostic to stderr in the --diagnostics-format.
+func printDiagnostic(d generate.Diagnostic) {
+	if diagnosticsFormat == "json" {
+		bs, err := json.Marshal(d)
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "%s\n", bs)
+			return
+		}
+	}
+	fmt.Fprintln(os.Stderr, d)
+}
+
+// Returns the --extensions, each with a leading dot.
+func inputExtensions() []string {
+	var res []string
+	for _, ext := range strings.Split(extensions, ",") {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
 			continue
 		}
-		for _, inputPath := range inputPaths {
-			g.visitInput(inputPath)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
 		}
+		res = append(res, ext)
 	}
+	return res
+}
 
-	fmt.Println("// GENERATED FILE. DO NOT EDIT.")
-	fmt.Println()
+// Builds a generator from command line flags.
+func newGenerator() (*generate.Generator, error) {
+	opts, err := typerOptions()
+	if err != nil {
+		return nil, err
+	}
+	if diagnosticsFormat != "text" && diagnosticsFormat != "json" {
+		return nil, fmt.Errorf("invalid --diagnostics-format: %q", diagnosticsFormat)
+	}
+	emitter, err := newEmitter(scalarsModule)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := tagConventions()
+	if err != nil {
+		return nil, err
+	}
+	return &generate.Generator{
+		SchemaPath:                schemaPath,
+		Options:                   opts,
+		Emitter:                   emitter,
+		Jobs:                      jobs,
+		Extensions:                inputExtensions(),
+		WarnUnmappedScalars:       warnUnmappedScalars,
+		WarnDeprecatedOnlyTypes:   warnDeprecatedOnlyTypes,
+		WarnOverlappingOperations: warnOverlappingOperations,
+		EmitPartial:               emitPartial,
+		Tags:                      tags,
+	}, nil
+}
+
+var tagName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
-	generated := g.typer.GeneratedTypes
-	if len(generated.Scalars) > 0 {
-		fmt.Print(`import type {`)
-		for _, scalar := range generated.Scalars {
-			fmt.Print(" ")
-			fmt.Print(scalar)
+// Parses --tag specs of the form Name=dir:Dir or Name=package:Field.
+func tagConventions() ([]generate.TagConvention, error) {
+	var res []generate.TagConvention
+	for _, spec := range tagSpecs {
+		eq := strings.IndexByte(spec, '=')
+		colon := strings.IndexByte(spec, ':')
+		if eq < 0 || colon < eq || colon == len(spec)-1 || !tagName.MatchString(spec[:eq]) {
+			return nil, fmt.Errorf("invalid --tag: %q, expected Name=dir:Dir or Name=package:Field", spec)
+		}
+		convention := generate.TagConvention{Tag: spec[:eq]}
+		switch value := spec[colon+1:]; spec[eq+1 : colon] {
+		case "dir":
+			convention.Dir = strings.ReplaceAll(value, `\`, "/")
+		case "package":
+			convention.PackageField = value
+		default:
+			return nil, fmt.Errorf("invalid --tag: %q, expected Name=dir:Dir or Name=package:Field", spec)
 		}
-		fmt.Println(` } from "./scalars";`)
-		fmt.Println()
+		res = append(res, convention)
 	}
+	return res, nil
+}
 
-	if len(generated.Declarations) > 0 {
-		for _, decl := range generated.Declarations {
-			fmt.Println(decl)
+// Returns the emitter selected by command line flags, which imports custom
+// scalars from the given module.
+func newEmitter(module string) (emit.Emitter, error) {
+	var emitter emit.Emitter
+	var err error
+	switch {
+	case templatePath != "":
+		emitter, err = emit.ParseTemplateFile(templatePath)
+	case validators != "":
+		if target != "typescript" {
+			return nil, fmt.Errorf("--validators requires --target=typescript")
+		}
+		emitter, err = emit.NewValidators(validators)
+	default:
+		emitter, err = emit.NewTarget(target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch e := emitter.(type) {
+	case *emit.TypeScript:
+		e.ScalarsModule = importSpecifier(module)
+		e.ExactVariables = naming == "graphql-codegen"
+		e.AugmentModule = augmentModule
+		e.GroupQueryTypes = groupQueryTypes
+		e.Optimize = optimize
+		e.VariableDefaults = variableDefaults
+		if sections != "" {
+			e.Sections = strings.Split(sections, ",")
+			if err := emit.ValidateSections(e.Sections); err != nil {
+				return nil, fmt.Errorf("invalid --sections: %w", err)
+			}
 		}
-		fmt.Println()
+	case *emit.Flow:
+		e.ScalarsModule = module
+	case *emit.Zod:
+		e.ScalarsModule = importSpecifier(module)
+	case *emit.Valibot:
+		e.ScalarsModule = importSpecifier(module)
+	}
+	if _, ok := emitter.(*emit.TypeScript); !ok && augmentModule != "" {
+		return nil, fmt.Errorf("--augment requires --target=typescript")
+	}
+	if _, ok := emitter.(*emit.TypeScript); !ok && optimize {
+		return nil, fmt.Errorf("--optimize requires --target=typescript without --template or --validators")
+	}
+	if _, ok := emitter.(*emit.TypeScript); !ok && variableDefaults {
+		return nil, fmt.Errorf("--variable-defaults requires --target=typescript without --template or --validators")
+	}
+	if _, ok := emitter.(*emit.TypeScript); !ok && sections != "" {
+		return nil, fmt.Errorf("--sections requires --target=typescript without --template or --validators")
 	}
+	return emitter, nil
+}
 
-	fmt.Println("export type QueryTypes = {")
-	for _, entry := range generated.QueryMap {
-		fmt.Printf("  %s: %s;\n", internal.StringToJSON(entry.Query), entry.Type)
+// Returns the specifier with which generated TypeScript imports module.
+func importSpecifier(module string) string {
+	if esm {
+		return emit.ESMSpecifier(module)
 	}
-	fmt.Println("}")
+	return module
+}
 
+// Writes the --split-dir, if any, removing generated modules of input files
+// that no longer have declarations.
+func writeSplitTypes(types typer.GeneratedTypes) error {
+	if splitDir == "" {
+		return nil
+	}
+	if target != "typescript" || templatePath != "" || validators != "" || augmentModule != "" {
+		return fmt.Errorf("--split-dir requires --target=typescript without --template, --validators, or --augment")
+	}
+	split := &emit.SplitTypeScript{
+		ScalarsModule:   importSpecifier(scalarsModule),
+		ExactVariables:  naming == "graphql-codegen",
+		ESM:             esm,
+		GroupQueryTypes: groupQueryTypes,
+	}
+	files, err := split.Files(types)
+	if err != nil {
+		return fmt.Errorf("writing split types: %w", err)
+	}
+	if err := os.MkdirAll(splitDir, 0755); err != nil {
+		return fmt.Errorf("writing split types: %w", err)
+	}
+	stale, err := filepath.Glob(filepath.Join(splitDir, "*.ts"))
+	if err != nil {
+		return fmt.Errorf("writing split types: %w", err)
+	}
+	current := make(map[string]bool)
+	for _, file := range files {
+		path := filepath.Join(splitDir, file.Name)
+		current[path] = true
+		if err := ioutil.WriteFile(path, file.Content, 0644); err != nil {
+			return fmt.Errorf("writing split types: %w", err)
+		}
+	}
+	for _, path := range stale {
+		if current[path] {
+			continue
+		}
+		// Leave modules that were not generated, such as scalars.
+		content, err := ioutil.ReadFile(path)
+		if err == nil && bytes.HasPrefix(content, []byte("// GENERATED FILE. DO NOT EDIT.")) {
+			err = os.Remove(path)
+		}
+		if err != nil {
+			return fmt.Errorf("writing split types: %w", err)
+		}
+	}
 	return nil
 }
 
-func (g *generator) loadSchema() (err error) {
-	g.typer.Schema, err = loadSchema()
-	return
+// Checks the flags of the outputs written after --output, so that an invalid
+// one is reported before anything is written.
+func checkOutputFlags() error {
+	if _, ok := emit.Clients[clientStyle]; !ok {
+		return fmt.Errorf("invalid --client-style: %q, expected one of: %s", clientStyle, strings.Join(emit.ClientNames(), ", "))
+	}
+	knownFormat := false
+	for _, format := range emit.CollectionFormats {
+		if format == collectionFormat {
+			knownFormat = true
+			break
+		}
+	}
+	if !knownFormat {
+		return fmt.Errorf("invalid --collection-format: %q, expected one of: %s", collectionFormat, strings.Join(emit.CollectionFormats, ", "))
+	}
+	if err := documentIDs().Validate(); err != nil {
+		return fmt.Errorf("invalid --document-hash: %w", err)
+	}
+	return nil
 }
 
-func loadSchema() (*ast.Schema, error) {
-	schemaBuf, err := ioutil.ReadFile(schemaPath)
+// Writes the --client, if any.
+func writeClient(types typer.GeneratedTypes) error {
+	if clientPath == "" {
+		return nil
+	}
+	emitter, err := emit.NewClient(clientStyle, importSpecifier(clientTypesModule))
 	if err != nil {
-		return nil, fmt.Errorf("reading: %w", err)
+		return err
 	}
+	if err := writeOutput(clientPath, emitter, types); err != nil {
+		return fmt.Errorf("writing client: %w", err)
+	}
+	return nil
+}
 
-	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{
-		Name:  schemaPath,
-		Input: string(schemaBuf),
-	})
-	if gqlErr != nil {
-		return nil, gqlErr
+// Writes the --trusted-documents, if any: allowlist.json, mapping document IDs
+// to documents, and each document as its own .graphql file.
+func writeTrustedDocuments(types typer.GeneratedTypes) error {
+	if trustedDocumentsDir == "" {
+		return nil
 	}
-	return schema, nil
+	if err := writeDocumentFiles(trustedDocumentsDir, types); err != nil {
+		return fmt.Errorf("writing trusted documents: %w", err)
+	}
+	if err := writeOutput(filepath.Join(trustedDocumentsDir, "allowlist.json"), &emit.TrustedDocumentsAllowlist{IDs: documentIDs()}, types); err != nil {
+		return fmt.Errorf("writing trusted documents: %w", err)
+	}
+	return nil
 }
 
-func (g *generator) visitInput(inputPath string) {
-	bs, err := ioutil.ReadFile(inputPath)
+// Writes the --documents and --documents-dir, if any.
+func writeDocuments(types typer.GeneratedTypes) error {
+	if documentsPath != "" {
+		if err := writeOutput(documentsPath, &emit.Documents{}, types); err != nil {
+			return fmt.Errorf("writing documents: %w", err)
+		}
+	}
+	if documentsDir != "" {
+		if err := writeDocumentFiles(documentsDir, types); err != nil {
+			return fmt.Errorf("writing documents: %w", err)
+		}
+	}
+	return nil
+}
+
+// Writes the --eslint-documents, if any, removing .graphql files of documents
+// that no longer exist.
+func writeESLintDocuments(types typer.GeneratedTypes) error {
+	if eslintDocumentsDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(eslintDocumentsDir, 0755); err != nil {
+		return fmt.Errorf("writing eslint documents: %w", err)
+	}
+	stale, err := filepath.Glob(filepath.Join(eslintDocumentsDir, "*.graphql"))
+	if err != nil {
+		return fmt.Errorf("writing eslint documents: %w", err)
+	}
+	current := make(map[string]bool)
+	for _, doc := range emit.SourceDocuments(types) {
+		path := filepath.Join(eslintDocumentsDir, doc.Filename)
+		current[path] = true
+		if err := ioutil.WriteFile(path, []byte(doc.Text), 0644); err != nil {
+			return fmt.Errorf("writing eslint documents: %w", err)
+		}
+	}
+	for _, path := range stale {
+		if !current[path] {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("writing eslint documents: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the scheme of operation IDs given by --document-hash and
+// --document-id-prefix.
+func documentIDs() emit.DocumentIDs {
+	return emit.DocumentIDs{Hash: documentHash, Prefix: documentIDPrefix}
+}
+
+// Writes each operation, with the fragments it spreads inlined, to its own
+// .graphql file in dir.
+func writeDocumentFiles(dir string, types typer.GeneratedTypes) error {
+	docs, err := emit.TrustedDocuments(types, documentIDs())
 	if err != nil {
-		g.warnf("reading %q: %w", inputPath, err)
-		return
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if err := ioutil.WriteFile(filepath.Join(dir, doc.Filename), []byte(doc.Document), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the --report, --coverage, --usages, --overfetch, and --schema-maps,
+// if any, which need the schema.
+func writeSchemaReports(ctx context.Context, g *generate.Generator, inputPatterns []string, types typer.GeneratedTypes) error {
+	if reportPath == "" && coveragePath == "" && usagesPath == "" && overFetchPath == "" && schemaMapsPath == "" {
+		return nil
 	}
-	queries, err := internal.ExtractQueriesFromBytes(bs)
+	tp, err := g.Typer(ctx)
 	if err != nil {
-		g.warnf("extracting queries from %q: %w", inputPath, err)
-		return
+		return err
+	}
+	if reportPath != "" {
+		report := &emit.Report{
+			Schema: tp.Schema,
+			HTML:   strings.HasSuffix(reportPath, ".html"),
+		}
+		if err := writeOutput(reportPath, report, types); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
 	}
-	for _, query := range queries {
-		_, warnings, err := g.typer.VisitString(inputPath, query)
-		for _, warning := range warnings {
-			g.warnf("warning: %v", warning)
+	if coveragePath != "" {
+		if err := writeOutput(coveragePath, &emit.Coverage{Schema: tp.Schema}, types); err != nil {
+			return fmt.Errorf("writing coverage: %w", err)
 		}
+	}
+	if usagesPath != "" {
+		if err := writeOutput(usagesPath, &emit.Usages{Schema: tp.Schema}, types); err != nil {
+			return fmt.Errorf("writing usages: %w", err)
+		}
+	}
+	if overFetchPath != "" {
+		overFetch := &emit.OverFetch{Schema: tp.Schema, Names: inputNames(inputPatterns)}
+		if err := writeOutput(overFetchPath, overFetch, types); err != nil {
+			return fmt.Errorf("writing over-fetching report: %w", err)
+		}
+	}
+	if schemaMapsPath != "" {
+		maps := &emit.SchemaMaps{
+			Schema:        tp.Schema,
+			Options:       tp.Options,
+			ScalarsModule: importSpecifier(scalarsModule),
+		}
+		if err := writeOutput(schemaMapsPath, maps, types); err != nil {
+			return fmt.Errorf("writing schema maps: %w", err)
+		}
+	}
+	return nil
+}
+
+// Prints, if --changes is set, how the types differ from those recorded by
+// the last run, then records them for the next.
+func writeChanges(types typer.GeneratedTypes) error {
+	if changesPath == "" {
+		return nil
+	}
+	bs, err := ioutil.ReadFile(changesPath)
+	switch {
+	case err == nil:
+		var old typer.GeneratedTypes
+		if err := json.Unmarshal(bs, &old); err != nil {
+			return fmt.Errorf("reading %s: %w", changesPath, err)
+		}
+		fmt.Fprintf(os.Stderr, "changes since the last run:\n%s", typer.CompareTypes(old, types))
+	case !os.IsNotExist(err):
+		return fmt.Errorf("reading changes: %w", err)
+	}
+	if err := writeOutput(changesPath, &emit.JSON{}, types); err != nil {
+		return fmt.Errorf("writing changes: %w", err)
+	}
+	return nil
+}
+
+// Returns the names in the code of the inputs, outside of GraphQL templates.
+// Inputs that cannot be read were reported while generating.
+func inputNames(inputPatterns []string) map[string]bool {
+	paths, _ := generate.ExpandPatterns(inputPatterns, inputExtensions())
+	names := make(map[string]bool)
+	for _, path := range paths {
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		found, err := extract.Names(string(bs))
 		if err != nil {
-			g.warnf("error: %v", err)
+			continue
+		}
+		for name := range found {
+			names[name] = true
 		}
 	}
+	return names
+}
+
+// Writes an additional output file.
+func writeOutput(path string, emitter emit.Emitter, types typer.GeneratedTypes) error {
+	var out bytes.Buffer
+	if err := emitter.Emit(&out, types); err != nil {
+		return err
+	}
+	return writeFileIfChanged(path, out.Bytes())
+}
+
+// Writes data to path, unless the file already holds it, so that file
+// watchers, such as bundlers' and --watch's own, react only to real changes.
+func writeFileIfChanged(path string, data []byte) error {
+	if old, err := ioutil.ReadFile(path); err == nil && bytes.Equal(old, data) {
+		return nil
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Builds typer options from command line flags.
+func typerOptions() (opts typer.Options, err error) {
+	opts.ShareShapes = shareShapes
+	opts.WarnDeprecated = warnDeprecated
+	switch nullability {
+	case "null":
+		opts.Nullability = typer.NullabilityNull
+	case "null-or-undefined":
+		opts.Nullability = typer.NullabilityNullOrUndefined
+	default:
+		return opts, fmt.Errorf("invalid --nullability: %q", nullability)
+	}
+	switch typename {
+	case "always":
+		opts.Typename = typer.TypenameAlways
+	case "selected":
+		opts.Typename = typer.TypenameSelected
+	default:
+		return opts, fmt.Errorf("invalid --typename: %q", typename)
+	}
+	switch unknownDirectives {
+	case "error":
+		opts.UnknownDirectives = typer.UnknownDirectivesError
+	case "warning":
+		opts.UnknownDirectives = typer.UnknownDirectivesWarning
+	case "allow":
+		opts.UnknownDirectives = typer.UnknownDirectivesAllow
+	default:
+		return opts, fmt.Errorf("invalid --unknown-directives: %q", unknownDirectives)
+	}
+	switch naming {
+	case "default":
+	case "graphql-codegen":
+		opts.Naming = typer.CodegenNaming
+	default:
+		return opts, fmt.Errorf("invalid --naming: %q", naming)
+	}
+	opts.Scalars = map[string]string{typer.UploadScalar: typer.DefaultUploadType}
+	for _, mapping := range scalarMappings {
+		eq := strings.IndexByte(mapping, '=')
+		if eq <= 0 {
+			return opts, fmt.Errorf("invalid --scalar: %q, expected Name=Type", mapping)
+		}
+		opts.Scalars[mapping[:eq]] = mapping[eq+1:]
+	}
+	for _, name := range transforms {
+		transform, ok := typer.TransformByName(name)
+		if !ok {
+			return opts, fmt.Errorf("invalid --transform: %q, expected one of: %s", name, strings.Join(typer.TransformNames(), ", "))
+		}
+		opts.Transforms = append(opts.Transforms, transform)
+	}
+	opts.Lint = map[string]typer.LintConfig{
+		"require-operation-name": {Severity: typer.LintWarning},
+	}
+	for _, spec := range lintRules {
+		name, config, err := typer.ParseLint(spec)
+		if err != nil {
+			return opts, fmt.Errorf("invalid --lint: %w", err)
+		}
+		opts.Lint[name] = config
+	}
+	return opts, nil
+}
+
+// A flag that may be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
 }
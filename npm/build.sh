@@ -0,0 +1,14 @@
+#!/bin/bash
+# Builds the WebAssembly module and copies the Go runtime support it needs
+# into this directory.
+
+set -euo pipefail
+cd "$(dirname "$0")"
+
+GOOS=js GOARCH=wasm go build -o extractgqlts.wasm ../wasm
+goroot="$(go env GOROOT)"
+if [ -f "$goroot/lib/wasm/wasm_exec.js" ]; then
+  cp "$goroot/lib/wasm/wasm_exec.js" .
+else
+  cp "$goroot/misc/wasm/wasm_exec.js" .
+fi
@@ -0,0 +1,16 @@
+export interface GenerateRequest {
+  schema: string;
+  sources: Array<{ path: string; text: string }>;
+  target?: "typescript" | "json";
+  nullability?: "null" | "null-or-undefined";
+  typename?: "always" | "selected";
+  scalars?: Record<string, string>;
+  shareShapes?: boolean;
+}
+
+export interface GenerateResponse {
+  output: string;
+  diagnostics: string[];
+}
+
+export function generate(request: GenerateRequest): Promise<GenerateResponse>;
@@ -0,0 +1,34 @@
+"use strict";
+
+// Thin wrapper around the extractgqlts WebAssembly module. Run build.sh to
+// produce extractgqlts.wasm and wasm_exec.js alongside this file.
+
+const fs = require("fs");
+const path = require("path");
+require("./wasm_exec.js");
+
+let loaded;
+
+function load() {
+  if (!loaded) {
+    loaded = (async () => {
+      const go = new globalThis.Go();
+      const wasm = fs.readFileSync(path.join(__dirname, "extractgqlts.wasm"));
+      const { instance } = await WebAssembly.instantiate(wasm, go.importObject);
+      go.run(instance);
+      return globalThis.extractgqlts;
+    })();
+  }
+  return loaded;
+}
+
+async function generate(request) {
+  const api = await load();
+  const response = JSON.parse(api.generate(JSON.stringify(request)));
+  if (response.error) {
+    throw new Error(response.error);
+  }
+  return response;
+}
+
+module.exports = { generate };
@@ -0,0 +1,23 @@
+import type { Plugin } from "vite";
+
+export interface ExtractGQLTSOptions {
+  // Path to the GraphQL schema, relative to the Vite root.
+  schema: string;
+  // Input patterns, relative to the Vite root, such as "src/**/*.ts".
+  inputs: string[];
+  // Path to write the generated types to, relative to the Vite root.
+  output: string;
+  // The extractgqlts binary. Defaults to "extractgqlts" on the PATH.
+  command?: string;
+  // Additional flags, such as "--nullability=null-or-undefined".
+  args?: string[];
+  // Extensions of files whose changes trigger regeneration.
+  extensions?: string[];
+}
+
+// Sent to clients as a custom HMR event when the output changes.
+export interface ExtractGQLTSUpdate {
+  output: string;
+}
+
+export function extractgqlts(options: ExtractGQLTSOptions): Plugin;
@@ -0,0 +1,164 @@
+"use strict";
+
+// Vite plugin that keeps generated types up to date by driving an
+// `extractgqlts serve` daemon. Sources are regenerated as they change, and the
+// output is rewritten only when its contents change, so Vite hot-updates only
+// what the new types affect. Requires the extractgqlts binary.
+
+const { spawn } = require("child_process");
+const fs = require("fs");
+const net = require("net");
+const os = require("os");
+const path = require("path");
+const readline = require("readline");
+
+const defaultExtensions = [".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".vue", ".svelte", ".gql", ".graphql"];
+
+// Runs `extractgqlts serve`, returning a function that sends it a request and
+// resolves to the response, and a function that stops it.
+function startDaemon({ command, args, cwd }) {
+  const socket = path.join(fs.mkdtempSync(path.join(os.tmpdir(), "extractgqlts-")), "serve.sock");
+  const child = spawn(command, ["serve", ...args, `--listen=${socket}`], {
+    cwd,
+    stdio: ["ignore", "ignore", "pipe"],
+  });
+  let stderr = "";
+
+  const ready = new Promise((resolve, reject) => {
+    child.on("error", reject);
+    child.on("exit", (code) => reject(new Error(`extractgqlts serve exited with ${code}: ${stderr.trim()}`)));
+    readline.createInterface({ input: child.stderr }).on("line", (line) => {
+      stderr += line + "\n";
+      if (line.startsWith("listening on ")) {
+        resolve();
+      }
+    });
+  });
+
+  let connection;
+  // Responses arrive in the order requests were sent.
+  const waiting = [];
+  async function connect() {
+    await ready;
+    if (!connection) {
+      connection = net.connect(socket);
+      connection.on("error", (err) => {
+        for (const { reject } of waiting.splice(0)) {
+          reject(err);
+        }
+        connection = undefined;
+      });
+      readline.createInterface({ input: connection }).on("line", (line) => {
+        const pending = waiting.shift();
+        if (pending) {
+          pending.resolve(JSON.parse(line));
+        }
+      });
+    }
+    return connection;
+  }
+
+  async function request(req) {
+    const conn = await connect();
+    return new Promise((resolve, reject) => {
+      waiting.push({ resolve, reject });
+      conn.write(JSON.stringify(req) + "\n");
+    });
+  }
+
+  function stop() {
+    if (connection) {
+      connection.end();
+    }
+    child.kill();
+  }
+
+  return { request, stop };
+}
+
+function extractgqlts(options) {
+  const {
+    schema,
+    inputs,
+    output,
+    command = "extractgqlts",
+    args = [],
+    extensions = defaultExtensions,
+  } = options;
+  let root;
+  let daemon;
+  let logger;
+  let devServer;
+  // Regenerations are serialized, and changes arriving during one coalesce
+  // into a single follow-up.
+  let running;
+  let again = false;
+
+  async function regenerate() {
+    if (running) {
+      again = true;
+      return running;
+    }
+    running = (async () => {
+      do {
+        again = false;
+        const res = await daemon.request({ inputs, output: path.resolve(root, output) });
+        for (const diagnostic of res.diagnostics || []) {
+          logger.warn(`[extractgqlts] ${diagnostic}`);
+        }
+        if (res.error) {
+          logger.error(`[extractgqlts] ${res.error}`);
+        } else if (res.changed && devServer) {
+          devServer.ws.send({ type: "custom", event: "extractgqlts:update", data: { output } });
+        }
+      } while (again);
+    })().finally(() => {
+      running = undefined;
+    });
+    return running;
+  }
+
+  return {
+    name: "extractgqlts",
+
+    configResolved(config) {
+      root = config.root;
+      logger = config.logger;
+    },
+
+    async buildStart() {
+      if (!daemon) {
+        daemon = startDaemon({ command, args: [`--schema=${schema}`, ...args], cwd: root });
+      }
+      await regenerate();
+    },
+
+    configureServer(server) {
+      devServer = server;
+      const outputPath = path.resolve(root, output);
+      const schemaPath = path.resolve(root, schema);
+      const onChange = (file) => {
+        if (file === outputPath) {
+          return;
+        }
+        if (file === schemaPath || extensions.includes(path.extname(file))) {
+          regenerate().catch((err) => logger.error(`[extractgqlts] ${err.message}`));
+        }
+      };
+      server.watcher.add(schemaPath);
+      server.watcher.on("add", onChange);
+      server.watcher.on("change", onChange);
+      server.watcher.on("unlink", onChange);
+      server.httpServer?.on("close", () => daemon?.stop());
+    },
+
+    buildEnd() {
+      if (!devServer && daemon) {
+        daemon.stop();
+        daemon = undefined;
+      }
+    },
+  };
+}
+
+module.exports = { extractgqlts };
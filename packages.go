@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/deref/extractgqlts/emit"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/typer"
+)
+
+// A package of a monorepo given by --package, whose documents are written to
+// an output of their own, importing custom scalars from a module of its own.
+type outputPackage struct {
+	// Slash-separated, without a leading ./.
+	pattern       string
+	output        string
+	scalarsModule string
+}
+
+// Parses --package specs of the form Pattern=Output[,ScalarsModule]. The
+// scalars module defaults to --scalars-module.
+func parsePackages() ([]outputPackage, error) {
+	var pkgs []outputPackage
+	for _, spec := range packageSpecs {
+		eq := strings.IndexByte(spec, '=')
+		if eq <= 0 || eq == len(spec)-1 {
+			return nil, fmt.Errorf("invalid --package: %q, expected Pattern=Output[,ScalarsModule]", spec)
+		}
+		pkg := outputPackage{
+			pattern:       path.Clean(strings.ReplaceAll(spec[:eq], `\`, "/")),
+			output:        spec[eq+1:],
+			scalarsModule: scalarsModule,
+		}
+		if comma := strings.IndexByte(pkg.output, ','); comma >= 0 {
+			pkg.output, pkg.scalarsModule = pkg.output[:comma], pkg.output[comma+1:]
+		}
+		if _, err := doublestar.Match(pkg.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid --package pattern %q: %w", spec[:eq], err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// Whether the package includes an input file.
+func (pkg outputPackage) includes(file string) bool {
+	ok, _ := doublestar.Match(pkg.pattern, file)
+	return ok
+}
+
+// Returns the first of the packages that includes an input file, or nil if
+// none does, in which case the file's documents are in the main output.
+func packageOf(pkgs []outputPackage, file string) *outputPackage {
+	for i := range pkgs {
+		if pkgs[i].includes(file) {
+			return &pkgs[i]
+		}
+	}
+	return nil
+}
+
+// Restricts the main output to documents of no package.
+func excludePackages(g *generate.Generator, pkgs []outputPackage) {
+	if len(pkgs) == 0 {
+		return
+	}
+	g.Emitter = &emit.Filtered{Emitter: g.Emitter, Include: outsidePackages(pkgs)}
+}
+
+// Writes the output of each package, verifying its scalars module if
+// --verify-scalars is set. Reports whether every scalars module is complete.
+func writePackages(pkgs []outputPackage, types typer.GeneratedTypes) (ok bool, err error) {
+	ok = true
+	for i := range pkgs {
+		pkg := &pkgs[i]
+		emitter, err := newEmitter(pkg.scalarsModule)
+		if err != nil {
+			return false, err
+		}
+		selected := emit.SelectFiles(types, func(file string) bool {
+			return packageOf(pkgs, file) == pkg
+		})
+		if err := writeOutput(pkg.output, emitter, selected); err != nil {
+			return false, fmt.Errorf("writing package output %s: %w", pkg.output, err)
+		}
+		if verifyScalarsDir == "" {
+			continue
+		}
+		if err := generate.VerifyScalarsModule(filepath.Dir(pkg.output), pkg.scalarsModule, selected.Scalars); err != nil {
+			printDiagnostic(generate.Diagnostic{Severity: generate.SeverityError, Err: err})
+			ok = false
+		}
+	}
+	return ok, nil
+}
+
+// Returns the types of the main output, those of documents of no package.
+func mainTypes(pkgs []outputPackage, types typer.GeneratedTypes) typer.GeneratedTypes {
+	if len(pkgs) == 0 {
+		return types
+	}
+	return emit.SelectFiles(types, outsidePackages(pkgs))
+}
+
+func outsidePackages(pkgs []outputPackage) func(file string) bool {
+	return func(file string) bool {
+		return packageOf(pkgs, file) == nil
+	}
+}
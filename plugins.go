@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/deref/extractgqlts/emit"
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Runs each --plugin and writes the files it produces under --plugin-out.
+func runPlugins(ctx context.Context, types typer.GeneratedTypes) error {
+	for _, command := range pluginCommands {
+		plugin, err := emit.ParsePlugin(command)
+		if err != nil {
+			return err
+		}
+		plugin.Stderr = os.Stderr
+		files, err := plugin.Run(ctx, types)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			path := filepath.Join(pluginOut, filepath.FromSlash(file.Name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(path, []byte(file.Content), 0644); err != nil {
+				return fmt.Errorf("writing plugin output: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/deref/extractgqlts/extract"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/internal/jsonrpc"
+	"github.com/deref/extractgqlts/typer"
+)
+
+// The rpc command serves JSON-RPC 2.0 on stdio, framed as in the Language
+// Server Protocol, for editor tooling. Sources are sent by the client, so
+// unsaved buffers may be queried. Offsets are in bytes.
+
+type rpcSource struct {
+	Path string `json:"path"`
+	Text string `json:"text"`
+}
+
+type rpcDocument struct {
+	Offset int    `json:"offset"`
+	Text   string `json:"text"`
+}
+
+type extractResult struct {
+	Documents []rpcDocument `json:"documents"`
+}
+
+type typeResult struct {
+	Output      string   `json:"output"`
+	Diagnostics []string `json:"diagnostics"`
+}
+
+type hoverParams struct {
+	rpcSource
+	Offset int `json:"offset"`
+}
+
+type hoverResult struct {
+	Document rpcDocument `json:"document"`
+	// The type of the document's entry in the QueryTypes map.
+	Type string `json:"type"`
+	// Declarations the type refers to.
+	Declarations []string `json:"declarations"`
+	Diagnostics  []string `json:"diagnostics"`
+}
+
+type rpcServer struct {
+	generator *generate.Generator
+}
+
+func serveRPC() error {
+	if schemaPath == "" {
+		return fmt.Errorf("usage: extractgqlts rpc --schema=/path/to/schema.gql")
+	}
+	g, err := newGenerator()
+	if err != nil {
+		return err
+	}
+	if err := g.LoadSchema(context.Background()); err != nil {
+		return err
+	}
+	s := &rpcServer{
+		generator: g,
+	}
+	return jsonrpc.NewConn(os.Stdin, os.Stdout).Serve(s.handle)
+}
+
+func (s *rpcServer) handle(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "extract":
+		var source rpcSource
+		if err := jsonrpc.DecodeParams(params, &source); err != nil {
+			return nil, err
+		}
+		documents, err := extractDocuments(source.Text)
+		if err != nil {
+			return nil, err
+		}
+		return extractResult{Documents: documents}, nil
+	case "type":
+		var source rpcSource
+		if err := jsonrpc.DecodeParams(params, &source); err != nil {
+			return nil, err
+		}
+		return s.typeSource(source)
+	case "hover":
+		var hover hoverParams
+		if err := jsonrpc.DecodeParams(params, &hover); err != nil {
+			return nil, err
+		}
+		return s.hover(hover)
+	default:
+		return nil, jsonrpc.Errorf(jsonrpc.MethodNotFound, "unknown method: %s", method)
+	}
+}
+
+func extractDocuments(text string) ([]rpcDocument, error) {
+	templates, err := extract.DocumentsFromString(text)
+	if err != nil {
+		return nil, fmt.Errorf("extracting queries: %w", err)
+	}
+	documents := make([]rpcDocument, len(templates))
+	for i, template := range templates {
+		documents[i] = rpcDocument{Offset: template.Offset, Text: template.Text}
+	}
+	return documents, nil
+}
+
+// Generates output for a single source.
+func (s *rpcServer) typeSource(source rpcSource) (*typeResult, error) {
+	var out bytes.Buffer
+	res, err := s.generator.GenerateSources(context.Background(), &out, []generate.Source{
+		{Path: source.Path, Text: source.Text},
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &typeResult{
+		Output:      out.String(),
+		Diagnostics: []string{},
+	}
+	for _, d := range res.Diagnostics {
+		result.Diagnostics = append(result.Diagnostics, d.String())
+	}
+	return result, nil
+}
+
+func (s *rpcServer) hover(params hoverParams) (*hoverResult, error) {
+	t, err := s.generator.Typer(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return hoverDocument(t, params.Path, params.Text, params.Offset)
+}
+
+// Types the document containing the offset, or returns nil if there is none.
+// Fragments defined elsewhere in the source are available to the document.
+func hoverDocument(t *typer.Typer, path, text string, offset int) (*hoverResult, error) {
+	documents, err := extractDocuments(text)
+	if err != nil {
+		return nil, err
+	}
+	var hovered *rpcDocument
+	for i, doc := range documents {
+		t.PrepareString(path, doc.Text)
+		if doc.Offset <= offset && offset <= doc.Offset+len(doc.Text) {
+			hovered = &documents[i]
+		}
+	}
+	if hovered == nil {
+		return nil, nil
+	}
+
+	typ, warnings, err := t.VisitString(path, hovered.Text)
+	res := &hoverResult{
+		Document:     *hovered,
+		Type:         typ,
+		Declarations: []string{},
+		Diagnostics:  []string{},
+	}
+	for _, decl := range typer.NewDeclarationSet(t.GeneratedTypes.Declarations).Ordered() {
+		res.Declarations = append(res.Declarations, decl.String())
+	}
+	for _, warning := range warnings {
+		res.Diagnostics = append(res.Diagnostics, fmt.Sprintf("warning: %v", warning))
+	}
+	if err != nil {
+		res.Diagnostics = append(res.Diagnostics, fmt.Sprintf("error: %v", err))
+	}
+	return res, nil
+}
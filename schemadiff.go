@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/typer"
+)
+
+// Reports the changes from an old schema to the --schema that break the
+// operations of the inputs, which are typed against the old schema.
+func schemaDiff() error {
+	args := flag.Args()
+	if schemaPath == "" || len(args) < 2 {
+		return fmt.Errorf("usage: extractgqlts schema-diff --schema=/path/to/new.gql /path/to/old.gql <input ...>")
+	}
+	g, err := newGenerator()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	after, err := g.Typer(ctx)
+	if err != nil {
+		return err
+	}
+
+	old := &generate.Generator{
+		SchemaPath: args[0],
+		Options:    after.Options,
+		Jobs:       jobs,
+	}
+	old.Options.Lint = nil
+	// Documents invalid against the old schema are not checked.
+	old.Hooks.OnDiagnostic = func(d generate.Diagnostic) {
+		if d.Severity == generate.SeverityError {
+			printDiagnostic(d)
+		}
+	}
+	res, err := old.Generate(ctx, ioutil.Discard, args[1:])
+	if err != nil {
+		return err
+	}
+	before, err := old.Typer(ctx)
+	if err != nil {
+		return err
+	}
+	changes, err := typer.BreakingChanges(before.Schema, after.Schema, after.Options, res.Types)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		printDiagnostic(generate.Diagnostic{Severity: generate.SeverityError, File: change.Location.File, Err: change})
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("found %d breaking changes", len(changes))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/deref/extractgqlts/generate"
+)
+
+// The serve command keeps the parsed schema and the per-input cache warm in
+// memory, accepting generation requests over a unix socket. Each connection
+// exchanges newline-delimited JSON: one serveRequest in, one serveResponse
+// out, any number of times.
+
+type serveRequest struct {
+	Inputs []string `json:"inputs"`
+	// If set, the generated file is written here instead of being returned.
+	Output string `json:"output,omitempty"`
+}
+
+type serveResponse struct {
+	Output      string   `json:"output,omitempty"`
+	Diagnostics []string `json:"diagnostics"`
+	Error       string   `json:"error,omitempty"`
+	// Whether the request's output file was written. It is left untouched if
+	// its contents would not change, so that file watchers, such as Vite's,
+	// react only to real changes.
+	Changed bool `json:"changed"`
+}
+
+type server struct {
+	mu        sync.Mutex // Serializes generation.
+	generator *generate.Generator
+}
+
+func serve() error {
+	if schemaPath == "" {
+		return fmt.Errorf("usage: extractgqlts serve --schema=/path/to/schema.gql [--listen=%s]", listenPath)
+	}
+	g, err := newGenerator()
+	if err != nil {
+		return err
+	}
+	// Interruption closes the listener and cancels in-flight generations.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := g.LoadSchema(ctx); err != nil {
+		return err
+	}
+	s := &server{
+		generator: g,
+	}
+
+	// Remove any socket left behind by a previous server.
+	if err := os.Remove(listenPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	listener, err := net.Listen("unix", listenPath)
+	if err != nil {
+		return fmt.Errorf("listening: %w", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "listening on %s\n", listenPath)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handle(ctx, conn)
+	}
+}
+
+func (s *server) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		var req serveRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		if err := enc.Encode(s.generate(ctx, req)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *server) generate(ctx context.Context, req serveRequest) (res serveResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out bytes.Buffer
+	s.generator.Hooks.OnDiagnostic = func(d generate.Diagnostic) {
+		res.Diagnostics = append(res.Diagnostics, d.String())
+	}
+	if _, err := s.generator.Generate(ctx, &out, req.Inputs); err != nil {
+		res.Error = err.Error()
+		return
+	}
+
+	if req.Output == "" {
+		res.Output = out.String()
+		return
+	}
+	if old, err := ioutil.ReadFile(req.Output); err == nil && bytes.Equal(old, out.Bytes()) {
+		return
+	}
+	if err := ioutil.WriteFile(req.Output, out.Bytes(), 0644); err != nil {
+		res.Error = fmt.Sprintf("writing output: %v", err)
+		return
+	}
+	res.Changed = true
+	return
+}
@@ -0,0 +1,145 @@
+package typer
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Whole-project generation against the synthetic schema must finish within
+// this budget, when checked with -budget. It is generous, but wall-clock time
+// still varies with the load of the machine, so it is checked only on
+// request; the benchmarks are the tool for catching smaller regressions.
+const projectBudget = 2 * time.Second
+
+var budget = flag.Bool("budget", false, "check that generating the synthetic project finishes within its time budget")
+
+const (
+	syntheticTypes         = 500
+	syntheticDocumentCount = 300
+)
+
+// Builds a schema of n object types, each linking to the next two, all
+// implementing a common interface.
+func syntheticSchema(n int) *ast.Schema {
+	var b strings.Builder
+	b.WriteString("type Query { node(id: ID!): Node, t0: T0! }\n")
+	b.WriteString("interface Node { id: ID! }\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "type T%d implements Node { id: ID! name: String! count: Int next: T%d! other: [T%d!]! }\n", i, (i+1)%n, (i+2)%n)
+	}
+	return gqlparser.MustLoadSchema(&ast.Source{
+		Name:  "schema.gql",
+		Input: b.String(),
+	})
+}
+
+// A named query selecting a chain of nested objects depth levels deep.
+func deepQuery(name string, depth int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "query %s { t0 {", name)
+	for i := 0; i < depth; i++ {
+		b.WriteString(" id name next {")
+	}
+	b.WriteString(" id")
+	for i := 0; i < depth; i++ {
+		b.WriteString(" }")
+	}
+	b.WriteString(" } }")
+	return b.String()
+}
+
+// A query narrowing an interface to many of its implementations.
+func wideQuery(name string, width int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "query %s($id: ID!) { node(id: $id) { id", name)
+	for i := 0; i < width; i++ {
+		fmt.Fprintf(&b, " ... on T%d { name other { id } }", i)
+	}
+	b.WriteString(" } }")
+	return b.String()
+}
+
+func benchmarkQuery(b *testing.B, query string) {
+	schema := syntheticSchema(syntheticTypes)
+	typer := &Typer{
+		Schema: schema,
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		typer.GeneratedTypes = GeneratedTypes{}
+		if _, _, err := typer.VisitString("", query); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTypeDeepSelection(b *testing.B) {
+	benchmarkQuery(b, deepQuery("Deep", 30))
+}
+
+func BenchmarkTypeWideSelection(b *testing.B) {
+	benchmarkQuery(b, wideQuery("Wide", 100))
+}
+
+// Types a synthetic project of many documents from scratch, as the CLI does.
+func generateProject(schema *ast.Schema, documents []string) error {
+	typer := &Typer{
+		Schema: schema,
+	}
+	for _, document := range documents {
+		typer.PrepareString("", document)
+	}
+	for _, document := range documents {
+		if _, _, err := typer.VisitString("", document); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func syntheticDocuments() []string {
+	documents := make([]string, syntheticDocumentCount)
+	for i := range documents {
+		name := fmt.Sprintf("Op%d", i)
+		if i%2 == 0 {
+			documents[i] = deepQuery(name, 5+i%10)
+		} else {
+			documents[i] = wideQuery(name, 5+i%20)
+		}
+	}
+	return documents
+}
+
+func BenchmarkGenerateProject(b *testing.B) {
+	schema := syntheticSchema(syntheticTypes)
+	documents := syntheticDocuments()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := generateProject(schema, documents); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGenerationBudget(t *testing.T) {
+	if !*budget {
+		t.Skip("skipping generation budget without -budget")
+	}
+	schema := syntheticSchema(syntheticTypes)
+	documents := syntheticDocuments()
+	start := time.Now()
+	if err := generateProject(schema, documents); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > projectBudget {
+		t.Errorf("generating %d documents took %v, exceeding budget of %v", len(documents), elapsed, projectBudget)
+	}
+}
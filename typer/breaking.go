@@ -0,0 +1,371 @@
+package typer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// A schema change that breaks an operation or fragment: it is no longer
+// valid, or its data or variables change in a way that code written against
+// the old types may not handle.
+type BreakingChange struct {
+	Operation OperationKind `json:"operation"`
+	// Empty for anonymous operations.
+	Name     string   `json:"name"`
+	Location Location `json:"location"`
+	Message  string   `json:"message"`
+}
+
+func (c BreakingChange) Error() string {
+	name := c.Name
+	if name == "" {
+		name = "(anonymous)"
+	}
+	return fmt.Sprintf("%s: %s %s: %s", c.Location, c.Operation, name, c.Message)
+}
+
+// Returns the changes from the old schema to the new that break the documents
+// of types, which must have been generated against old, in order of
+// appearance. Each document is typed against both schemas with opts, less any
+// lint rules. Data may become narrower, such as by no longer being null, and
+// variables wider, without breaking anything.
+func BreakingChanges(old, new *ast.Schema, opts Options, types GeneratedTypes) ([]BreakingChange, error) {
+	opts.Lint = nil
+	opts.WarnDeprecated = false
+	before := &Typer{Schema: old, Options: opts}
+	after := &Typer{Schema: new, Options: opts}
+	for _, entry := range types.QueryMap {
+		if entry.Operation == OperationFragment {
+			before.PrepareString(entry.Location.File, entry.Query)
+			after.PrepareString(entry.Location.File, entry.Query)
+		}
+	}
+
+	type typed struct {
+		before, after QueryType
+		messages      []string
+	}
+	var documents []*typed
+	byQuery := make(map[string]*typed)
+	for _, entry := range types.QueryMap {
+		if _, ok := byQuery[entry.Query]; ok {
+			continue
+		}
+		doc := &typed{}
+		byQuery[entry.Query] = doc
+		documents = append(documents, doc)
+
+		_, oldWarnings, err := before.VisitString(entry.Location.File, entry.Query)
+		if err != nil {
+			return nil, fmt.Errorf("typing %s %s against the old schema: %w", entry.Operation, entry.Name, err)
+		}
+		doc.before = before.QueryMap[len(before.QueryMap)-1]
+		known := make(map[string]bool)
+		for _, warning := range oldWarnings {
+			known[diagnosticMessage(warning)] = true
+		}
+		_, newWarnings, err := after.VisitString(entry.Location.File, entry.Query)
+		for _, warning := range newWarnings {
+			if message := diagnosticMessage(warning); !known[message] {
+				doc.messages = append(doc.messages, message)
+			}
+		}
+		var validationErr *ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			for _, gqlErr := range validationErr.Errors {
+				doc.messages = append(doc.messages, gqlErr.Message)
+			}
+		case err != nil:
+			doc.messages = append(doc.messages, err.Error())
+		default:
+			doc.after = after.QueryMap[len(after.QueryMap)-1]
+		}
+	}
+
+	beforeDecls := NewDeclarationSet(before.Declarations)
+	afterDecls := NewDeclarationSet(after.Declarations)
+	for _, doc := range documents {
+		if len(doc.messages) > 0 {
+			continue
+		}
+		data := &typeDiffer{old: old, new: new, oldDecls: beforeDecls, newDecls: afterDecls}
+		data.diff("data", doc.before.Data, doc.after.Data)
+		variables := &typeDiffer{old: old, new: new, oldDecls: beforeDecls, newDecls: afterDecls, input: true}
+		variables.diff("variables", doc.before.Variables, doc.after.Variables)
+		doc.messages = append(data.changes, variables.changes...)
+	}
+
+	var changes []BreakingChange
+	for _, entry := range types.QueryMap {
+		for _, message := range byQuery[entry.Query].messages {
+			changes = append(changes, BreakingChange{
+				Operation: entry.Operation,
+				Name:      entry.Name,
+				Location:  entry.Location,
+				Message:   message,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// Returns the message of a diagnostic, without its location.
+func diagnosticMessage(err error) string {
+	if gqlErr, ok := err.(*gqlerror.Error); ok {
+		return gqlErr.Message
+	}
+	return err.Error()
+}
+
+// Compares types of the same document generated against two schemas,
+// recording incompatible changes.
+type typeDiffer struct {
+	old, new           *ast.Schema
+	oldDecls, newDecls *DeclarationSet
+	// Whether the types are of variables, which the document's users provide,
+	// rather than of data, which they receive.
+	input bool
+	// Names of the old declarations, and the schema types, being compared.
+	comparing map[string]bool
+	changes   []string
+}
+
+func (d *typeDiffer) record(format string, args ...interface{}) {
+	d.changes = append(d.changes, fmt.Sprintf(format, args...))
+}
+
+func (d *typeDiffer) diff(path string, a, b Type) {
+	// Input object types may be recursive.
+	if named, ok := a.(NamedType); ok {
+		if _, declared := d.oldDecls.Get(named.Name); declared {
+			if d.comparing[named.Name] {
+				return
+			}
+			if d.comparing == nil {
+				d.comparing = make(map[string]bool)
+			}
+			d.comparing[named.Name] = true
+			defer delete(d.comparing, named.Name)
+		}
+	}
+	a = resolveDeclared(d.oldDecls, a)
+	b = resolveDeclared(d.newDecls, b)
+	nullableA, aNull := a.(NullableType)
+	nullableB, bNull := b.(NullableType)
+	switch {
+	case aNull && bNull:
+		d.diff(path, nullableA.Type, nullableB.Type)
+		return
+	case aNull:
+		if d.input {
+			d.record("%s no longer accepts null", path)
+			return
+		}
+		d.diff(path, nullableA.Type, b)
+		return
+	case bNull:
+		if !d.input {
+			d.record("%s may now be null", path)
+			return
+		}
+		d.diff(path, a, nullableB.Type)
+		return
+	}
+
+	switch a := a.(type) {
+	case ObjectType:
+		if b, ok := b.(ObjectType); ok {
+			d.diffObjects(path, a, b)
+			return
+		}
+	case ArrayType:
+		if b, ok := b.(ArrayType); ok {
+			d.diff(path+"[]", a.Elem, b.Elem)
+			return
+		}
+	case UnionType:
+		if b, ok := b.(UnionType); ok && d.diffUnions(path, a, b) {
+			return
+		}
+	case IntersectionType:
+		if b, ok := b.(IntersectionType); ok && len(a.Members) == len(b.Members) {
+			for i := range a.Members {
+				d.diff(path, a.Members[i], b.Members[i])
+			}
+			return
+		}
+	case NamedType:
+		if b, ok := b.(NamedType); ok && a.Name == b.Name {
+			d.diffSchemaType(path, a.Name)
+			return
+		}
+	}
+	if before, after := RenderType(a), RenderType(b); before != after {
+		d.record("%s changes from %s to %s", path, before, after)
+	}
+}
+
+func (d *typeDiffer) diffObjects(path string, a, b ObjectType) {
+	fields := make(map[string]Type, len(b.Fields))
+	for _, field := range b.Fields {
+		fields[field.Name] = field.Type
+	}
+	for _, field := range a.Fields {
+		typ, ok := fields[field.Name]
+		delete(fields, field.Name)
+		if !ok {
+			if d.input {
+				d.record("%s.%s is no longer accepted", path, field.Name)
+			} else {
+				d.record("%s.%s is no longer present", path, field.Name)
+			}
+			continue
+		}
+		d.diff(path+"."+field.Name, field.Type, typ)
+	}
+	if !d.input {
+		return
+	}
+	for _, field := range b.Fields {
+		if typ, ok := fields[field.Name]; ok {
+			if _, nullable := typ.(NullableType); !nullable {
+				d.record("%s.%s is now required", path, field.Name)
+			}
+		}
+	}
+}
+
+// Compares unions of literals, or of the same number of other members,
+// reporting whether it could.
+func (d *typeDiffer) diffUnions(path string, a, b UnionType) bool {
+	literalsA, okA := literalSet(a)
+	literalsB, okB := literalSet(b)
+	if okA && okB {
+		d.diffValues(path, literalsA, literalsB)
+		return true
+	}
+	if len(a.Members) != len(b.Members) {
+		return false
+	}
+	for i := range a.Members {
+		d.diff(path, a.Members[i], b.Members[i])
+	}
+	return true
+}
+
+func literalSet(u UnionType) ([]string, bool) {
+	var literals []string
+	for _, member := range u.Members {
+		literal, ok := member.(StringLiteralType)
+		if !ok {
+			return nil, false
+		}
+		literals = append(literals, literal.Value)
+	}
+	return literals, true
+}
+
+// Compares an enum or input object type, which are imported rather than
+// declared.
+func (d *typeDiffer) diffSchemaType(path, name string) {
+	a, b := d.old.Types[name], d.new.Types[name]
+	if a == nil || b == nil || a.Kind != b.Kind {
+		return
+	}
+	key := "type " + name
+	if d.comparing[key] {
+		return
+	}
+	if d.comparing == nil {
+		d.comparing = make(map[string]bool)
+	}
+	d.comparing[key] = true
+	defer delete(d.comparing, key)
+	switch a.Kind {
+	case ast.Enum:
+		var valuesA, valuesB []string
+		for _, value := range a.EnumValues {
+			valuesA = append(valuesA, value.Name)
+		}
+		for _, value := range b.EnumValues {
+			valuesB = append(valuesB, value.Name)
+		}
+		d.diffValues(path, valuesA, valuesB)
+	case ast.InputObject:
+		for _, field := range a.Fields {
+			if other := b.Fields.ForName(field.Name); other != nil {
+				d.diffInputType(path+"."+field.Name, field.Type, other.Type)
+			} else {
+				d.record("%s.%s is no longer accepted", path, field.Name)
+			}
+		}
+		for _, field := range b.Fields {
+			if a.Fields.ForName(field.Name) == nil && field.Type.NonNull && field.DefaultValue == nil {
+				d.record("%s.%s is now required", path, field.Name)
+			}
+		}
+	}
+}
+
+func (d *typeDiffer) diffInputType(path string, a, b *ast.Type) {
+	if b.NonNull && !a.NonNull {
+		d.record("%s no longer accepts null", path)
+	}
+	switch {
+	case (a.Elem == nil) != (b.Elem == nil) || a.NamedType != b.NamedType:
+		d.record("%s changes from %s to %s", path, a.Name(), b.Name())
+	case a.Elem != nil:
+		d.diffInputType(path+"[]", a.Elem, b.Elem)
+	default:
+		d.diffSchemaType(path, a.NamedType)
+	}
+}
+
+// Records values that data may now have, or that variables no longer accept.
+func (d *typeDiffer) diffValues(path string, a, b []string) {
+	wider, narrower := b, a
+	if d.input {
+		wider, narrower = a, b
+	}
+	allowed := make(map[string]bool)
+	for _, value := range narrower {
+		allowed[value] = true
+	}
+	var extra []string
+	for _, value := range wider {
+		if !allowed[value] {
+			extra = append(extra, StringToJSON(value))
+		}
+	}
+	if len(extra) == 0 {
+		return
+	}
+	sort.Strings(extra)
+	if d.input {
+		d.record("%s no longer accepts %s", path, strings.Join(extra, ", "))
+	} else {
+		d.record("%s may now be %s", path, strings.Join(extra, ", "))
+	}
+}
+
+// Returns the type a generated declaration names, or typ itself.
+func resolveDeclared(decls *DeclarationSet, typ Type) Type {
+	for i := 0; i < 16; i++ {
+		named, ok := typ.(NamedType)
+		if !ok {
+			break
+		}
+		decl, ok := decls.Get(named.Name)
+		if !ok {
+			break
+		}
+		typ = decl.Type
+	}
+	return typ
+}
@@ -0,0 +1,94 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestBreakingChanges(t *testing.T) {
+	old := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "old.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+				users(filter: Filter): [User!]!
+			}
+
+			type User {
+				id: ID!
+				name: String
+				role: Role!
+				email: String!
+				legacy: String
+			}
+
+			enum Role { ADMIN USER }
+
+			input Filter {
+				role: Role
+				name: String
+				and: [Filter!]
+			}
+		`,
+	})
+	new := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "new.gql",
+		Input: `
+			type Query {
+				user(id: ID!, org: ID!): User
+				users(filter: Filter): [User!]!
+			}
+
+			type User {
+				id: ID!
+				name: String!
+				role: Role!
+				email: String
+			}
+
+			enum Role { ADMIN USER GUEST }
+
+			input Filter {
+				role: Role!
+				and: [Filter!]
+				limit: Int
+			}
+		`,
+	})
+
+	typer := &Typer{Schema: old}
+	fragment := "fragment UserFields on User { name role email }"
+	typer.PrepareString("fragments.ts", fragment)
+	for _, doc := range []struct{ file, query string }{
+		{"fragments.ts", fragment},
+		{"a.ts", "query GetUser($id: ID!) { user(id: $id) { id } }"},
+		{"b.ts", "query ListUsers($filter: Filter) { users(filter: $filter) { id ...UserFields } }"},
+		{"c.ts", "query Legacy { users { legacy } }"},
+		{"d.ts", "query Unaffected { users { id name } }"},
+	} {
+		_, _, err := typer.VisitString(doc.file, doc.query)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	changes, err := BreakingChanges(old, new, Options{}, typer.GeneratedTypes)
+	assert.NoError(t, err)
+	var messages []string
+	for _, change := range changes {
+		messages = append(messages, change.Error())
+	}
+	assert.Equal(t, []string{
+		`fragments.ts:1:1: fragment UserFields: data.email may now be null`,
+		`fragments.ts:1:1: fragment UserFields: data.role may now be "GUEST"`,
+		`a.ts:1:1: query GetUser: Field "user" argument "org" of type "ID!" is required, but it was not provided.`,
+		`b.ts:1:1: query ListUsers: data.users[].email may now be null`,
+		`b.ts:1:1: query ListUsers: data.users[].role may now be "GUEST"`,
+		`b.ts:1:1: query ListUsers: variables.filter.role no longer accepts null`,
+		`b.ts:1:1: query ListUsers: variables.filter.name is no longer accepted`,
+		`c.ts:1:1: query Legacy: Cannot query field "legacy" on type "User".`,
+	}, messages)
+}
@@ -0,0 +1,119 @@
+package typer
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected.txt of each case in testdata/cases instead of comparing against it")
+
+// Types the inputs of each directory of testdata/cases, and compares the
+// outcome to its expected.txt. See testdata/cases/README.md.
+func TestCases(t *testing.T) {
+	entries, err := ioutil.ReadDir("testdata/cases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dir := filepath.Join("testdata/cases", entry.Name())
+			t.Run(entry.Name(), func(t *testing.T) {
+				checkCase(t, dir)
+			})
+		}
+	}
+}
+
+func checkCase(t *testing.T, dir string) {
+	schemaPath := filepath.Join(dir, "schema.graphql")
+	if _, err := os.Stat(schemaPath); os.IsNotExist(err) {
+		schemaPath = filepath.Join(filepath.Dir(dir), "schema.graphql")
+	}
+	schemaText, err := ioutil.ReadFile(schemaPath)
+	if err != nil {
+		t.Fatalf("reading schema: %v", err)
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(schemaText)})
+	if gqlErr != nil {
+		t.Fatalf("loading schema: %v", gqlErr)
+	}
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(inputs)
+
+	typer := &Typer{Schema: schema}
+	sources := make(map[string]string)
+	for _, input := range inputs {
+		if filepath.Base(input) == "schema.graphql" {
+			continue
+		}
+		bs, err := ioutil.ReadFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources[filepath.Base(input)] = string(bs)
+		typer.PrepareString(filepath.Base(input), string(bs))
+	}
+	if len(sources) == 0 {
+		t.Fatalf("no inputs in %s", dir)
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out bytes.Buffer
+	for _, name := range names {
+		root, warnings, _ := typer.VisitString(name, sources[name])
+		fmt.Fprintf(&out, "# %s\n%s\n", name, root)
+		for _, warning := range warnings {
+			fmt.Fprintf(&out, "warning: %v\n", warning)
+		}
+	}
+	if len(typer.Scalars) > 0 {
+		fmt.Fprintf(&out, "\nscalars: %s\n", strings.Join(typer.Scalars, ", "))
+	}
+	if len(typer.Declarations) > 0 {
+		out.WriteString("\n")
+		for _, decl := range typer.Declarations {
+			fmt.Fprintf(&out, "%s\n", decl)
+		}
+	}
+
+	expectedPath := filepath.Join(dir, "expected.txt")
+	if *update {
+		if err := ioutil.WriteFile(expectedPath, out.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := ioutil.ReadFile(expectedPath)
+	if err != nil {
+		t.Fatalf("reading expected output: %v (run with -update to create it)", err)
+	}
+	if bytes.Equal(expected, out.Bytes()) {
+		return
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(out.String()),
+		FromFile: expectedPath,
+		ToFile:   "actual",
+		Context:  3,
+	})
+	t.Errorf("output differs from %s (run with -update to accept):\n%s", expectedPath, diff)
+}
@@ -0,0 +1,132 @@
+package typer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// How the types generated by one run differ from those of another, such as
+// the last run over the same inputs, so that reviewers of a diff of the
+// generated output may see what it means.
+type Changes struct {
+	// Descriptions of the operations and fragments added and removed, such as
+	// query GetUser, in order of appearance.
+	Added   []string
+	Removed []string
+	// Descriptions of the operations and fragments whose data or variables
+	// changed shape, such as query GetUser (data), in order of appearance.
+	Changed []string
+	// Names of the shared shapes, declared with ShareShapes, whose types
+	// changed, in order of appearance.
+	ChangedTypes []string
+}
+
+// Returns how the types of new differ from those of old. Operations and
+// fragments are identified by kind and name, or by source text if anonymous,
+// so that changing a document's text but not its types is no change.
+func CompareTypes(old, new GeneratedTypes) Changes {
+	var c Changes
+	oldOps := indexOperations(old)
+	newOps := indexOperations(new)
+	oldDecls := NewDeclarationSet(old.Declarations)
+	newDecls := NewDeclarationSet(new.Declarations)
+	for _, key := range newOps.order {
+		entry := newOps.entries[key]
+		prev, ok := oldOps.entries[key]
+		if !ok {
+			c.Added = append(c.Added, describeEntry(entry))
+			continue
+		}
+		var parts []string
+		if renderDeclared(oldDecls, prev.Data) != renderDeclared(newDecls, entry.Data) {
+			parts = append(parts, "data")
+		}
+		if renderDeclared(oldDecls, prev.Variables) != renderDeclared(newDecls, entry.Variables) {
+			parts = append(parts, "variables")
+		}
+		if len(parts) > 0 {
+			c.Changed = append(c.Changed, fmt.Sprintf("%s (%s)", describeEntry(entry), strings.Join(parts, ", ")))
+		}
+	}
+	for _, key := range oldOps.order {
+		if _, ok := newOps.entries[key]; !ok {
+			c.Removed = append(c.Removed, describeEntry(oldOps.entries[key]))
+		}
+	}
+
+	// Declarations of operations and fragments change along with them; only
+	// those of shared shapes are left.
+	for _, decl := range newDecls.Ordered() {
+		if decl.Kind != DeclarationShape {
+			continue
+		}
+		if prev, ok := oldDecls.Get(decl.Name); ok && RenderType(prev.Type) != RenderType(decl.Type) {
+			c.ChangedTypes = append(c.ChangedTypes, decl.Name)
+		}
+	}
+	return c
+}
+
+// Whether anything changed.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0 && len(c.ChangedTypes) == 0
+}
+
+// Returns a summary of the changes, a line for each, or "no changes".
+func (c Changes) String() string {
+	if c.Empty() {
+		return "no changes\n"
+	}
+	var sb strings.Builder
+	for _, s := range c.Added {
+		fmt.Fprintf(&sb, "added %s\n", s)
+	}
+	for _, s := range c.Removed {
+		fmt.Fprintf(&sb, "removed %s\n", s)
+	}
+	for _, s := range c.Changed {
+		fmt.Fprintf(&sb, "changed %s\n", s)
+	}
+	for _, s := range c.ChangedTypes {
+		fmt.Fprintf(&sb, "changed type %s\n", s)
+	}
+	return sb.String()
+}
+
+// Renders typ, or the type it names if it is declared in decls.
+func renderDeclared(decls *DeclarationSet, typ Type) string {
+	if named, ok := typ.(NamedType); ok {
+		if decl, ok := decls.Get(named.Name); ok {
+			typ = decl.Type
+		}
+	}
+	return RenderType(typ)
+}
+
+type operationIndex struct {
+	order   []string
+	entries map[string]QueryType
+}
+
+func indexOperations(types GeneratedTypes) operationIndex {
+	idx := operationIndex{entries: make(map[string]QueryType)}
+	for _, entry := range types.QueryMap {
+		key := string(entry.Operation) + " " + entry.Name
+		if entry.Name == "" {
+			key += "\n" + entry.Query
+		}
+		if _, ok := idx.entries[key]; ok {
+			continue
+		}
+		idx.order = append(idx.order, key)
+		idx.entries[key] = entry
+	}
+	return idx
+}
+
+func describeEntry(entry QueryType) string {
+	if entry.Name == "" {
+		return fmt.Sprintf("anonymous %s at %s", entry.Operation, entry.Location)
+	}
+	return fmt.Sprintf("%s %s", entry.Operation, entry.Name)
+}
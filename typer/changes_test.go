@@ -0,0 +1,57 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestCompareTypes(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID): User
+				users: [User!]!
+			}
+
+			type User {
+				id: ID!
+				name: String
+			}
+		`,
+	})
+	generate := func(queries ...string) GeneratedTypes {
+		tp := &Typer{Schema: schema}
+		for _, query := range queries {
+			_, _, err := tp.VisitString("a.ts", query)
+			assert.NoError(t, err)
+		}
+		return tp.GeneratedTypes
+	}
+	old := generate(
+		`query A { user { id } }`,
+		`query B($id: ID) { user(id: $id) { id } }`,
+		`query C { users { id } }`,
+		`{ users { name } }`,
+	)
+	new := generate(
+		`query A { user { id name } }`,
+		`query B { user { id } }`,
+		`query C {
+			# Reformatted.
+			users { id }
+		}`,
+		`query D { users { id } }`,
+	)
+	c := CompareTypes(old, new)
+	assert.Equal(t, Changes{
+		Added:   []string{"query D"},
+		Removed: []string{"anonymous query at a.ts:1:1"},
+		Changed: []string{"query A (data)", "query B (variables)"},
+	}, c)
+	assert.Equal(t, "added query D\nremoved anonymous query at a.ts:1:1\nchanged query A (data)\nchanged query B (variables)\n", c.String())
+	assert.Equal(t, "no changes\n", CompareTypes(old, old).String())
+}
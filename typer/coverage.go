@@ -0,0 +1,99 @@
+package typer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Describes which parts of a schema's output types are selected by at least
+// one operation, to find schema surface that no client uses.
+type Coverage struct {
+	FieldsUsed  int            `json:"fieldsUsed"`
+	FieldsTotal int            `json:"fieldsTotal"`
+	Types       []TypeCoverage `json:"types"`
+}
+
+type TypeCoverage struct {
+	Name string             `json:"name"`
+	Kind ast.DefinitionKind `json:"kind"`
+	// Whether any operation selects from or may return this type.
+	Used bool `json:"used"`
+	// Fields of object and interface types.
+	Fields []FieldCoverage `json:"fields,omitempty"`
+}
+
+type FieldCoverage struct {
+	Name       string `json:"name"`
+	Used       bool   `json:"used"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+// Returns the coverage of schema's output types by the operations in types,
+// sorted by type name. Interfaces and unions that are used count as uses of
+// their possible types, and fields selected on an interface as uses of the
+// same fields of its implementations. Built-in, introspection, and input types
+// are omitted.
+func SchemaCoverage(schema *ast.Schema, types GeneratedTypes) (Coverage, error) {
+	usedTypes := make(map[string]bool)
+	usedFields := make(map[string]bool)
+	err := walkOperations(schema, types, func(entry QueryType, r *reporter) {
+		for name := range r.types {
+			usedTypes[name] = true
+			if def := schema.Types[name]; def.IsAbstractType() {
+				for _, possible := range schema.GetPossibleTypes(def) {
+					usedTypes[possible.Name] = true
+				}
+			}
+		}
+		for coordinate := range r.fields {
+			usedFields[coordinate] = true
+			dot := strings.IndexByte(coordinate, '.')
+			parent := schema.Types[coordinate[:dot]]
+			if parent.Kind == ast.Interface {
+				for _, impl := range schema.GetPossibleTypes(parent) {
+					usedFields[impl.Name+coordinate[dot:]] = true
+				}
+			}
+		}
+	})
+	if err != nil {
+		return Coverage{}, err
+	}
+
+	var coverage Coverage
+	for _, def := range schema.Types {
+		if def.BuiltIn || strings.HasPrefix(def.Name, "__") || def.Kind == ast.InputObject {
+			continue
+		}
+		tc := TypeCoverage{
+			Name: def.Name,
+			Kind: def.Kind,
+			Used: usedTypes[def.Name],
+		}
+		if def.Kind == ast.Object || def.Kind == ast.Interface {
+			for _, field := range def.Fields {
+				if strings.HasPrefix(field.Name, "__") {
+					continue
+				}
+				_, deprecated := deprecationReason(field.Directives)
+				fc := FieldCoverage{
+					Name:       field.Name,
+					Used:       usedFields[def.Name+"."+field.Name],
+					Deprecated: deprecated,
+				}
+				coverage.FieldsTotal++
+				if fc.Used {
+					coverage.FieldsUsed++
+				}
+				tc.Fields = append(tc.Fields, fc)
+			}
+		}
+		coverage.Types = append(coverage.Types, tc)
+	}
+	sort.Slice(coverage.Types, func(i, j int) bool {
+		return coverage.Types[i].Name < coverage.Types[j].Name
+	})
+	return coverage, nil
+}
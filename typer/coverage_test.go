@@ -0,0 +1,60 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSchemaCoverage(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				node(id: ID!): Node
+				legacy: String @deprecated
+			}
+
+			interface Node {
+				id: ID!
+			}
+
+			type User implements Node {
+				id: ID!
+				name: String!
+			}
+
+			enum Role { ADMIN }
+
+			input Filter { role: Role }
+		`,
+	})
+	tp := &Typer{Schema: schema}
+	if _, _, err := tp.VisitString("ops.ts", `{ node(id: 1) { id } }`); !assert.NoError(t, err) {
+		return
+	}
+	coverage, err := SchemaCoverage(schema, tp.GeneratedTypes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Coverage{
+		FieldsUsed:  3,
+		FieldsTotal: 5,
+		Types: []TypeCoverage{
+			{Name: "Node", Kind: ast.Interface, Used: true, Fields: []FieldCoverage{
+				{Name: "id", Used: true},
+			}},
+			{Name: "Query", Kind: ast.Object, Used: true, Fields: []FieldCoverage{
+				{Name: "node", Used: true},
+				{Name: "legacy", Deprecated: true},
+			}},
+			{Name: "Role", Kind: ast.Enum},
+			{Name: "User", Kind: ast.Object, Used: true, Fields: []FieldCoverage{
+				{Name: "id", Used: true},
+				{Name: "name"},
+			}},
+		},
+	}, coverage)
+}
@@ -0,0 +1,116 @@
+package typer
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	DeclarationData      = "data"
+	DeclarationVariables = "variables"
+	DeclarationShape     = "shape"
+	// A union of string literals declared once by Optimize.
+	DeclarationAlias = "alias"
+)
+
+// A named TypeScript type declaration.
+type Declaration struct {
+	Name string
+	Kind string
+	// Input file of the document that produced this declaration.
+	Source string
+	// Names of the generated declarations referenced by Type.
+	Dependencies []string
+	Type         Type
+}
+
+func (d Declaration) String() string {
+	return fmt.Sprintf("export type %s = %s;", d.Name, RenderType(d.Type))
+}
+
+func newDeclaration(name, kind, source string, typ Type, references map[string]bool) Declaration {
+	var deps []string
+	for ref := range references {
+		deps = append(deps, ref)
+	}
+	sort.Strings(deps)
+	return Declaration{
+		Name:         name,
+		Kind:         kind,
+		Source:       source,
+		Dependencies: deps,
+		Type:         typ,
+	}
+}
+
+// A keyed collection of declarations. Generation produces a log of
+// declarations that may contain duplicates, since each document's
+// contribution is self-contained; a DeclarationSet keeps the first of each
+// name.
+type DeclarationSet struct {
+	names  []string // Insertion order.
+	byName map[string]Declaration
+}
+
+func NewDeclarationSet(decls []Declaration) *DeclarationSet {
+	set := &DeclarationSet{
+		byName: make(map[string]Declaration, len(decls)),
+	}
+	for _, decl := range decls {
+		set.Add(decl)
+	}
+	return set
+}
+
+// Adds decl unless a declaration of the same name already exists. Reports
+// whether it was added.
+func (set *DeclarationSet) Add(decl Declaration) bool {
+	if _, exists := set.byName[decl.Name]; exists {
+		return false
+	}
+	set.names = append(set.names, decl.Name)
+	set.byName[decl.Name] = decl
+	return true
+}
+
+func (set *DeclarationSet) Get(name string) (decl Declaration, ok bool) {
+	decl, ok = set.byName[name]
+	return
+}
+
+func (set *DeclarationSet) Len() int {
+	return len(set.names)
+}
+
+// Returns declarations in insertion order, except that each declaration is
+// preceded by its dependencies.
+func (set *DeclarationSet) Ordered() []Declaration {
+	res := make([]Declaration, 0, len(set.names))
+	visited := make(map[string]bool, len(set.names))
+	var visit func(name string)
+	visit = func(name string) {
+		decl, ok := set.byName[name]
+		if !ok || visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, dep := range decl.Dependencies {
+			visit(dep)
+		}
+		res = append(res, decl)
+	}
+	for _, name := range set.names {
+		visit(name)
+	}
+	return res
+}
+
+// Groups declarations by source file, each group in dependency order.
+// Dependencies may belong to other groups.
+func (set *DeclarationSet) BySource() map[string][]Declaration {
+	res := make(map[string][]Declaration)
+	for _, decl := range set.Ordered() {
+		res[decl.Source] = append(res[decl.Source], decl)
+	}
+	return res
+}
@@ -0,0 +1,26 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclarationSet(t *testing.T) {
+	query := newDeclaration("Query_Me_Data", DeclarationData, "query.ts", RawType{Text: `{ me: Shape_1 & Fragment_User_Data; }`}, map[string]bool{
+		"Shape_1":            true,
+		"Fragment_User_Data": true,
+	})
+	shape := newDeclaration("Shape_1", DeclarationShape, "query.ts", RawType{Text: `{ id: string; }`}, nil)
+	fragment := newDeclaration("Fragment_User_Data", DeclarationData, "fragment.ts", RawType{Text: `{ name: string; }`}, nil)
+	assert.Equal(t, []string{"Fragment_User_Data", "Shape_1"}, query.Dependencies)
+
+	set := NewDeclarationSet([]Declaration{query, shape, shape, fragment})
+	assert.Equal(t, 3, set.Len())
+	assert.False(t, set.Add(shape))
+	assert.Equal(t, []Declaration{fragment, shape, query}, set.Ordered())
+	assert.Equal(t, map[string][]Declaration{
+		"query.ts":    {shape, query},
+		"fragment.ts": {fragment},
+	}, set.BySource())
+}
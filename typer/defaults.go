@@ -0,0 +1,72 @@
+package typer
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// The default values of a named operation's variables, for emitters to
+// declare as a constant, so that code can refer to the defaults the server
+// applies rather than repeat them.
+type VariableDefaults struct {
+	// Name of the constant, per Options.Naming, such as Query_GetUsers_Defaults.
+	Name string `json:"name"`
+	// Of the variables that declare defaults, in order of declaration.
+	Values []VariableDefault `json:"values"`
+}
+
+type VariableDefault struct {
+	Name string `json:"name"`
+	// TypeScript literal of the value, such as 20 or { first: 10 }. Enum
+	// values are strings, as they are sent.
+	Value string `json:"value"`
+}
+
+// Returns the defaults of def's variables, or nil if it is anonymous or none
+// declare defaults.
+func (t *Typer) variableDefaults(opKind string, def *ast.OperationDefinition) *VariableDefaults {
+	if def.Name == "" {
+		return nil
+	}
+	var values []VariableDefault
+	for _, v := range def.VariableDefinitions {
+		if v.DefaultValue != nil {
+			values = append(values, VariableDefault{Name: v.Variable, Value: valueLiteral(v.DefaultValue)})
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return &VariableDefaults{
+		Name:   t.declarationName(opKind, def.Name, "Defaults"),
+		Values: values,
+	}
+}
+
+// Returns the TypeScript literal of a constant GraphQL value.
+func valueLiteral(v *ast.Value) string {
+	switch v.Kind {
+	case ast.StringValue, ast.BlockValue, ast.EnumValue:
+		return StringToJSON(v.Raw)
+	case ast.NullValue:
+		return "null"
+	case ast.ListValue:
+		items := make([]string, len(v.Children))
+		for i, child := range v.Children {
+			items[i] = valueLiteral(child.Value)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case ast.ObjectValue:
+		if len(v.Children) == 0 {
+			return "{}"
+		}
+		fields := make([]string, len(v.Children))
+		for i, child := range v.Children {
+			fields[i] = child.Name + ": " + valueLiteral(child.Value)
+		}
+		return "{ " + strings.Join(fields, ", ") + " }"
+	default:
+		return v.Raw
+	}
+}
@@ -0,0 +1,65 @@
+package typer
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Returns a warning for each use of a deprecated field or argument in doc.
+// Spreads are not followed, so that uses within a fragment are reported only
+// by the document that defines it.
+func deprecationWarnings(doc *ast.QueryDocument) []error {
+	var warnings []error
+	var walk func(selections ast.SelectionSet)
+	walk = func(selections ast.SelectionSet) {
+		for _, selection := range selections {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				if def := selection.Definition; def != nil {
+					if reason, ok := deprecationReason(def.Directives); ok {
+						warnings = append(warnings, deprecationWarning(selection.Position,
+							`The field "%s.%s" is deprecated. %s`, selection.ObjectDefinition.Name, selection.Name, reason))
+					}
+					for _, arg := range selection.Arguments {
+						argDef := def.Arguments.ForName(arg.Name)
+						if argDef == nil {
+							continue
+						}
+						if reason, ok := deprecationReason(argDef.Directives); ok {
+							warnings = append(warnings, deprecationWarning(arg.Position,
+								`The argument "%s" of "%s.%s" is deprecated. %s`, arg.Name, selection.ObjectDefinition.Name, selection.Name, reason))
+						}
+					}
+				}
+				walk(selection.SelectionSet)
+			case *ast.InlineFragment:
+				walk(selection.SelectionSet)
+			}
+		}
+	}
+	for _, op := range doc.Operations {
+		walk(op.SelectionSet)
+	}
+	for _, fragment := range doc.Fragments {
+		walk(fragment.SelectionSet)
+	}
+	return warnings
+}
+
+func deprecationReason(directives ast.DirectiveList) (reason string, deprecated bool) {
+	directive := directives.ForName("deprecated")
+	if directive == nil {
+		return "", false
+	}
+	reason = "No longer supported"
+	if arg := directive.Arguments.ForName("reason"); arg != nil && arg.Value != nil {
+		reason = arg.Value.Raw
+	}
+	return reason, true
+}
+
+func deprecationWarning(pos *ast.Position, message string, args ...interface{}) error {
+	err := gqlerror.ErrorPosf(pos, message, args...)
+	err.Rule = "NoDeprecated"
+	return err
+}
@@ -0,0 +1,50 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestDeprecations(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID, login: String @deprecated(reason: "Use id.")): User
+				viewer: User @deprecated
+			}
+
+			type User {
+				name: String
+			}
+		`,
+	})
+	query := "query Q {\n  user(login: \"x\") { name }\n  viewer { name }\n}"
+
+	typer := &Typer{
+		Schema: schema,
+	}
+	_, warnings, err := typer.VisitString("q.ts", query)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	typer = &Typer{
+		Schema: schema,
+		Options: Options{
+			WarnDeprecated: true,
+		},
+	}
+	_, warnings, err = typer.VisitString("q.ts", query)
+	assert.NoError(t, err)
+	var messages []string
+	for _, warning := range warnings {
+		messages = append(messages, warning.Error())
+	}
+	assert.Equal(t, []string{
+		`q.ts:2: The argument "login" of "Query.user" is deprecated. Use id.`,
+		`q.ts:3: The field "Query.viewer" is deprecated. No longer supported`,
+	}, messages)
+}
@@ -0,0 +1,9 @@
+// Package typer translates GraphQL operations and fragments to TypeScript
+// types, given a schema. It is the engine behind the extractgqlts command and
+// may be used directly by other tools.
+//
+// The exported API of this package is stable: exported identifiers will not
+// be removed or change meaning without a major version bump, and new Options
+// will always default to existing behavior. The exact text of generated types
+// is not covered by this guarantee, although it changes only deliberately.
+package typer
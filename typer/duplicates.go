@@ -0,0 +1,196 @@
+package typer
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Reported when distinct documents define operations, or fragments, of the
+// same name, whose generated declarations would then collide, keeping only
+// those of the first.
+type DuplicateNameError struct {
+	// Either "operation" or "fragment".
+	Kind string
+	Name string
+	// Where each of the distinct definitions is, in order of appearance.
+	Locations []Location
+}
+
+func (e *DuplicateNameError) Error() string {
+	var others []string
+	for _, loc := range e.Locations[1:] {
+		others = append(others, loc.String())
+	}
+	return fmt.Sprintf("%s: %s %q is also defined at %s", e.Locations[0], e.Kind, e.Name, strings.Join(others, ", "))
+}
+
+// Returns an error for each name defined by more than one distinct document
+// of types, in order of first appearance. Repeated documents are not
+// duplicates, since their declarations are identical; see DuplicateDocuments.
+func DuplicateNames(types GeneratedTypes) []*DuplicateNameError {
+	type key struct{ kind, name string }
+	var order []key
+	defs := make(map[key]*DuplicateNameError)
+	queries := make(map[key]map[string]bool)
+	for _, entry := range types.QueryMap {
+		if entry.Name == "" {
+			continue
+		}
+		k := key{kind: "operation", name: entry.Name}
+		if entry.Operation == OperationFragment {
+			k.kind = "fragment"
+		}
+		if defs[k] == nil {
+			order = append(order, k)
+			defs[k] = &DuplicateNameError{Kind: k.kind, Name: k.name}
+			queries[k] = make(map[string]bool)
+		}
+		normalized := normalizeQuery(entry.Query)
+		if queries[k][normalized] {
+			continue
+		}
+		queries[k][normalized] = true
+		defs[k].Locations = append(defs[k].Locations, entry.Location)
+	}
+	var errs []*DuplicateNameError
+	for _, k := range order {
+		if len(defs[k].Locations) > 1 {
+			errs = append(errs, defs[k])
+		}
+	}
+	return errs
+}
+
+// Reported when a document is repeated, whether verbatim, so that its key
+// occurs more than once in the QueryTypes map, or differing only in
+// formatting, comments, and the order of its selections, arguments,
+// variables, and fragments, so that the map and persisted query manifests
+// have several entries for it.
+type DuplicateDocumentError struct {
+	// Such as "query GetUser", "anonymous query", or "fragment UserFields".
+	Definition string
+	// Whether the documents differ in formatting or order, rather than being
+	// identical.
+	Reformatted bool
+	// Where each of the documents is, in order of appearance.
+	Locations []Location
+}
+
+func (e *DuplicateDocumentError) Error() string {
+	var others []string
+	for _, loc := range e.Locations[1:] {
+		others = append(others, loc.String())
+	}
+	if e.Reformatted {
+		return fmt.Sprintf("%s: the document of %s is repeated, but for formatting or order, at %s. Share one copy of it, as a constant or fragment, so that it has one entry in the QueryTypes map and persisted query manifests", e.Locations[0], e.Definition, strings.Join(others, ", "))
+	}
+	return fmt.Sprintf("%s: the document of %s is repeated verbatim at %s", e.Locations[0], e.Definition, strings.Join(others, ", "))
+}
+
+// Returns an error for each document of types that is repeated, in order of
+// first appearance.
+func DuplicateDocuments(types GeneratedTypes) []*DuplicateDocumentError {
+	var order []string
+	dups := make(map[string]*DuplicateDocumentError)
+	queries := make(map[string]string)
+	for _, entry := range types.QueryMap {
+		normalized := normalizeQuery(entry.Query)
+		dup := dups[normalized]
+		if dup == nil {
+			order = append(order, normalized)
+			dup = &DuplicateDocumentError{Definition: definitionName(entry)}
+			dups[normalized] = dup
+			queries[normalized] = entry.Query
+		}
+		if entry.Query != queries[normalized] {
+			dup.Reformatted = true
+		}
+		dup.Locations = append(dup.Locations, entry.Location)
+	}
+	var errs []*DuplicateDocumentError
+	for _, normalized := range order {
+		if len(dups[normalized].Locations) > 1 {
+			errs = append(errs, dups[normalized])
+		}
+	}
+	return errs
+}
+
+// Returns query formatted canonically, without comments, and with its
+// selections, arguments, variables, and fragments sorted, or query itself if
+// it cannot be parsed.
+func normalizeQuery(query string) string {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return query
+	}
+	for _, op := range doc.Operations {
+		sort.SliceStable(op.VariableDefinitions, func(i, j int) bool {
+			return op.VariableDefinitions[i].Variable < op.VariableDefinitions[j].Variable
+		})
+		for _, v := range op.VariableDefinitions {
+			sortValue(v.DefaultValue)
+		}
+		sortSelectionSet(op.SelectionSet)
+	}
+	for _, fragment := range doc.Fragments {
+		sortSelectionSet(fragment.SelectionSet)
+	}
+	sort.SliceStable(doc.Fragments, func(i, j int) bool {
+		return doc.Fragments[i].Name < doc.Fragments[j].Name
+	})
+	return formatQuery(doc)
+}
+
+func formatQuery(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String()
+}
+
+// Sorts set and the selection sets within it by their formatted selections.
+// The order of directives is kept, since it may be significant.
+func sortSelectionSet(set ast.SelectionSet) {
+	keys := make(map[ast.Selection]string, len(set))
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			sort.SliceStable(selection.Arguments, func(i, j int) bool {
+				return selection.Arguments[i].Name < selection.Arguments[j].Name
+			})
+			for _, arg := range selection.Arguments {
+				sortValue(arg.Value)
+			}
+			sortSelectionSet(selection.SelectionSet)
+		case *ast.InlineFragment:
+			sortSelectionSet(selection.SelectionSet)
+		}
+		keys[selection] = formatQuery(&ast.QueryDocument{
+			Operations: ast.OperationList{{Operation: ast.Query, SelectionSet: ast.SelectionSet{selection}}},
+		})
+	}
+	sort.SliceStable(set, func(i, j int) bool {
+		return keys[set[i]] < keys[set[j]]
+	})
+}
+
+// Sorts the fields of object values within value by name.
+func sortValue(value *ast.Value) {
+	if value == nil {
+		return
+	}
+	for _, child := range value.Children {
+		sortValue(child.Value)
+	}
+	if value.Kind == ast.ObjectValue {
+		sort.SliceStable(value.Children, func(i, j int) bool {
+			return value.Children[i].Name < value.Children[j].Name
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateNames(t *testing.T) {
+	a := Location{File: "a.ts", Line: 1, Column: 1}
+	b := Location{File: "b.ts", Line: 2, Column: 3}
+	c := Location{File: "c.ts", Line: 4, Column: 5}
+	types := GeneratedTypes{
+		QueryMap: []QueryType{
+			{Query: "query X { a }", Operation: OperationQuery, Name: "X", Location: a},
+			{Query: "fragment X on T { a }", Operation: OperationFragment, Name: "X", Location: a},
+			{Query: "query X { a }", Operation: OperationQuery, Name: "X", Location: b},
+			{Query: "mutation X { b }", Operation: OperationMutation, Name: "X", Location: c},
+			{Query: "query X {\n  a # Reformatted.\n}", Operation: OperationQuery, Name: "X", Location: c},
+			{Query: "{ a }", Operation: OperationQuery, Location: b},
+			{Query: "{ b }", Operation: OperationQuery, Location: c},
+		},
+	}
+	errs := DuplicateNames(types)
+	assert.Equal(t, []*DuplicateNameError{
+		{Kind: "operation", Name: "X", Locations: []Location{a, c}},
+	}, errs)
+	assert.EqualError(t, errs[0], `a.ts:1:1: operation "X" is also defined at c.ts:4:5`)
+}
+
+func TestDuplicateDocuments(t *testing.T) {
+	a := Location{File: "a.ts", Line: 1, Column: 1}
+	b := Location{File: "b.ts", Line: 2, Column: 3}
+	c := Location{File: "c.ts", Line: 4, Column: 5}
+	types := GeneratedTypes{
+		QueryMap: []QueryType{
+			{Query: "query X { a }", Operation: OperationQuery, Name: "X", Location: a},
+			{Query: "{ b }", Operation: OperationQuery, Location: a},
+			{Query: "query X { a }", Operation: OperationQuery, Name: "X", Location: b},
+			{Query: "{ c }", Operation: OperationQuery, Location: b},
+			{Query: "{\n  b # Reformatted.\n}", Operation: OperationQuery, Location: c},
+			{Query: "query Y($a: Int, $b: Int) { f(x: $a, y: {p: 1, q: 2}) { ...G } g ...F }", Operation: OperationQuery, Name: "Y", Location: a},
+			{Query: "query Y($b: Int, $a: Int) { ...F g f(y: {q: 2, p: 1}, x: $a) { ...G } }", Operation: OperationQuery, Name: "Y", Location: c},
+			{Query: "query Y($b: Int, $a: Int) { ...F g f(y: {q: 2, p: 1}, x: $b) { ...G } }", Operation: OperationQuery, Name: "Y", Location: b},
+		},
+	}
+	errs := DuplicateDocuments(types)
+	assert.Equal(t, []*DuplicateDocumentError{
+		{Definition: "query X", Locations: []Location{a, b}},
+		{Definition: "anonymous query", Reformatted: true, Locations: []Location{a, c}},
+		{Definition: "query Y", Reformatted: true, Locations: []Location{a, c}},
+	}, errs)
+	assert.EqualError(t, errs[0], `a.ts:1:1: the document of query X is repeated verbatim at b.ts:2:3`)
+	assert.EqualError(t, errs[1], `a.ts:1:1: the document of anonymous query is repeated, but for formatting or order, at c.ts:4:5. Share one copy of it, as a constant or fragment, so that it has one entry in the QueryTypes map and persisted query manifests`)
+}
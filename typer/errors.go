@@ -0,0 +1,38 @@
+package typer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Returned for documents without any operation or fragment definitions.
+var ErrNoDefinitions = errors.New("no definitions")
+
+// Returned for documents with more than one operation definition, or with
+// more than one fragment definition and no operation.
+type MultipleDefinitionsError struct {
+	// Either "operation" or "fragment".
+	Kind  string
+	Count int
+}
+
+func (e *MultipleDefinitionsError) Error() string {
+	return fmt.Sprintf("expected at most one %s definition, found %d", e.Kind, e.Count)
+}
+
+// Returned for documents that cannot be parsed or that are invalid against
+// the schema. Unwraps to its gqlerror.List.
+type ValidationError struct {
+	Errors gqlerror.List
+}
+
+func (e *ValidationError) Error() string {
+	return strings.TrimSuffix(e.Errors.Error(), "\n")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Errors
+}
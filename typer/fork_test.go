@@ -0,0 +1,54 @@
+package typer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestForkConcurrently(t *testing.T) {
+	schema := syntheticSchema(50)
+	documents := make([]string, 40)
+	for i := range documents {
+		documents[i] = wideQuery(fmt.Sprintf("Op%d", i), 10)
+	}
+	typer := &Typer{
+		Schema: schema,
+	}
+	for _, document := range documents {
+		typer.PrepareString("", document)
+	}
+
+	results := make([]GeneratedTypes, len(documents))
+	done := make(chan struct{})
+	for w := 0; w < 4; w++ {
+		go func(w int) {
+			fork := typer.Fork()
+			for i := w; i < len(documents); i += 4 {
+				fork.GeneratedTypes = GeneratedTypes{}
+				if _, _, err := fork.VisitString("", documents[i]); err != nil {
+					t.Error(err)
+				}
+				results[i] = fork.GeneratedTypes
+			}
+			done <- struct{}{}
+		}(w)
+	}
+	for w := 0; w < 4; w++ {
+		<-done
+	}
+
+	serial := &Typer{
+		Schema: schema,
+	}
+	var merged GeneratedTypes
+	for i, document := range documents {
+		if _, _, err := serial.VisitString("", document); err != nil {
+			t.Fatal(err)
+		}
+		merged.Append(results[i])
+	}
+	if !reflect.DeepEqual(serial.GeneratedTypes, merged) {
+		t.Error("concurrent results differ from serial results")
+	}
+}
@@ -0,0 +1,195 @@
+package typer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Fragments defined in fragment-only documents are shared project-wide.
+// Each shared fragment is validated once, along with the rest of its own
+// document. Documents that spread a shared fragment are validated without it
+// inlined; the spread is linked to the shared definition afterwards, and only
+// the checks that depend on the spread site are repeated.
+
+type preparedDocument struct {
+	doc      *ast.QueryDocument
+	sent     string
+	warnings []error
+	err      error
+}
+
+func preparedKey(filename, gql string) string {
+	return filename + "\x00" + gql
+}
+
+// Parses a document ahead of typing. If the document contains only fragment
+// definitions, those fragments become available to every other document.
+// Callers should prepare all documents before visiting any of them.
+func (t *Typer) PrepareString(filename, gql string) {
+	doc, gqlErr := parser.ParseQuery(&ast.Source{
+		Name:  filename,
+		Input: gql,
+	})
+	if gqlErr != nil {
+		t.setPrepared(filename, gql, &preparedDocument{err: &ValidationError{Errors: gqlerror.List{gqlErr}}})
+		return
+	}
+	if len(doc.Operations) > 0 {
+		return
+	}
+	sent := t.transform(doc)
+	if t.fragments == nil {
+		t.fragments = make(map[string]*ast.FragmentDefinition)
+	}
+	for _, fragment := range doc.Fragments {
+		t.fragments[fragment.Name] = fragment
+	}
+	t.setPrepared(filename, gql, &preparedDocument{doc: doc, sent: sent})
+}
+
+func (t *Typer) setPrepared(filename, gql string, prepared *preparedDocument) {
+	if t.prepared == nil {
+		t.prepared = make(map[string]*preparedDocument)
+	}
+	t.prepared[preparedKey(filename, gql)] = prepared
+}
+
+// Summarizes the source text of all shared fragments. Any change to a shared
+// fragment may change the types of the documents that spread it.
+func (t *Typer) FragmentsDigest() string {
+	names := make([]string, 0, len(t.fragments))
+	for name := range t.fragments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(t.fragments[name].Position.Src.Input))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Links spreads of shared fragments and drops the corresponding unknown
+// fragment diagnostics. Diagnostics of fragments that remain unknown suggest
+// similarly named ones.
+func (t *Typer) linkSharedFragments(doc *ast.QueryDocument, diags gqlerror.List) gqlerror.List {
+	linked := make(map[gqlerror.Location]bool)
+	unknown := make(map[gqlerror.Location]string)
+	var linkErrs gqlerror.List
+	link := func(spread *ast.FragmentSpread) {
+		if spread.Definition != nil {
+			return
+		}
+		loc := gqlerror.Location{
+			Line:   spread.Position.Line,
+			Column: spread.Position.Column,
+		}
+		shared := t.fragments[spread.Name]
+		if shared == nil {
+			unknown[loc] = spread.Name
+			return
+		}
+		spread.Definition = shared
+		linked[loc] = true
+		if !t.spreadPossible(spread.ObjectDefinition, shared.TypeCondition) {
+			linkErr := gqlerror.ErrorPosf(spread.Position,
+				`Fragment "%s" cannot be spread here as objects of type "%s" can never be of type "%s".`,
+				spread.Name, spread.ObjectDefinition.Name, shared.TypeCondition)
+			linkErr.Rule = "PossibleFragmentSpreads"
+			linkErrs = append(linkErrs, linkErr)
+		}
+	}
+	for _, op := range doc.Operations {
+		walkFragmentSpreads(op.SelectionSet, link)
+	}
+	for _, fragment := range doc.Fragments {
+		walkFragmentSpreads(fragment.SelectionSet, link)
+	}
+	if len(linked) == 0 && len(unknown) == 0 {
+		return diags
+	}
+
+	res := make(gqlerror.List, 0, len(diags)+len(linkErrs))
+	for _, diag := range diags {
+		if diag.Rule == "KnownFragmentNames" && len(diag.Locations) > 0 {
+			if linked[diag.Locations[0]] {
+				continue
+			}
+			if name, ok := unknown[diag.Locations[0]]; ok {
+				diag.Message += t.fragmentSuggestions(doc, name)
+			}
+		}
+		res = append(res, diag)
+	}
+	return append(res, linkErrs...)
+}
+
+// Returns a sentence suggesting fragments named similarly to name, each with
+// the file that defines it if it is shared, or else the empty string.
+func (t *Typer) fragmentSuggestions(doc *ast.QueryDocument, name string) string {
+	files := make(map[string]string)
+	for _, fragment := range t.fragments {
+		files[fragment.Name] = fragment.Position.Src.Name
+	}
+	for _, fragment := range doc.Fragments {
+		files[fragment.Name] = ""
+	}
+	options := make([]string, 0, len(files))
+	for option := range files {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	suggestions := validator.SuggestionList(name, options)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	for i, suggestion := range suggestions {
+		if file := files[suggestion]; file != "" {
+			suggestions[i] = fmt.Sprintf("%q in %s", suggestion, file)
+		} else {
+			suggestions[i] = fmt.Sprintf("%q", suggestion)
+		}
+	}
+	return " Did you mean " + validator.OrList(suggestions...) + "?"
+}
+
+func (t *Typer) spreadPossible(parent *ast.Definition, typeCondition string) bool {
+	if parent == nil {
+		return true
+	}
+	fragmentType := t.Schema.Types[typeCondition]
+	if fragmentType == nil || !fragmentType.IsCompositeType() {
+		return true
+	}
+	for _, fragmentDef := range t.Schema.GetPossibleTypes(fragmentType) {
+		for _, parentDef := range t.Schema.GetPossibleTypes(parent) {
+			if parentDef.Name == fragmentDef.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func walkFragmentSpreads(selections ast.SelectionSet, f func(spread *ast.FragmentSpread)) {
+	for _, selection := range selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			walkFragmentSpreads(selection.SelectionSet, f)
+		case *ast.InlineFragment:
+			walkFragmentSpreads(selection.SelectionSet, f)
+		case *ast.FragmentSpread:
+			f(selection)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSharedFragments(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				currentUser: User
+				status: Status
+			}
+
+			type User {
+				name: String!
+			}
+
+			union Status = Green | Red
+
+			type Green {
+				ok: Boolean!
+			}
+
+			type Red {
+				message: String!
+			}
+		`,
+	})
+	typer := &Typer{
+		Schema: schema,
+	}
+	fragments := `fragment UserFields on User { name }`
+	query := `query Me { currentUser { ...UserFields } }`
+	bad := `query Bad { status { ...UserFields } }`
+	typer.PrepareString("fragments.ts", fragments)
+	typer.PrepareString("query.ts", query)
+	typer.PrepareString("bad.ts", bad)
+
+	_, warnings, err := typer.VisitString("query.ts", query)
+	assert.Empty(t, warnings)
+	assert.NoError(t, err)
+	_, _, err = typer.VisitString("fragments.ts", fragments)
+	assert.NoError(t, err)
+	_, _, err = typer.VisitString("bad.ts", bad)
+	assert.EqualError(t, err, "bad.ts:1: Fragment \"UserFields\" cannot be spread here as objects of type \"Status\" can never be of type \"User\".")
+	var validationErr *ValidationError
+	if assert.ErrorAs(t, err, &validationErr) {
+		assert.Len(t, validationErr.Errors, 1)
+	}
+
+	assert.Equal(t, []string{
+		`export type Query_Me_Data = { __typename: "Query"; currentUser: (({ __typename: "User"; } & Fragment_UserFields_Data) | null); };`,
+		`export type Query_Me_Variables = { };`,
+		`export type Fragment_UserFields_Data = { __typename: "User"; name: string; };`,
+		`export type Fragment_UserFields_Variables = { };`,
+	}, renderTypes(typer.GeneratedTypes).Declarations)
+}
+
+func TestUnknownFragmentSuggestions(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				currentUser: User
+			}
+
+			type User {
+				name: String!
+			}
+		`,
+	})
+	typer := &Typer{
+		Schema: schema,
+	}
+	typer.PrepareString("fragments.ts", `fragment UserFields on User { name }`)
+
+	_, _, err := typer.VisitString("query.ts", `query Me { currentUser { ...UserFeilds ...UserName } } fragment UserNames on User { name }`)
+	assert.EqualError(t, err, `query.ts:1: Unknown fragment "UserFeilds". Did you mean "UserFields" in fragments.ts or "UserNames"?
+query.ts:1: Unknown fragment "UserName". Did you mean "UserNames"?`)
+
+	_, _, err = typer.VisitString("query.ts", `query Me { currentUser { ...Unrelated } }`)
+	assert.EqualError(t, err, `query.ts:1: Unknown fragment "Unrelated".`)
+}
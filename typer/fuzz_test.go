@@ -0,0 +1,48 @@
+//go:build go1.18
+
+package typer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Types arbitrary documents against the schema of testdata/cases, which must
+// fail with errors rather than panic. Seeded with the inputs of the cases.
+// Run with go test ./typer -fuzz FuzzVisitString.
+func FuzzVisitString(f *testing.F) {
+	schemaText, err := ioutil.ReadFile("testdata/cases/schema.graphql")
+	if err != nil {
+		f.Fatal(err)
+	}
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: string(schemaText)})
+	inputs, err := filepath.Glob("testdata/cases/*/*.graphql")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, input := range inputs {
+		bs, err := ioutil.ReadFile(input)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(bs))
+	}
+	f.Add(`{ status { ... on Named { name } ... on Green { ok } } }`)
+	f.Add(`query ($id: Instant) { userById(id: $id) { ...F } } fragment F on Status { __typename }`)
+	f.Add(`fragment F on Query { userById(id: $id) { name } }`)
+	f.Fuzz(func(t *testing.T, gql string) {
+		typer := &Typer{
+			Schema: schema,
+			Options: Options{
+				UnknownDirectives: UnknownDirectivesAllow,
+				Lint:              map[string]LintConfig{"require-operation-name": {Severity: LintWarning}},
+			},
+		}
+		typer.PrepareString("input.graphql", gql)
+		typer.VisitString("input.graphql", gql)
+	})
+}
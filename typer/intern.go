@@ -0,0 +1,73 @@
+package typer
+
+import "github.com/vektah/gqlparser/v2/ast"
+
+// Type unions depend only on the schema, yet the same few are computed over
+// and over again: once per selection of an abstract type and once per
+// fragment narrowing it. Wide interfaces make each computation expensive, so
+// unions are interned per schema and their canonical strings are shared.
+
+type unionKey struct {
+	a, b string
+}
+
+type unionInterner struct {
+	schema        *ast.Schema
+	concrete      map[string]typeUnion   // definition name -> concrete union.
+	intersections map[unionKey]typeUnion // canonical pair -> intersection.
+	canonicals    map[string]string
+	typenames     map[string]Type // canonical -> type of __typename.
+}
+
+func (t *Typer) interner() *unionInterner {
+	if t.unions == nil || t.unions.schema != t.Schema {
+		t.unions = &unionInterner{
+			schema:        t.Schema,
+			concrete:      make(map[string]typeUnion),
+			intersections: make(map[unionKey]typeUnion),
+			canonicals:    make(map[string]string),
+			typenames:     make(map[string]Type),
+		}
+	}
+	return t.unions
+}
+
+func (in *unionInterner) intern(u typeUnion) typeUnion {
+	if canonical, ok := in.canonicals[u.canonical]; ok {
+		u.canonical = canonical
+	} else {
+		in.canonicals[u.canonical] = u.canonical
+	}
+	return u
+}
+
+func (t *Typer) toConcreteUnion(def *ast.Definition) typeUnion {
+	in := t.interner()
+	if u, ok := in.concrete[def.Name]; ok {
+		return u
+	}
+	u := in.intern(t.computeConcreteUnion(def))
+	in.concrete[def.Name] = u
+	return u
+}
+
+func (t *Typer) intersectUnions(a, b typeUnion) typeUnion {
+	in := t.interner()
+	key := unionKey{a.canonical, b.canonical}
+	if u, ok := in.intersections[key]; ok {
+		return u
+	}
+	u := in.intern(intersectUnions(a, b))
+	in.intersections[key] = u
+	return u
+}
+
+func (t *Typer) typenameType(u typeUnion) Type {
+	in := t.interner()
+	if typ, ok := in.typenames[u.canonical]; ok {
+		return typ
+	}
+	typ := typenameType(u)
+	in.typenames[u.canonical] = typ
+	return typ
+}
@@ -1,4 +1,4 @@
-package internal
+package typer
 
 import "encoding/json"
 
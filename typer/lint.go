@@ -0,0 +1,282 @@
+package typer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Lint rules check conventions that valid documents may still break. Each
+// rule is off unless enabled by Options.Lint. Problems are reported as
+// warnings or, at LintError, as validation errors, with the rule's name
+// appended to the message.
+
+type LintSeverity int
+
+const (
+	LintOff LintSeverity = iota
+	LintWarning
+	LintError
+)
+
+// Configures a lint rule.
+type LintConfig struct {
+	Severity LintSeverity
+	// A limit for rules that have one, such as the greatest depth allowed by
+	// max-depth. Zero selects the rule's default.
+	Limit int
+}
+
+type LintRule struct {
+	Name        string
+	Description string
+	// Limit used when the rule's LintConfig has none. Zero for rules without
+	// a limit.
+	DefaultLimit int
+	// Checks a document before it is typed.
+	check func(t *Typer, doc *ast.QueryDocument, limit int) []*gqlerror.Error
+	// Checks the types generated for a document instead, which fail the
+	// document at LintError.
+	checkTypes func(measures []typeMeasure, limit int) []*gqlerror.Error
+}
+
+// All lint rules, in order of name.
+var LintRules = []LintRule{
+	{
+		Name:         "max-depth",
+		Description:  "operations nest fields at most limit deep, counting fields of spread fragments",
+		DefaultLimit: 10,
+		check:        checkMaxDepth,
+	},
+	{
+		Name:         "max-type-size",
+		Description:  "the data types generated for operations and fragments have at most limit nodes",
+		DefaultLimit: 2000,
+		checkTypes:   checkMaxTypeSize,
+	},
+	{
+		Name:         "max-union-width",
+		Description:  "the generated types have unions of at most limit members, including those of __typename",
+		DefaultLimit: 50,
+		checkTypes:   checkMaxUnionWidth,
+	},
+	{
+		Name:        "no-deprecated",
+		Description: "operations and fragments use no deprecated fields or arguments",
+		check: func(t *Typer, doc *ast.QueryDocument, limit int) []*gqlerror.Error {
+			var errs []*gqlerror.Error
+			for _, warning := range deprecationWarnings(doc) {
+				errs = append(errs, warning.(*gqlerror.Error))
+			}
+			return errs
+		},
+	},
+	{
+		Name:        "require-id",
+		Description: "selections of types with an id field include it, as normalized caches need",
+		check:       checkRequireID,
+	},
+	{
+		Name:        "require-operation-name",
+		Description: "operations are named",
+		check: func(t *Typer, doc *ast.QueryDocument, limit int) []*gqlerror.Error {
+			var errs []*gqlerror.Error
+			for _, op := range doc.Operations {
+				if op.Name == "" {
+					errs = append(errs, gqlerror.ErrorPosf(op.Position,
+						"Anonymous %s; give it a name.", op.Operation))
+				}
+			}
+			return errs
+		},
+	},
+}
+
+// Returns the rule of the given name.
+func LintRuleByName(name string) (LintRule, bool) {
+	for _, rule := range LintRules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return LintRule{}, false
+}
+
+// Returns the names of all lint rules.
+func LintRuleNames() []string {
+	names := make([]string, len(LintRules))
+	for i, rule := range LintRules {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+// Parses the configuration of a rule written as NAME[=SEVERITY[,LIMIT]], such
+// as max-depth=error,8, where SEVERITY is off, warning, or error. The severity
+// defaults to warning.
+func ParseLint(spec string) (name string, config LintConfig, err error) {
+	name, rest := spec, ""
+	if eq := strings.IndexByte(spec, '='); eq >= 0 {
+		name, rest = spec[:eq], spec[eq+1:]
+	}
+	if _, ok := LintRuleByName(name); !ok {
+		return "", LintConfig{}, fmt.Errorf("unknown lint rule %q, expected one of: %s", name, strings.Join(LintRuleNames(), ", "))
+	}
+	severity, limit := rest, ""
+	if comma := strings.IndexByte(rest, ','); comma >= 0 {
+		severity, limit = rest[:comma], rest[comma+1:]
+	}
+	switch severity {
+	case "", "warning":
+		config.Severity = LintWarning
+	case "error":
+		config.Severity = LintError
+	case "off":
+		config.Severity = LintOff
+	default:
+		return "", LintConfig{}, fmt.Errorf("invalid severity %q of lint rule %s, expected off, warning, or error", severity, name)
+	}
+	if limit != "" {
+		config.Limit, err = strconv.Atoi(limit)
+		if err != nil || config.Limit <= 0 {
+			return "", LintConfig{}, fmt.Errorf("invalid limit %q of lint rule %s, expected a positive integer", limit, name)
+		}
+	}
+	return name, config, nil
+}
+
+// Runs the enabled lint rules on doc, which must have been validated.
+func (t *Typer) lint(doc *ast.QueryDocument) (warnings []error, errs gqlerror.List) {
+	return t.runLint(func(rule LintRule, limit int) []*gqlerror.Error {
+		if rule.check == nil {
+			return nil
+		}
+		return rule.check(t, doc, limit)
+	})
+}
+
+// Runs the enabled lint rules that check the types generated for a document.
+func (t *Typer) lintTypes(measures []typeMeasure) (warnings []error, errs gqlerror.List) {
+	return t.runLint(func(rule LintRule, limit int) []*gqlerror.Error {
+		if rule.checkTypes == nil {
+			return nil
+		}
+		return rule.checkTypes(measures, limit)
+	})
+}
+
+func (t *Typer) runLint(check func(rule LintRule, limit int) []*gqlerror.Error) (warnings []error, errs gqlerror.List) {
+	for _, rule := range LintRules {
+		config := t.Options.Lint[rule.Name]
+		if config.Severity == LintOff {
+			continue
+		}
+		limit := config.Limit
+		if limit == 0 {
+			limit = rule.DefaultLimit
+		}
+		for _, err := range check(rule, limit) {
+			err.Message = fmt.Sprintf("%s (%s)", err.Message, rule.Name)
+			err.Rule = rule.Name
+			if config.Severity == LintError {
+				errs = append(errs, err)
+			} else {
+				warnings = append(warnings, err)
+			}
+		}
+	}
+	return warnings, errs
+}
+
+func checkMaxDepth(t *Typer, doc *ast.QueryDocument, limit int) []*gqlerror.Error {
+	var errs []*gqlerror.Error
+	for _, op := range doc.Operations {
+		if depth := selectionDepth(op.SelectionSet, make(map[string]bool)); depth > limit {
+			errs = append(errs, gqlerror.ErrorPosf(op.Position, "Operation has depth %d, exceeding the limit of %d.", depth, limit))
+		}
+	}
+	return errs
+}
+
+// Returns the greatest nesting of fields in selections, where top-level
+// fields have depth 1. Spreading guards against cyclic fragments.
+func selectionDepth(selections ast.SelectionSet, spreading map[string]bool) int {
+	depth := 0
+	for _, selection := range selections {
+		d := 0
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d = 1 + selectionDepth(selection.SelectionSet, spreading)
+		case *ast.InlineFragment:
+			d = selectionDepth(selection.SelectionSet, spreading)
+		case *ast.FragmentSpread:
+			if selection.Definition != nil && !spreading[selection.Name] {
+				spreading[selection.Name] = true
+				d = selectionDepth(selection.Definition.SelectionSet, spreading)
+				spreading[selection.Name] = false
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+func checkRequireID(t *Typer, doc *ast.QueryDocument, limit int) []*gqlerror.Error {
+	var errs []*gqlerror.Error
+	var walk func(selections ast.SelectionSet)
+	walk = func(selections ast.SelectionSet) {
+		for _, selection := range selections {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				if len(selection.SelectionSet) == 0 || selection.Definition == nil {
+					continue
+				}
+				typ := t.getDefinition(selection.Definition.Type.Name())
+				if typ != nil && typ.Fields.ForName("id") != nil && !selectsField(selection.SelectionSet, "id", make(map[string]bool)) {
+					errs = append(errs, gqlerror.ErrorPosf(selection.Position,
+						`Selections of "%s" must include "id".`, typ.Name))
+				}
+				walk(selection.SelectionSet)
+			case *ast.InlineFragment:
+				walk(selection.SelectionSet)
+			}
+		}
+	}
+	for _, op := range doc.Operations {
+		walk(op.SelectionSet)
+	}
+	for _, fragment := range doc.Fragments {
+		walk(fragment.SelectionSet)
+	}
+	return errs
+}
+
+// Reports whether selections include the field of the given name, unaliased,
+// directly or through fragments.
+func selectsField(selections ast.SelectionSet, name string, spreading map[string]bool) bool {
+	for _, selection := range selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name == name && (selection.Alias == "" || selection.Alias == name) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectsField(selection.SelectionSet, name, spreading) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if selection.Definition != nil && !spreading[selection.Name] {
+				spreading[selection.Name] = true
+				if selectsField(selection.Definition.SelectionSet, name, spreading) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestLint(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID): User
+				viewer: User @deprecated
+			}
+
+			type User {
+				id: ID!
+				name: String
+				friends: [User!]!
+				profile: Profile
+			}
+
+			type Profile {
+				bio: String
+			}
+		`,
+	})
+	lint := func(rules map[string]LintConfig, query string) (warnings []string, errs []string) {
+		typer := &Typer{
+			Schema:  schema,
+			Options: Options{Lint: rules},
+		}
+		_, ws, err := typer.VisitString("q.ts", query)
+		for _, warning := range ws {
+			warnings = append(warnings, warning.Error())
+		}
+		if err != nil {
+			for _, e := range err.(*ValidationError).Errors {
+				errs = append(errs, e.Error())
+			}
+		}
+		return warnings, errs
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		warnings, errs := lint(nil, "{ viewer { name } }")
+		assert.Empty(t, warnings)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("require-operation-name", func(t *testing.T) {
+		warnings, errs := lint(map[string]LintConfig{
+			"require-operation-name": {Severity: LintWarning},
+		}, "{ user { name } }")
+		assert.Equal(t, []string{"q.ts:1: Anonymous query; give it a name. (require-operation-name)"}, warnings)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("no-deprecated", func(t *testing.T) {
+		warnings, errs := lint(map[string]LintConfig{
+			"no-deprecated": {Severity: LintError},
+		}, "query Q {\n  viewer { name }\n}")
+		assert.Empty(t, warnings)
+		assert.Equal(t, []string{`q.ts:2: The field "Query.viewer" is deprecated. No longer supported (no-deprecated)`}, errs)
+	})
+
+	t.Run("max-depth", func(t *testing.T) {
+		query := `
+			query Q { user { friends { ...F } } }
+			fragment F on User { friends { name } }
+		`
+		warnings, errs := lint(map[string]LintConfig{
+			"max-depth": {Severity: LintError, Limit: 3},
+		}, query)
+		assert.Empty(t, warnings)
+		assert.Equal(t, []string{"q.ts:2: Operation has depth 4, exceeding the limit of 3. (max-depth)"}, errs)
+
+		warnings, errs = lint(map[string]LintConfig{
+			"max-depth": {Severity: LintError},
+		}, query)
+		assert.Empty(t, warnings)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("require-id", func(t *testing.T) {
+		warnings, errs := lint(map[string]LintConfig{
+			"require-id": {Severity: LintWarning},
+		}, `query Q {
+  user {
+    ...F
+    profile { bio }
+    friends { name }
+  }
+}
+fragment F on User { id }`)
+		assert.Equal(t, []string{`q.ts:5: Selections of "User" must include "id". (require-id)`}, warnings)
+		assert.Empty(t, errs)
+	})
+}
+
+func TestParseLint(t *testing.T) {
+	name, config, err := ParseLint("max-depth=error,8")
+	assert.NoError(t, err)
+	assert.Equal(t, "max-depth", name)
+	assert.Equal(t, LintConfig{Severity: LintError, Limit: 8}, config)
+
+	name, config, err = ParseLint("require-id")
+	assert.NoError(t, err)
+	assert.Equal(t, "require-id", name)
+	assert.Equal(t, LintConfig{Severity: LintWarning}, config)
+
+	_, config, err = ParseLint("no-deprecated=off")
+	assert.NoError(t, err)
+	assert.Equal(t, LintOff, config.Severity)
+
+	_, _, err = ParseLint("max-dept")
+	assert.EqualError(t, err, `unknown lint rule "max-dept", expected one of: max-depth, max-type-size, max-union-width, no-deprecated, require-id, require-operation-name`)
+	_, _, err = ParseLint("max-depth=fatal")
+	assert.Error(t, err)
+	_, _, err = ParseLint("max-depth=error,0")
+	assert.Error(t, err)
+}
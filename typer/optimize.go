@@ -0,0 +1,168 @@
+package typer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Optimize shortens the generated types of large applications, for emitters
+// that render them with RenderCompact. Aliases are named after what they
+// alias, as shapes are, so that they agree between runs.
+
+// Returns types with variables types identical to those of an earlier
+// operation declared as aliases of them, and unions of string literals that
+// are repeated declared once as aliases and referenced by name. Each is
+// replaced only where that shortens the output.
+func Optimize(types GeneratedTypes) GeneratedTypes {
+	res := types
+	res.Declarations = shareVariables(NewDeclarationSet(types.Declarations).Ordered())
+	res.QueryMap = append([]QueryType(nil), types.QueryMap...)
+	aliasLiteralUnions(&res)
+	return res
+}
+
+func shareVariables(decls []Declaration) []Declaration {
+	res := make([]Declaration, len(decls))
+	first := make(map[string]string)
+	for i, decl := range decls {
+		res[i] = decl
+		if decl.Kind != DeclarationVariables {
+			continue
+		}
+		rendered := RenderType(decl.Type)
+		name, ok := first[rendered]
+		if !ok {
+			first[rendered] = decl.Name
+			continue
+		}
+		if len(name) < len(rendered) {
+			res[i].Type = NamedType{Name: name}
+			res[i].Dependencies = []string{name}
+		}
+	}
+	return res
+}
+
+func literalsName(union UnionType) string {
+	sum := sha256.Sum256([]byte(RenderType(union)))
+	return "Literals_" + hex.EncodeToString(sum[:5])
+}
+
+func aliasLiteralUnions(types *GeneratedTypes) {
+	type candidate struct {
+		union  UnionType
+		source string
+		count  int
+	}
+	candidates := make(map[string]*candidate)
+	var order []string
+	count := func(source string) func(Type) (Type, bool) {
+		return func(typ Type) (Type, bool) {
+			union, ok := typ.(UnionType)
+			if !ok || len(union.Members) < 2 || !isLiteralUnion(union) {
+				return nil, false
+			}
+			name := literalsName(union)
+			if c := candidates[name]; c != nil {
+				c.count++
+			} else {
+				candidates[name] = &candidate{union: union, source: source, count: 1}
+				order = append(order, name)
+			}
+			return typ, true
+		}
+	}
+	for _, decl := range types.Declarations {
+		replaceTypes(decl.Type, count(decl.Source))
+	}
+	for _, entry := range types.QueryMap {
+		replaceTypes(entry.Data, count(entry.Location.File))
+		replaceTypes(entry.Variables, count(entry.Location.File))
+	}
+
+	aliases := make(map[string]bool)
+	for _, name := range order {
+		c := candidates[name]
+		rendered := RenderType(c.union)
+		declaration := len(fmt.Sprintf("export type %s = %s;\n", name, rendered))
+		if c.count*(len(rendered)-len(name)) <= declaration {
+			continue
+		}
+		aliases[name] = true
+		types.Declarations = append(types.Declarations, Declaration{
+			Name:   name,
+			Kind:   DeclarationAlias,
+			Source: c.source,
+			Type:   c.union,
+		})
+	}
+	if len(aliases) == 0 {
+		return
+	}
+	alias := func(references map[string]bool) func(Type) (Type, bool) {
+		return func(typ Type) (Type, bool) {
+			union, ok := typ.(UnionType)
+			if !ok || len(union.Members) < 2 || !isLiteralUnion(union) {
+				return nil, false
+			}
+			name := literalsName(union)
+			if !aliases[name] {
+				return typ, true
+			}
+			if references != nil {
+				references[name] = true
+			}
+			return NamedType{Name: name}, true
+		}
+	}
+	for i, decl := range types.Declarations {
+		if decl.Kind == DeclarationAlias {
+			continue
+		}
+		references := make(map[string]bool)
+		for _, dep := range decl.Dependencies {
+			references[dep] = true
+		}
+		types.Declarations[i] = newDeclaration(decl.Name, decl.Kind, decl.Source, replaceTypes(decl.Type, alias(references)), references)
+	}
+	for i := range types.QueryMap {
+		entry := &types.QueryMap[i]
+		entry.Data = replaceTypes(entry.Data, alias(nil))
+		entry.Variables = replaceTypes(entry.Variables, alias(nil))
+	}
+}
+
+// Returns typ with each type for which replace reports true replaced by the
+// type it returns, without visiting that type's own members.
+func replaceTypes(typ Type, replace func(Type) (Type, bool)) Type {
+	if res, ok := replace(typ); ok {
+		return res
+	}
+	switch typ := typ.(type) {
+	case ObjectType:
+		fields := make([]Field, len(typ.Fields))
+		for i, field := range typ.Fields {
+			fields[i] = Field{Name: field.Name, Type: replaceTypes(field.Type, replace)}
+		}
+		return ObjectType{Fields: fields}
+	case ArrayType:
+		return ArrayType{Elem: replaceTypes(typ.Elem, replace)}
+	case NullableType:
+		return NullableType{Type: replaceTypes(typ.Type, replace), Undefined: typ.Undefined}
+	case UnionType:
+		return UnionType{Members: replaceMembers(typ.Members, replace)}
+	case IntersectionType:
+		return IntersectionType{Members: replaceMembers(typ.Members, replace)}
+	default:
+		return typ
+	}
+}
+
+func replaceMembers(members []Type, replace func(Type) (Type, bool)) []Type {
+	res := make([]Type, len(members))
+	for i, member := range members {
+		res[i] = replaceTypes(member, replace)
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCompact(t *testing.T) {
+	user := NamedType{Name: "User"}
+	for _, test := range []struct {
+		typ      Type
+		expected string
+	}{
+		{NullableType{Type: user}, "User | null"},
+		{NullableType{Type: user, Undefined: true}, "User | null | undefined"},
+		{ArrayType{Elem: NullableType{Type: user}}, "(User | null)[]"},
+		{NullableType{Type: ArrayType{Elem: user}}, "User[] | null"},
+		{ObjectType{Fields: []Field{{Name: "user", Type: NullableType{Type: user}}}}, "{ user: User | null; }"},
+		{IntersectionType{Members: []Type{user, NullableType{Type: user}}}, "User & (User | null)"},
+		{UnionType{Members: []Type{IntersectionType{Members: []Type{user, user}}, user}}, "User & User | User"},
+		{NullableType{Type: RawType{Text: "() => void"}}, "(() => void) | null"},
+		{ArrayType{Elem: UnionType{Members: []Type{StringLiteralType{Value: "A"}}}}, `"A"[]`},
+	} {
+		assert.Equal(t, test.expected, RenderCompact(test.typ))
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	kind := UnionType{Members: []Type{
+		StringLiteralType{Value: "Administrator"},
+		StringLiteralType{Value: "Moderator"},
+		StringLiteralType{Value: "Member"},
+	}}
+	short := UnionType{Members: []Type{StringLiteralType{Value: "A"}, StringLiteralType{Value: "B"}}}
+	variables := ObjectType{Fields: []Field{
+		{Name: "id", Type: NamedType{Name: "string"}},
+		{Name: "first", Type: NamedType{Name: "number"}},
+	}}
+	types := GeneratedTypes{
+		Declarations: []Declaration{
+			{Name: "Query_A_Data", Kind: DeclarationData, Source: "a.ts", Type: ObjectType{Fields: []Field{
+				{Name: "kind", Type: kind},
+				{Name: "previous", Type: NullableType{Type: kind}},
+				{Name: "short", Type: short},
+			}}},
+			{Name: "Query_A_Variables", Kind: DeclarationVariables, Source: "a.ts", Type: variables},
+			{Name: "Query_B_Data", Kind: DeclarationData, Source: "b.ts", Type: ObjectType{Fields: []Field{
+				{Name: "kinds", Type: ArrayType{Elem: kind}},
+				{Name: "short", Type: short},
+			}}},
+			{Name: "Query_B_Variables", Kind: DeclarationVariables, Source: "b.ts", Type: variables},
+		},
+		QueryMap: []QueryType{
+			{Query: "query C { kind }", Data: ObjectType{Fields: []Field{{Name: "kind", Type: kind}}}, Variables: ObjectType{}},
+		},
+	}
+
+	optimized := Optimize(types)
+	alias := literalsName(kind)
+	decls := NewDeclarationSet(optimized.Declarations)
+	var rendered []string
+	for _, decl := range decls.Ordered() {
+		rendered = append(rendered, decl.Name+" = "+RenderCompact(decl.Type))
+	}
+	assert.Equal(t, []string{
+		alias + ` = "Administrator" | "Moderator" | "Member"`,
+		`Query_A_Data = { kind: ` + alias + `; previous: ` + alias + ` | null; short: "A" | "B"; }`,
+		`Query_A_Variables = { id: string; first: number; }`,
+		`Query_B_Data = { kinds: ` + alias + `[]; short: "A" | "B"; }`,
+		`Query_B_Variables = Query_A_Variables`,
+	}, rendered)
+	assert.Equal(t, []string{alias}, decls.Ordered()[1].Dependencies)
+	assert.Equal(t, []string{"Query_A_Variables"}, decls.Ordered()[4].Dependencies)
+	assert.Equal(t, `{ data: { kind: `+alias+`; }; variables: { }; }`, optimized.QueryMap[0].RenderWith(RenderCompact))
+
+	// The input is left as it was.
+	assert.Equal(t, kind, types.QueryMap[0].Data.(ObjectType).Fields[0].Type)
+	assert.Equal(t, variables, types.Declarations[3].Type)
+}
@@ -0,0 +1,168 @@
+package typer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Options controls how GraphQL types are translated to TypeScript.
+//
+// The zero value reproduces the default output of the extractgqlts command.
+// Options may be added in future releases, but always such that their zero
+// values preserve existing behavior, so setting fields by name is safe.
+// Existing options will not be removed or change meaning without a major
+// version bump.
+type Options struct {
+	// How nullable GraphQL types are represented. Defaults to NullabilityNull.
+	Nullability Nullability
+
+	// Whether object types include a __typename field. Defaults to
+	// TypenameAlways.
+	Typename TypenameMode
+
+	// Names the declarations generated for named operations and fragments.
+	// Defaults to DefaultNaming.
+	Naming func(kind, name, part string) string
+
+	// Maps GraphQL scalar names to TypeScript types, overriding the built-in
+	// mappings. Custom scalars mapped here are not imported from the scalars
+	// module.
+	Scalars map[string]string
+
+	// If set, called with the definition of each leaf type, such as a scalar,
+	// an enum, or the input object of a variable, before the default rules and
+	// Scalars. Returns the TypeScript type to translate it to, such as the
+	// name of a hand-written union, or false to apply the default rules.
+	ResolveLeafType func(def *ast.Definition) (typ string, ok bool)
+
+	// Declare each distinct nested object type once as a named shape. See
+	// shapes.go.
+	ShareShapes bool
+
+	// Warn about uses of deprecated fields and arguments.
+	WarnDeprecated bool
+
+	// Configures lint rules by name. See LintRules. Rules not configured are
+	// off. Enabling no-deprecated supersedes WarnDeprecated.
+	Lint map[string]LintConfig
+
+	// How directives that the schema does not declare, such as those only a
+	// client understands, are treated. Defaults to UnknownDirectivesError.
+	UnknownDirectives UnknownDirectivePolicy
+
+	// Rewrites of each document, applied in order before it is validated and
+	// typed, such as those of Transforms. See transforms.go.
+	Transforms []Transform
+}
+
+// Name of the scalar of file uploads, per the GraphQL multipart request
+// specification. Operations with variables of this scalar are marked as
+// Multipart.
+const UploadScalar = "Upload"
+
+// TypeScript type of uploads that the extractgqlts command maps the
+// UploadScalar to, unless overridden.
+const DefaultUploadType = "File | Blob"
+
+// Name of the directive that makes a query live, as with graphql-live-query.
+// Schemas must declare it, such as with `directive @live on QUERY`.
+const LiveDirective = "live"
+
+// Name of the client-only directive that excludes an operation from
+// generation, as `@extractgqlts(skip: true)`, such as an experimental query
+// behind a feature flag. Skipped operations are still validated. The
+// directive is removed before validation, so schemas need not declare it,
+// and from the documents as sent. See QueryType.Sent.
+const SkipDirective = "extractgqlts"
+
+type Nullability int
+
+const (
+	// Nullable types are represented as `T | null`.
+	NullabilityNull Nullability = iota
+	// Nullable types are represented as `T | null | undefined`.
+	NullabilityNullOrUndefined
+)
+
+type TypenameMode int
+
+const (
+	// Every object type has a __typename field, whether or not it was
+	// selected. Appropriate for clients that add __typename to every
+	// selection set.
+	TypenameAlways TypenameMode = iota
+	// Object types have a __typename field only where it was selected.
+	TypenameSelected
+)
+
+type UnknownDirectivePolicy int
+
+const (
+	// Documents using unknown directives are invalid, per the specification.
+	UnknownDirectivesError UnknownDirectivePolicy = iota
+	// Uses of unknown directives are reported as warnings.
+	UnknownDirectivesWarning
+	// Unknown directives are allowed silently.
+	UnknownDirectivesAllow
+)
+
+// Returns names of the form Query_GetUser_Data and Fragment_User_Variables.
+// The kind is one of "Query", "Mutation", "Subscription", or "Fragment"; the
+// part is either "Data" or "Variables".
+func DefaultNaming(kind, name, part string) string {
+	return fmt.Sprintf("%s_%s_%s", kind, name, part)
+}
+
+// Returns names of the form GetUserQuery and GetUserQueryVariables, matching
+// graphql-code-generator's typescript-operations plugin.
+func CodegenNaming(kind, name, part string) string {
+	if part == "Data" {
+		return name + kind
+	}
+	return name + kind + part
+}
+
+func (t *Typer) declarationName(kind, name, part string) string {
+	if t.Options.Naming != nil {
+		return t.Options.Naming(kind, name, part)
+	}
+	return DefaultNaming(kind, name, part)
+}
+
+// Returns a digest of the options that affect generated types and
+// diagnostics, so that caches of them may be invalidated when they change.
+// Naming and ResolveLeafType are funcs, so are left out; callers that set
+// them must key caches by them some other way. Transforms are identified by
+// name.
+func (opts Options) Digest() string {
+	transforms := make([]string, len(opts.Transforms))
+	for i, transform := range opts.Transforms {
+		transforms[i] = transform.Name
+	}
+	// Maps are marshaled with sorted keys, so the encoding is deterministic.
+	bs, _ := json.Marshal(struct {
+		Nullability       Nullability
+		Typename          TypenameMode
+		Scalars           map[string]string
+		ShareShapes       bool
+		WarnDeprecated    bool
+		Lint              map[string]LintConfig
+		UnknownDirectives UnknownDirectivePolicy
+		Transforms        []string
+	}{
+		Nullability:       opts.Nullability,
+		Typename:          opts.Typename,
+		Scalars:           opts.Scalars,
+		ShareShapes:       opts.ShareShapes,
+		WarnDeprecated:    opts.WarnDeprecated,
+		Lint:              opts.Lint,
+		UnknownDirectives: opts.UnknownDirectives,
+		Transforms:        transforms,
+	})
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:])
+}
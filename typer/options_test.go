@@ -0,0 +1,227 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestOptions(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				currentUser: User
+				now: Instant!
+			}
+
+			scalar Instant
+
+			type User {
+				name: String
+			}
+		`,
+	})
+	tests := []struct {
+		Options      Options
+		Input        string
+		ExpectedRoot string
+	}{
+		{
+			Options: Options{
+				Nullability: NullabilityNullOrUndefined,
+			},
+			Input:        `{ currentUser { name } }`,
+			ExpectedRoot: `{ data: { __typename: "Query"; currentUser: (({ __typename: "User"; name: (string | null | undefined); }) | null | undefined); }; variables: { }; }`,
+		},
+		{
+			Options: Options{
+				Typename: TypenameSelected,
+			},
+			Input:        `{ currentUser { __typename name } }`,
+			ExpectedRoot: `{ data: { currentUser: (({ __typename: "User"; name: (string | null); }) | null); }; variables: { }; }`,
+		},
+		{
+			Options: Options{
+				Naming: func(kind, name, part string) string {
+					return name + kind + part
+				},
+			},
+			Input:        `query Me { currentUser { name } }`,
+			ExpectedRoot: `{ data: MeQueryData; variables: MeQueryVariables; }`,
+		},
+		{
+			Options: Options{
+				Naming: CodegenNaming,
+			},
+			Input:        `query Me { currentUser { name } }`,
+			ExpectedRoot: `{ data: MeQuery; variables: MeQueryVariables; }`,
+		},
+		{
+			Options: Options{
+				Scalars: map[string]string{
+					"Instant": "Date",
+				},
+			},
+			Input:        `{ now }`,
+			ExpectedRoot: `{ data: { __typename: "Query"; now: Date; }; variables: { }; }`,
+		},
+	}
+	for _, test := range tests {
+		typer := &Typer{
+			Schema:  schema,
+			Options: test.Options,
+		}
+		actualRoot, _, err := typer.VisitString("", test.Input)
+		if assert.NoError(t, err, "input: %s", test.Input) {
+			assert.Equal(t, test.ExpectedRoot, actualRoot)
+		}
+	}
+}
+
+func TestMultipart(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				ok: Boolean
+			}
+
+			type Mutation {
+				setAvatar(file: Upload!): Boolean
+				attach(input: AttachmentInput!): Boolean
+				rename(input: RenameInput!): Boolean
+			}
+
+			scalar Upload
+
+			input AttachmentInput {
+				parent: AttachmentInput
+				files: [Upload!]
+			}
+
+			input RenameInput {
+				parent: RenameInput
+				name: String!
+			}
+		`,
+	})
+	tests := []struct {
+		Input        string
+		ExpectedRoot string
+	}{
+		{
+			Input:        `mutation ($file: Upload!) { setAvatar(file: $file) }`,
+			ExpectedRoot: `{ data: { __typename: "Mutation"; setAvatar: (boolean | null); }; variables: { file: (File | Blob); }; multipart: true; }`,
+		},
+		{
+			Input:        `mutation ($input: AttachmentInput!) { attach(input: $input) }`,
+			ExpectedRoot: `{ data: { __typename: "Mutation"; attach: (boolean | null); }; variables: { input: AttachmentInput; }; multipart: true; }`,
+		},
+		{
+			Input:        `mutation ($input: RenameInput!) { rename(input: $input) }`,
+			ExpectedRoot: `{ data: { __typename: "Mutation"; rename: (boolean | null); }; variables: { input: RenameInput; }; }`,
+		},
+	}
+	for _, test := range tests {
+		typer := &Typer{
+			Schema: schema,
+			Options: Options{
+				Scalars: map[string]string{UploadScalar: DefaultUploadType},
+			},
+		}
+		actualRoot, _, err := typer.VisitString("", test.Input)
+		if assert.NoError(t, err, "input: %s", test.Input) {
+			assert.Equal(t, test.ExpectedRoot, actualRoot)
+		}
+	}
+}
+
+func TestLive(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			directive @live on QUERY
+
+			type Query {
+				now: String!
+			}
+		`,
+	})
+	typer := &Typer{Schema: schema}
+	actualRoot, _, err := typer.VisitString("", `query @live { now }`)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{ data: { __typename: "Query"; now: string; }; variables: { }; live: true; }`, actualRoot)
+		assert.True(t, typer.QueryMap[0].Streamed())
+	}
+}
+
+func TestUnknownDirectives(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			directive @live on QUERY
+
+			type Query {
+				now: String!
+			}
+		`,
+	})
+	const input = `query Q($local: Boolean!) { now @client(always: $local) }`
+	for _, policy := range []UnknownDirectivePolicy{UnknownDirectivesError, UnknownDirectivesWarning, UnknownDirectivesAllow} {
+		typer := &Typer{Schema: schema, Options: Options{UnknownDirectives: policy}}
+		actualRoot, warnings, err := typer.VisitString("", input)
+		switch policy {
+		case UnknownDirectivesError:
+			assert.EqualError(t, err, `input:1: Unknown directive "@client".`)
+		case UnknownDirectivesWarning:
+			if assert.NoError(t, err) && assert.Len(t, warnings, 1) {
+				assert.EqualError(t, warnings[0], `input:1: Unknown directive "@client".`)
+			}
+		case UnknownDirectivesAllow:
+			assert.NoError(t, err)
+			assert.Empty(t, warnings)
+			assert.Equal(t, `{ data: Query_Q_Data; variables: Query_Q_Variables; }`, actualRoot)
+		}
+	}
+
+	// Directives in the wrong place remain errors.
+	typer := &Typer{Schema: schema, Options: Options{UnknownDirectives: UnknownDirectivesAllow}}
+	_, _, err := typer.VisitString("", `{ now @live }`)
+	assert.EqualError(t, err, `input:1: Directive "@live" may not be used on FIELD.`)
+}
+
+func TestResolveLeafType(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				role(as: Role): Role!
+				now: Instant!
+				name: String
+			}
+
+			enum Role { ADMIN USER }
+			scalar Instant
+		`,
+	})
+	typer := &Typer{
+		Schema: schema,
+		Options: Options{
+			Scalars: map[string]string{"Role": "string", "Instant": "Date"},
+			ResolveLeafType: func(def *ast.Definition) (string, bool) {
+				if def.Kind == ast.Enum {
+					return "AppRole", true
+				}
+				return "", false
+			},
+		},
+	}
+	actualRoot, _, err := typer.VisitString("", `query ($as: Role) { role(as: $as) now name }`)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{ data: { __typename: "Query"; name: (string | null); now: Date; role: AppRole; }; variables: { as: (AppRole | null); }; }`, actualRoot)
+	}
+	assert.Empty(t, typer.Scalars)
+}
@@ -0,0 +1,122 @@
+package typer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Reported for a custom scalar that operations use but Options.Scalars does
+// not map, so that its type is imported from the scalars module.
+type UnmappedScalarError struct {
+	Scalar string
+	// Where each operation using the scalar is, in order of appearance.
+	Locations []Location
+}
+
+func (e *UnmappedScalarError) Error() string {
+	return fmt.Sprintf("%s: custom scalar %q is not mapped to a TypeScript type, so it is imported from the scalars module%s", e.Locations[0], e.Scalar, alsoAt(e.Locations))
+}
+
+// Reported for a schema type that operations reach only through deprecated
+// fields, so that they will no longer use it once those fields are removed.
+type DeprecatedOnlyTypeError struct {
+	Type string
+	// Sorted coordinates of the outermost deprecated fields through which
+	// operations reach the type, such as Query.viewer.
+	Fields []string
+	// Where each operation reaching the type is, in order of appearance.
+	Locations []Location
+}
+
+func (e *DeprecatedOnlyTypeError) Error() string {
+	return fmt.Sprintf("%s: type %q is used only through the deprecated %s%s", e.Locations[0], e.Type, strings.Join(e.Fields, ", "), alsoAt(e.Locations))
+}
+
+// Returns a clause listing the locations after the first, if any.
+func alsoAt(locs []Location) string {
+	if len(locs) < 2 {
+		return ""
+	}
+	others := make([]string, len(locs)-1)
+	for i, loc := range locs[1:] {
+		others[i] = loc.String()
+	}
+	return "; also used at " + strings.Join(others, ", ")
+}
+
+// Returns an error for each custom scalar that operations in types, which
+// must have been generated against schema, use as the type of a field or
+// variable but that scalars does not map, in order of first use.
+func UnmappedScalars(schema *ast.Schema, types GeneratedTypes, scalars map[string]string) ([]*UnmappedScalarError, error) {
+	var errs []*UnmappedScalarError
+	byName := make(map[string]*UnmappedScalarError)
+	err := walkOperations(schema, types, func(entry QueryType, r *reporter) {
+		names := make(map[string]bool)
+		for name := range r.types {
+			names[name] = true
+		}
+		for name := range r.variableTypes {
+			names[name] = true
+		}
+		for _, name := range sortedKeys(names) {
+			def := schema.Types[name]
+			if def == nil || def.Kind != ast.Scalar || def.BuiltIn {
+				continue
+			}
+			if _, ok := scalars[name]; ok {
+				continue
+			}
+			if byName[name] == nil {
+				byName[name] = &UnmappedScalarError{Scalar: name}
+				errs = append(errs, byName[name])
+			}
+			byName[name].Locations = append(byName[name].Locations, entry.Location)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// Returns an error for each schema type that operations in types, which must
+// have been generated against schema, select from or return only through
+// deprecated fields, in order of first use.
+func DeprecatedOnlyTypes(schema *ast.Schema, types GeneratedTypes) ([]*DeprecatedOnlyTypeError, error) {
+	var order []string
+	reached := make(map[string]bool)
+	fields := make(map[string]map[string]bool)
+	locations := make(map[string][]Location)
+	err := walkOperations(schema, types, func(entry QueryType, r *reporter) {
+		for _, name := range sortedKeys(r.types) {
+			if r.reached[name] {
+				reached[name] = true
+			}
+			if fields[name] == nil {
+				fields[name] = make(map[string]bool)
+				order = append(order, name)
+			}
+			for coordinate := range r.reachedVia[name] {
+				fields[name][coordinate] = true
+			}
+			locations[name] = append(locations[name], entry.Location)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	var errs []*DeprecatedOnlyTypeError
+	for _, name := range order {
+		if reached[name] {
+			continue
+		}
+		errs = append(errs, &DeprecatedOnlyTypeError{
+			Type:      name,
+			Fields:    sortedKeys(fields[name]),
+			Locations: locations[name],
+		})
+	}
+	return errs, nil
+}
@@ -0,0 +1,74 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestOrphans(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(since: Instant): User
+				viewer: Viewer @deprecated
+				legacy: Legacy @deprecated
+			}
+
+			scalar Instant
+			scalar URL
+			scalar Color
+
+			type User {
+				name: String
+				avatar: URL
+			}
+
+			type Viewer {
+				user: User
+				theme: Color
+			}
+
+			type Legacy {
+				x: Int
+			}
+		`,
+	})
+	tp := &Typer{Schema: schema, Options: Options{Scalars: map[string]string{"Color": "string"}}}
+	inputs := []struct{ file, query string }{
+		{"a.ts", `query A($since: Instant) { user(since: $since) { name } }`},
+		{"b.ts", `query B { viewer { user { avatar } theme } }`},
+		{"c.ts", `query C { legacy { x } viewer { theme } }`},
+	}
+	for _, in := range inputs {
+		if _, _, err := tp.VisitString(in.file, in.query); !assert.NoError(t, err) {
+			return
+		}
+	}
+	a := Location{File: "a.ts", Line: 1, Column: 1}
+	b := Location{File: "b.ts", Line: 1, Column: 1}
+	c := Location{File: "c.ts", Line: 1, Column: 1}
+
+	scalars, err := UnmappedScalars(schema, tp.GeneratedTypes, tp.Options.Scalars)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*UnmappedScalarError{
+			{Scalar: "Instant", Locations: []Location{a}},
+			{Scalar: "URL", Locations: []Location{b}},
+		}, scalars)
+		assert.EqualError(t, scalars[0], `a.ts:1:1: custom scalar "Instant" is not mapped to a TypeScript type, so it is imported from the scalars module`)
+	}
+
+	orphans, err := DeprecatedOnlyTypes(schema, tp.GeneratedTypes)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*DeprecatedOnlyTypeError{
+			{Type: "Color", Fields: []string{"Query.viewer"}, Locations: []Location{b, c}},
+			{Type: "URL", Fields: []string{"Query.viewer"}, Locations: []Location{b}},
+			{Type: "Viewer", Fields: []string{"Query.viewer"}, Locations: []Location{b, c}},
+			{Type: "Legacy", Fields: []string{"Query.legacy"}, Locations: []Location{c}},
+		}, orphans)
+		assert.EqualError(t, orphans[2], `b.ts:1:1: type "Viewer" is used only through the deprecated Query.viewer; also used at c.ts:1:1`)
+	}
+}
@@ -0,0 +1,62 @@
+package typer
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// The fields an operation selects that code never reads, which servers
+// resolve and send for nothing.
+type OverFetchReport struct {
+	Operation OperationKind `json:"operation"`
+	// Empty for anonymous operations.
+	Name     string   `json:"name"`
+	Location Location `json:"location"`
+	// Paths of the unread fields by response key, such as user.avatar, in
+	// order of selection. Fields within unread fields are omitted.
+	Unread []string `json:"unread"`
+}
+
+// Returns a report of each operation in types, which must have been generated
+// against schema, that selects fields whose response keys code never names.
+// Whether code names a key is up to the caller, such as by scanning source
+// files with extract.Names; since any mention counts, fields are reported
+// only if unread, but not every unread field is reported.
+func OverFetching(schema *ast.Schema, types GeneratedTypes, named func(key string) bool) ([]OverFetchReport, error) {
+	reports := []OverFetchReport{}
+	err := walkOperations(schema, types, func(entry QueryType, r *reporter) {
+		var unread []string
+		for _, path := range r.pathOrder {
+			if within(path, unread) {
+				continue
+			}
+			if !named(path[strings.LastIndexByte(path, '.')+1:]) {
+				unread = append(unread, path)
+			}
+		}
+		if len(unread) == 0 {
+			return
+		}
+		reports = append(reports, OverFetchReport{
+			Operation: entry.Operation,
+			Name:      entry.Name,
+			Location:  entry.Location,
+			Unread:    unread,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Whether path is within any of the given paths.
+func within(path string, paths []string) bool {
+	for _, p := range paths {
+		if strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
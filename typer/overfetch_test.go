@@ -0,0 +1,56 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestOverFetching(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+				now: String!
+			}
+
+			type User {
+				id: ID!
+				name: String!
+				friends: [User!]!
+			}
+		`,
+	})
+	tp := &Typer{Schema: schema}
+	inputs := []struct{ file, query string }{
+		{"Name.tsx", `fragment UserName on User { name }`},
+		{"User.tsx", `query GetUser($id: ID!) { user(id: $id) { __typename id ...UserName pals: friends { id name } } }`},
+		{"Clock.tsx", `{ now }`},
+	}
+	for _, in := range inputs {
+		tp.PrepareString(in.file, in.query)
+	}
+	for _, in := range inputs {
+		if _, _, err := tp.VisitString(in.file, in.query); !assert.NoError(t, err) {
+			return
+		}
+	}
+	named := map[string]bool{"user": true, "id": true, "now": true, "friends": true}
+	reports, err := OverFetching(schema, tp.GeneratedTypes, func(key string) bool {
+		return named[key]
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []OverFetchReport{
+		{
+			Operation: OperationQuery,
+			Name:      "GetUser",
+			Location:  Location{File: "User.tsx", Line: 1, Column: 1},
+			Unread:    []string{"user.name", "user.pals"},
+		},
+	}, reports)
+}
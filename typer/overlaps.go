@@ -0,0 +1,131 @@
+package typer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Reported when every field that an operation selects is also selected by
+// another operation of the same kind and root fields, such as one of dozens
+// of slightly different queries of the current user, so that the two might
+// be consolidated, such as by sharing a fragment.
+type OverlappingOperationError struct {
+	// Such as "query GetUserName" or "anonymous query".
+	Definition string
+	Location   Location
+	// The operation that selects the same fields, or more.
+	Other         string
+	OtherLocation Location
+	// Whether Other selects exactly the same fields, rather than more.
+	Same bool
+}
+
+func (e *OverlappingOperationError) Error() string {
+	relation := "selects only fields that " + e.Other + " at " + e.OtherLocation.String() + " also selects"
+	if e.Same {
+		relation = "selects the same fields as " + e.Other + " at " + e.OtherLocation.String()
+	}
+	return fmt.Sprintf("%s: %s %s. Consider consolidating them, such as by sharing a fragment", e.Location, e.Definition, relation)
+}
+
+// Returns an error for each operation of types, which must have been
+// generated against schema, whose selected fields, by path and with
+// fragments inlined, are a subset of those of another operation of the same
+// kind and root fields, in order of appearance. Each is compared to the first
+// operation that covers it and that no other covers. Arguments and
+// directives are not compared, and repeated documents are left to
+// DuplicateDocuments.
+func OverlappingOperations(schema *ast.Schema, types GeneratedTypes) ([]*OverlappingOperationError, error) {
+	type operation struct {
+		entry QueryType
+		paths map[string]bool
+	}
+	var groups [][]*operation
+	byRoots := make(map[string]int)
+	seen := make(map[string]bool)
+	err := walkOperations(schema, types, func(entry QueryType, r *reporter) {
+		normalized := normalizeQuery(entry.Query)
+		if seen[normalized] || len(r.paths) == 0 {
+			return
+		}
+		seen[normalized] = true
+		var roots []string
+		for _, path := range sortedKeys(r.paths) {
+			if !strings.Contains(path, ".") {
+				roots = append(roots, path)
+			}
+		}
+		key := string(entry.Operation) + " " + strings.Join(roots, " ")
+		i, ok := byRoots[key]
+		if !ok {
+			i = len(groups)
+			byRoots[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], &operation{entry: entry, paths: r.paths})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Whether a covers b: it selects every field that b does, and more, or
+	// the same fields but appears first.
+	covers := func(a, b *operation, aFirst bool) bool {
+		if len(a.paths) < len(b.paths) || len(a.paths) == len(b.paths) && !aFirst {
+			return false
+		}
+		for path := range b.paths {
+			if !a.paths[path] {
+				return false
+			}
+		}
+		return true
+	}
+	var errs []*OverlappingOperationError
+	for _, group := range groups {
+		// Operations that no other covers.
+		var maximal []*operation
+		var maximalIndexes []int
+		for i, op := range group {
+			covered := false
+			for j, other := range group {
+				if i != j && covers(other, op, j < i) {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				maximal = append(maximal, op)
+				maximalIndexes = append(maximalIndexes, i)
+			}
+		}
+		for i, op := range group {
+			for k, other := range maximal {
+				j := maximalIndexes[k]
+				if i == j || !covers(other, op, j < i) {
+					continue
+				}
+				errs = append(errs, &OverlappingOperationError{
+					Definition:    definitionName(op.entry),
+					Location:      op.entry.Location,
+					Other:         definitionName(other.entry),
+					OtherLocation: other.entry.Location,
+					Same:          len(other.paths) == len(op.paths),
+				})
+				break
+			}
+		}
+	}
+	return errs, nil
+}
+
+// Returns a description of the entry's definition, such as "query GetUser",
+// "anonymous query", or "fragment UserFields".
+func definitionName(entry QueryType) string {
+	if entry.Name == "" {
+		return "anonymous " + string(entry.Operation)
+	}
+	return string(entry.Operation) + " " + entry.Name
+}
@@ -0,0 +1,63 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestOverlappingOperations(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				me: User
+				now: String
+			}
+
+			type User {
+				id: ID!
+				name: String
+				email: String
+			}
+		`,
+	})
+	queries := []string{
+		`fragment UserFields on User { id name email }`,
+		`query UserName { me { name } }`,
+		`query UserProfile { me { ...UserFields } }`,
+		`query UserEmail { me { email id } }`,
+		`query Profile { me { id name email } }`,
+		`query ProfileAgain { me { email name id } }`,
+		// Repeated documents are left to DuplicateDocuments.
+		`query Profile {me{id name email}}`,
+		`query Now { now }`,
+		`query Both { me { name } now }`,
+	}
+	typer := &Typer{Schema: schema}
+	for _, query := range queries {
+		typer.PrepareString("ops.ts", query)
+	}
+	for i, query := range queries {
+		if _, _, err := typer.VisitString("ops.ts", query); !assert.NoError(t, err) {
+			return
+		}
+		typer.QueryMap[i].Location.Line = i + 1
+	}
+	errs, err := OverlappingOperations(schema, typer.GeneratedTypes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		"ops.ts:2:1: query UserName selects only fields that query UserProfile at ops.ts:3:1 also selects. Consider consolidating them, such as by sharing a fragment",
+		"ops.ts:4:1: query UserEmail selects only fields that query UserProfile at ops.ts:3:1 also selects. Consider consolidating them, such as by sharing a fragment",
+		"ops.ts:5:1: query Profile selects the same fields as query UserProfile at ops.ts:3:1. Consider consolidating them, such as by sharing a fragment",
+		"ops.ts:6:1: query ProfileAgain selects the same fields as query UserProfile at ops.ts:3:1. Consider consolidating them, such as by sharing a fragment",
+	}, messages)
+}
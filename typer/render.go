@@ -0,0 +1,217 @@
+package typer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Renders typ as TypeScript source.
+func RenderType(typ Type) string {
+	var b strings.Builder
+	writeType(&b, typ)
+	return b.String()
+}
+
+func writeType(b *strings.Builder, typ Type) {
+	switch typ := typ.(type) {
+	case NamedType:
+		b.WriteString(typ.Name)
+	case StringLiteralType:
+		writeStringLiteral(b, typ.Value)
+	case RawType:
+		b.WriteString(typ.Text)
+	case ObjectType:
+		b.WriteString("{ ")
+		for _, field := range typ.Fields {
+			b.WriteString(field.Name)
+			b.WriteString(": ")
+			writeWrapped(b, field.Type)
+			b.WriteString("; ")
+		}
+		b.WriteString("}")
+	case ArrayType:
+		writeWrapped(b, typ.Elem)
+		b.WriteString("[]")
+	case NullableType:
+		b.WriteString("(")
+		writeWrapped(b, typ.Type)
+		b.WriteString(" | null")
+		if typ.Undefined {
+			b.WriteString(" | undefined")
+		}
+		b.WriteString(")")
+	case UnionType:
+		writeMembers(b, typ.Members, " | ")
+	case IntersectionType:
+		writeMembers(b, typ.Members, " & ")
+	case nil:
+		b.WriteString("unknown")
+	default:
+		panic(fmt.Errorf("unexpected type: %T", typ))
+	}
+}
+
+// Most literals are GraphQL names, which need no escaping.
+func writeStringLiteral(b *strings.Builder, s string) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '"' || c == '\\' || c < 0x20 || c >= 0x7f || c == '<' || c == '>' || c == '&' {
+			b.WriteString(StringToJSON(s))
+			return
+		}
+	}
+	b.WriteByte('"')
+	b.WriteString(s)
+	b.WriteByte('"')
+}
+
+func writeMembers(b *strings.Builder, members []Type, sep string) {
+	if len(members) == 0 {
+		b.WriteString("never")
+		return
+	}
+	for i, member := range members {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		writeType(b, member)
+	}
+}
+
+// Writes the type of a field, array element, or nullable type, which is
+// parenthesized if it would otherwise contain a space. Wrappers and unions of
+// string literals, such as the types of __typename, are left bare.
+func writeWrapped(b *strings.Builder, typ Type) {
+	switch typ := typ.(type) {
+	case ArrayType, NullableType:
+		writeType(b, typ)
+		return
+	case UnionType:
+		if isLiteralUnion(typ) {
+			writeType(b, typ)
+			return
+		}
+	}
+	if containsSpace(typ) {
+		b.WriteString("(")
+		writeType(b, typ)
+		b.WriteString(")")
+	} else {
+		writeType(b, typ)
+	}
+}
+
+func isLiteralUnion(typ UnionType) bool {
+	for _, member := range typ.Members {
+		if _, ok := member.(StringLiteralType); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether the rendering of typ contains a space, without rendering it.
+func containsSpace(typ Type) bool {
+	switch typ := typ.(type) {
+	case NamedType:
+		return strings.Contains(typ.Name, " ")
+	case StringLiteralType:
+		return strings.Contains(typ.Value, " ")
+	case RawType:
+		return strings.Contains(typ.Text, " ")
+	case ArrayType:
+		return containsSpace(typ.Elem)
+	case UnionType:
+		return len(typ.Members) > 1 || len(typ.Members) == 1 && containsSpace(typ.Members[0])
+	case IntersectionType:
+		return len(typ.Members) > 1 || len(typ.Members) == 1 && containsSpace(typ.Members[0])
+	default:
+		return true
+	}
+}
+
+// Renders typ as TypeScript source, as RenderType does, but parenthesizing
+// only where precedence requires it, such as about a union in an array or an
+// intersection. Used for Optimize.
+func RenderCompact(typ Type) string {
+	var b strings.Builder
+	writeCompact(&b, typ, precedenceUnion)
+	return b.String()
+}
+
+// Precedence of the position of a type, from loosest to tightest binding.
+const (
+	precedenceUnion = iota
+	precedenceIntersection
+	precedenceArray
+)
+
+func writeCompact(b *strings.Builder, typ Type, precedence int) {
+	switch typ := typ.(type) {
+	case ObjectType:
+		b.WriteString("{ ")
+		for _, field := range typ.Fields {
+			b.WriteString(field.Name)
+			b.WriteString(": ")
+			writeCompact(b, field.Type, precedenceUnion)
+			b.WriteString("; ")
+		}
+		b.WriteString("}")
+	case ArrayType:
+		writeCompact(b, typ.Elem, precedenceArray)
+		b.WriteString("[]")
+	case NullableType:
+		members := []Type{typ.Type, NamedType{Name: "null"}}
+		if typ.Undefined {
+			members = append(members, NamedType{Name: "undefined"})
+		}
+		writeCompact(b, UnionType{Members: members}, precedence)
+	case UnionType:
+		writeCompactMembers(b, typ.Members, " | ", precedence, precedenceUnion)
+	case IntersectionType:
+		writeCompactMembers(b, typ.Members, " & ", precedence, precedenceIntersection)
+	case RawType:
+		// Raw text may be of any precedence, such as that of a function type,
+		// so is left bare only where any type may be.
+		if precedence > precedenceUnion && containsSpace(typ) {
+			b.WriteString("(")
+			b.WriteString(typ.Text)
+			b.WriteString(")")
+		} else {
+			b.WriteString(typ.Text)
+		}
+	default:
+		writeType(b, typ)
+	}
+}
+
+// Writes the members of a union or intersection, whose own precedence is of,
+// in a position of the given precedence.
+func writeCompactMembers(b *strings.Builder, members []Type, sep string, precedence, of int) {
+	switch len(members) {
+	case 0:
+		b.WriteString("never")
+		return
+	case 1:
+		writeCompact(b, members[0], precedence)
+		return
+	}
+	wrap := precedence > of
+	if wrap {
+		b.WriteString("(")
+	}
+	for i, member := range members {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		memberPrecedence := of
+		if _, ok := member.(RawType); ok {
+			// Raw text, such as a function type, may bind more loosely than
+			// its neighbors.
+			memberPrecedence = precedenceArray
+		}
+		writeCompact(b, member, memberPrecedence)
+	}
+	if wrap {
+		b.WriteString(")")
+	}
+}
@@ -0,0 +1,254 @@
+package typer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Describes what an operation selects, for auditing what clients query.
+type OperationReport struct {
+	Operation OperationKind `json:"operation"`
+	// Empty for anonymous operations.
+	Name     string   `json:"name"`
+	Location Location `json:"location"`
+	// Greatest nesting of fields, where top-level fields have depth 1.
+	Depth int `json:"depth"`
+	// Number of fields selected, counting those of fragments at each spread.
+	FieldCount int `json:"fieldCount"`
+	// Sorted names of the schema types that are selected from or returned,
+	// excluding built-in scalars.
+	Types []string `json:"types"`
+	// Sorted coordinates of the deprecated fields and arguments used, such as
+	// User.name and User.avatar(size:).
+	Deprecated []string `json:"deprecated"`
+	// See QueryType.Tags.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Returns a report of each operation in types, which must have been generated
+// against schema. Fragments are found in the documents of the operations that
+// spread them, or else among the fragment documents of types.
+func Report(schema *ast.Schema, types GeneratedTypes) ([]OperationReport, error) {
+	var reports []OperationReport
+	err := walkOperations(schema, types, func(entry QueryType, r *reporter) {
+		reports = append(reports, OperationReport{
+			Operation:  entry.Operation,
+			Name:       entry.Name,
+			Location:   entry.Location,
+			Depth:      r.depth,
+			FieldCount: r.fieldCount,
+			Types:      sortedKeys(r.types),
+			Deprecated: sortedKeys(r.deprecated),
+			Tags:       entry.Tags,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Walks the selections of each operation in types, calling fn with the
+// resulting reporter.
+func walkOperations(schema *ast.Schema, types GeneratedTypes, fn func(entry QueryType, r *reporter)) error {
+	shared, err := parseSharedFragments(types)
+	if err != nil {
+		return err
+	}
+	for _, entry := range types.QueryMap {
+		if entry.Operation == OperationFragment {
+			continue
+		}
+		doc, err := parser.ParseQuery(&ast.Source{Input: entry.Query})
+		if err != nil {
+			return fmt.Errorf("parsing operation %s: %w", entry.Name, err)
+		}
+		if len(doc.Operations) != 1 {
+			continue
+		}
+		op := doc.Operations[0]
+		r := newReporter(schema, doc.Fragments, shared)
+		var root *ast.Definition
+		switch op.Operation {
+		case ast.Query:
+			root = schema.Query
+		case ast.Mutation:
+			root = schema.Mutation
+		case ast.Subscription:
+			root = schema.Subscription
+		}
+		r.walk(root, op.SelectionSet, 1, "")
+		for _, v := range op.VariableDefinitions {
+			r.variableTypes[v.Type.Name()] = true
+		}
+		fn(entry, r)
+	}
+	return nil
+}
+
+// Returns the fragment definitions of the fragment documents in types, by
+// name. Where names collide, the first definition wins.
+func parseSharedFragments(types GeneratedTypes) (map[string]*ast.FragmentDefinition, error) {
+	shared := make(map[string]*ast.FragmentDefinition)
+	for _, entry := range types.QueryMap {
+		if entry.Operation != OperationFragment {
+			continue
+		}
+		doc, err := parser.ParseQuery(&ast.Source{Input: entry.Query})
+		if err != nil {
+			return nil, fmt.Errorf("parsing fragment %s: %w", entry.Name, err)
+		}
+		for _, fragment := range doc.Fragments {
+			if _, ok := shared[fragment.Name]; !ok {
+				shared[fragment.Name] = fragment
+			}
+		}
+	}
+	return shared, nil
+}
+
+func newReporter(schema *ast.Schema, local ast.FragmentDefinitionList, shared map[string]*ast.FragmentDefinition) *reporter {
+	return &reporter{
+		schema:     schema,
+		local:      local,
+		shared:     shared,
+		spreading:  make(map[string]bool),
+		spread:     make(map[string]bool),
+		types:      make(map[string]bool),
+		fields:     make(map[string]bool),
+		deprecated: make(map[string]bool),
+		paths:      make(map[string]bool),
+		reached:    make(map[string]bool),
+		reachedVia: make(map[string]map[string]bool),
+
+		variableTypes: make(map[string]bool),
+	}
+}
+
+type reporter struct {
+	schema *ast.Schema
+	local  ast.FragmentDefinitionList
+	shared map[string]*ast.FragmentDefinition
+	// Names of the fragments being spread, to guard against cycles.
+	spreading map[string]bool
+	// Names of the fragments spread, directly or not.
+	spread     map[string]bool
+	depth      int
+	fieldCount int
+	types      map[string]bool
+	// Coordinates of the fields selected, such as User.name.
+	fields     map[string]bool
+	deprecated map[string]bool
+	// Paths of the fields selected, other than __typename, by response key.
+	paths     map[string]bool
+	pathOrder []string
+	// Names of the types in types reached other than through deprecated
+	// fields.
+	reached map[string]bool
+	// Coordinates of the outermost deprecated fields through which each type
+	// in types is reached, by type name.
+	reachedVia map[string]map[string]bool
+	// Coordinate of the outermost deprecated field being walked, if any.
+	deprecatedVia string
+	// Names of the types of the operation's variables, without modifiers.
+	variableTypes map[string]bool
+}
+
+func (r *reporter) addType(def *ast.Definition) {
+	if def == nil || def.BuiltIn {
+		return
+	}
+	r.types[def.Name] = true
+	if r.deprecatedVia == "" {
+		r.reached[def.Name] = true
+		return
+	}
+	if r.reachedVia[def.Name] == nil {
+		r.reachedVia[def.Name] = make(map[string]bool)
+	}
+	r.reachedVia[def.Name][r.deprecatedVia] = true
+}
+
+// Walks selections of the parent type at the given depth, and at the given
+// path of response keys, such as user.friends.
+func (r *reporter) walk(parent *ast.Definition, selections ast.SelectionSet, depth int, parentPath string) {
+	if parent == nil {
+		return
+	}
+	r.addType(parent)
+	for _, selection := range selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			r.fieldCount++
+			if depth > r.depth {
+				r.depth = depth
+			}
+			key := selection.Alias
+			if key == "" {
+				key = selection.Name
+			}
+			path := key
+			if parentPath != "" {
+				path = parentPath + "." + key
+			}
+			if key != "__typename" && !r.paths[path] {
+				r.paths[path] = true
+				r.pathOrder = append(r.pathOrder, path)
+			}
+			def := parent.Fields.ForName(selection.Name)
+			if def == nil {
+				continue
+			}
+			coordinate := parent.Name + "." + def.Name
+			r.fields[coordinate] = true
+			via := r.deprecatedVia
+			if _, ok := deprecationReason(def.Directives); ok {
+				r.deprecated[coordinate] = true
+				if via == "" {
+					r.deprecatedVia = coordinate
+				}
+			}
+			for _, arg := range selection.Arguments {
+				if argDef := def.Arguments.ForName(arg.Name); argDef != nil {
+					if _, ok := deprecationReason(argDef.Directives); ok {
+						r.deprecated[fmt.Sprintf("%s(%s:)", coordinate, arg.Name)] = true
+					}
+				}
+			}
+			result := r.schema.Types[def.Type.Name()]
+			r.addType(result)
+			r.walk(result, selection.SelectionSet, depth+1, path)
+			r.deprecatedVia = via
+		case *ast.InlineFragment:
+			typ := parent
+			if selection.TypeCondition != "" {
+				typ = r.schema.Types[selection.TypeCondition]
+			}
+			r.walk(typ, selection.SelectionSet, depth, parentPath)
+		case *ast.FragmentSpread:
+			fragment := r.local.ForName(selection.Name)
+			if fragment == nil {
+				fragment = r.shared[selection.Name]
+			}
+			if fragment == nil || r.spreading[fragment.Name] {
+				continue
+			}
+			r.spreading[fragment.Name] = true
+			r.spread[fragment.Name] = true
+			r.walk(r.schema.Types[fragment.TypeCondition], fragment.SelectionSet, depth, parentPath)
+			r.spreading[fragment.Name] = false
+		}
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
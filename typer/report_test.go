@@ -0,0 +1,70 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestReport(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+				now: String!
+			}
+
+			interface Node {
+				id: ID!
+			}
+
+			type User implements Node {
+				id: ID!
+				name: String! @deprecated(reason: "Use fullName.")
+				fullName: String!
+				avatar(size: Int @deprecated): String
+				friends: [User!]!
+			}
+		`,
+	})
+	tp := &Typer{Schema: schema}
+	queries := []string{
+		`fragment UserName on User { name fullName }`,
+		`query GetUser { user(id: 1) { ...UserName friends { ... on Node { id } avatar(size: 2) } } }`,
+		`{ now }`,
+	}
+	for _, query := range queries {
+		tp.PrepareString("ops.ts", query)
+	}
+	for _, query := range queries {
+		if _, _, err := tp.VisitString("ops.ts", query); !assert.NoError(t, err) {
+			return
+		}
+	}
+	reports, err := Report(schema, tp.GeneratedTypes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []OperationReport{
+		{
+			Operation:  OperationQuery,
+			Name:       "GetUser",
+			Location:   Location{File: "ops.ts", Line: 1, Column: 1},
+			Depth:      3,
+			FieldCount: 6,
+			Types:      []string{"Node", "Query", "User"},
+			Deprecated: []string{"User.avatar(size:)", "User.name"},
+		},
+		{
+			Operation:  OperationQuery,
+			Location:   Location{File: "ops.ts", Line: 1, Column: 1},
+			Depth:      1,
+			FieldCount: 1,
+			Types:      []string{"Query"},
+			Deprecated: []string{},
+		},
+	}, reports)
+}
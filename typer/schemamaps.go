@@ -0,0 +1,93 @@
+package typer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Lookups derived from a schema, so that generic code, such as that
+// normalizing a cache, can be written against generated types rather than
+// hand-maintained lists of types.
+type SchemaMaps struct {
+	// Custom scalars and enums referenced by Objects, as in GeneratedTypes.
+	Scalars []string
+	// The type of each object type's objects, by name, sorted by name. Each has
+	// its __typename and every field, whatever the arguments. Fields of object
+	// types refer to TypenameToType, and those of interfaces and unions to
+	// PossibleTypes.
+	Objects []Field
+	// The possible types of each interface and union, sorted by name.
+	PossibleTypes []PossibleTypes
+}
+
+type PossibleTypes struct {
+	Name string
+	// Names of the object types, sorted. Empty if none implement an interface.
+	Typenames []string
+}
+
+// Returns the SchemaMaps of schema. Leaf types are translated as in
+// operations, per opts.
+func NewSchemaMaps(schema *ast.Schema, opts Options) SchemaMaps {
+	t := &Typer{Schema: schema, Options: opts}
+	var maps SchemaMaps
+	for _, def := range schema.Types {
+		if def.BuiltIn || strings.HasPrefix(def.Name, "__") {
+			continue
+		}
+		switch def.Kind {
+		case ast.Object:
+			maps.Objects = append(maps.Objects, Field{Name: def.Name, Type: t.schemaObjectType(def)})
+		case ast.Interface, ast.Union:
+			possible := PossibleTypes{Name: def.Name, Typenames: []string{}}
+			for _, impl := range schema.GetPossibleTypes(def) {
+				possible.Typenames = append(possible.Typenames, impl.Name)
+			}
+			sort.Strings(possible.Typenames)
+			maps.PossibleTypes = append(maps.PossibleTypes, possible)
+		}
+	}
+	sort.Slice(maps.Objects, func(i, j int) bool {
+		return maps.Objects[i].Name < maps.Objects[j].Name
+	})
+	sort.Slice(maps.PossibleTypes, func(i, j int) bool {
+		return maps.PossibleTypes[i].Name < maps.PossibleTypes[j].Name
+	})
+	seen := make(map[string]bool)
+	for _, scalar := range t.Scalars {
+		if !seen[scalar] {
+			seen[scalar] = true
+			maps.Scalars = append(maps.Scalars, scalar)
+		}
+	}
+	sort.Strings(maps.Scalars)
+	return maps
+}
+
+func (t *Typer) schemaObjectType(def *ast.Definition) Type {
+	obj := ObjectType{
+		Fields: []Field{{Name: "__typename", Type: StringLiteralType{Value: def.Name}}},
+	}
+	for _, field := range def.Fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		var leaf Type
+		fieldType := t.getDefinition(leafTypeName(field.Type))
+		switch {
+		case fieldType == nil:
+			leaf = NamedType{Name: "unknown"}
+		case fieldType.Kind == ast.Object:
+			leaf = RawType{Text: "TypenameToType[" + StringToJSON(fieldType.Name) + "]"}
+		case fieldType.IsAbstractType():
+			leaf = RawType{Text: "TypenameToType[PossibleTypes[" + StringToJSON(fieldType.Name) + "]]"}
+		default:
+			obj.Fields = append(obj.Fields, Field{Name: field.Name, Type: t.visitType(field.Type)})
+			continue
+		}
+		obj.Fields = append(obj.Fields, Field{Name: field.Name, Type: t.wrapType(field.Type, leaf)})
+	}
+	return obj
+}
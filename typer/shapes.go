@@ -0,0 +1,28 @@
+package typer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// When Options.ShareShapes is enabled, each distinct nested object type is declared
+// once as a named type and referenced by name, rather than being repeated
+// inline everywhere it is selected. Names are derived from the shape itself,
+// so declarations from separate runs or separate inputs agree with each other
+// and can be deduplicated by name.
+
+func shapeName(shape Type) string {
+	sum := sha256.Sum256([]byte(RenderType(shape)))
+	return "Shape_" + hex.EncodeToString(sum[:5])
+}
+
+func (t *Typer) shareShape(shape Type, references map[string]bool) string {
+	name := shapeName(shape)
+	// Declared once per document, so that each document's contribution is
+	// self-contained.
+	if !t.documentShapes[name] {
+		t.documentShapes[name] = true
+		t.Declarations = append(t.Declarations, newDeclaration(name, DeclarationShape, t.filename, shape, references))
+	}
+	return name
+}
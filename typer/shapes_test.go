@@ -0,0 +1,47 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestShareShapes(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				currentUser: User
+				allUsers: [User!]!
+			}
+
+			type User {
+				name: String!
+			}
+		`,
+	})
+	typer := &Typer{
+		Schema: schema,
+		Options: Options{
+			ShareShapes: true,
+		},
+	}
+	a, _, err := typer.VisitString("", `{ currentUser { name } }`)
+	assert.NoError(t, err)
+	b, _, err := typer.VisitString("", `{ allUsers { name } }`)
+	assert.NoError(t, err)
+
+	shape := `{ __typename: "User"; name: string; }`
+	name := shapeName(RawType{Text: shape})
+	assert.Equal(t, `{ data: { __typename: "Query"; currentUser: (`+name+` | null); }; variables: { }; }`, a)
+	assert.Equal(t, `{ data: { __typename: "Query"; allUsers: `+name+`[]; }; variables: { }; }`, b)
+	decls := NewDeclarationSet(typer.Declarations)
+	assert.Len(t, typer.Declarations, 2)
+	assert.Equal(t, 1, decls.Len())
+	decl, ok := decls.Get(name)
+	if assert.True(t, ok) {
+		assert.Equal(t, `export type `+name+` = `+shape+`;`, decl.String())
+	}
+}
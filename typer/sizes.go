@@ -0,0 +1,167 @@
+package typer
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Generated types grow with what operations select, and selecting interfaces
+// and unions with many members can make them large enough to slow tsc to a
+// crawl. The max-type-size and max-union-width lint rules check the types as
+// they are generated, so their problems point at the selections responsible.
+
+// The size of a generated object type: that of the data type of an operation
+// or fragment, or of the selection set of a field within one.
+type typeMeasure struct {
+	// Nil for the data type of an operation or fragment.
+	field *ast.Field
+	// Of the field, or else of the operation or fragment.
+	pos *ast.Position
+	// Number of nodes of the type.
+	size int
+	// Members of the widest union of the type's alternatives or of the
+	// values of their __typename fields.
+	width int
+}
+
+// Whether a lint rule that checks generated types is enabled.
+func (t *Typer) measuringTypes() bool {
+	for _, rule := range LintRules {
+		if rule.checkTypes != nil && t.Options.Lint[rule.Name].Severity != LintOff {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Typer) measureType(field *ast.Field, pos *ast.Position, dataType Type) {
+	if t.measures == nil {
+		return
+	}
+	*t.measures = append(*t.measures, typeMeasure{
+		field: field,
+		pos:   pos,
+		size:  typeSize(dataType),
+		width: unionWidth(dataType),
+	})
+}
+
+// Returns the number of nodes of typ.
+func typeSize(typ Type) int {
+	switch typ := typ.(type) {
+	case ObjectType:
+		n := 1
+		for _, field := range typ.Fields {
+			n += typeSize(field.Type)
+		}
+		return n
+	case ArrayType:
+		return 1 + typeSize(typ.Elem)
+	case NullableType:
+		return 1 + typeSize(typ.Type)
+	case UnionType:
+		n := 1
+		for _, member := range typ.Members {
+			n += typeSize(member)
+		}
+		return n
+	case IntersectionType:
+		n := 1
+		for _, member := range typ.Members {
+			n += typeSize(member)
+		}
+		return n
+	default:
+		return 1
+	}
+}
+
+// Returns the number of members of the widest union of the alternatives of
+// typ or of the values of their __typename fields. The types of other fields
+// are measured with those fields.
+func unionWidth(typ Type) int {
+	width := 0
+	switch typ := typ.(type) {
+	case ObjectType:
+		for _, field := range typ.Fields {
+			if field.Name == "__typename" {
+				width = unionWidth(field.Type)
+			}
+		}
+	case UnionType:
+		width = len(typ.Members)
+		for _, member := range typ.Members {
+			if w := unionWidth(member); w > width {
+				width = w
+			}
+		}
+	case IntersectionType:
+		for _, member := range typ.Members {
+			if w := unionWidth(member); w > width {
+				width = w
+			}
+		}
+	}
+	return width
+}
+
+// Reports the data types of operations and fragments with more nodes than
+// limit, at the smallest selection within them that accounts for at least
+// half of their size, if any.
+func checkMaxTypeSize(measures []typeMeasure, limit int) []*gqlerror.Error {
+	var errs []*gqlerror.Error
+	start := 0
+	for i, m := range measures {
+		if m.field != nil {
+			continue
+		}
+		// The measures of the fields of a definition precede its own.
+		fields := measures[start:i]
+		start = i + 1
+		if m.size <= limit {
+			continue
+		}
+		var responsible *typeMeasure
+		for j := range fields {
+			f := &fields[j]
+			if 2*f.size >= m.size && (responsible == nil || f.size < responsible.size) {
+				responsible = f
+			}
+		}
+		if responsible == nil {
+			errs = append(errs, gqlerror.ErrorPosf(m.pos, "Generated data type has %d nodes, exceeding the limit of %d.", m.size, limit))
+			continue
+		}
+		errs = append(errs, gqlerror.ErrorPosf(responsible.pos,
+			`Generated data type has %d nodes, exceeding the limit of %d, of which the selection of "%s" has %d.`,
+			m.size, limit, responseKey(responsible.field), responsible.size))
+	}
+	return errs
+}
+
+// Reports the types with unions of more members than limit.
+func checkMaxUnionWidth(measures []typeMeasure, limit int) []*gqlerror.Error {
+	var errs []*gqlerror.Error
+	for _, m := range measures {
+		if m.width <= limit {
+			continue
+		}
+		subject := "Generated data type"
+		if m.field != nil {
+			subject = fmt.Sprintf(`Type of the selection of "%s"`, responseKey(m.field))
+		}
+		errs = append(errs, gqlerror.ErrorPosf(m.pos,
+			"%s has a union of %d members, exceeding the limit of %d. Select fields of an interface or union with many members through fragments on fewer of them.",
+			subject, m.width, limit))
+	}
+	return errs
+}
+
+func responseKey(field *ast.Field) string {
+	if field.Alias != "" {
+		return field.Alias
+	}
+	return field.Name
+}
@@ -0,0 +1,67 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestTypeSizeLint(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				node: Node
+				now: String!
+			}
+
+			interface Node {
+				id: ID!
+			}
+
+			type A implements Node { id: ID! a: String }
+			type B implements Node { id: ID! b: String }
+			type C implements Node { id: ID! c: String }
+		`,
+	})
+	visit := func(rules map[string]LintConfig, query string) (warnings []string, err error) {
+		typer := &Typer{
+			Schema:  schema,
+			Options: Options{Lint: rules},
+		}
+		_, ws, err := typer.VisitString("q.ts", query)
+		for _, warning := range ws {
+			warnings = append(warnings, warning.Error())
+		}
+		if err == nil {
+			assert.Len(t, typer.QueryMap, 1)
+		} else {
+			assert.Empty(t, typer.GeneratedTypes.Declarations)
+		}
+		return warnings, err
+	}
+	const query = "query Q {\n  now\n  node {\n    id\n    ... on A { a }\n    ... on B { b }\n  }\n}"
+
+	warnings, err := visit(map[string]LintConfig{
+		"max-union-width": {Severity: LintWarning, Limit: 2},
+	}, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`q.ts:3: Type of the selection of "node" has a union of 3 members, exceeding the limit of 2. Select fields of an interface or union with many members through fragments on fewer of them. (max-union-width)`,
+	}, warnings)
+
+	warnings, err = visit(map[string]LintConfig{
+		"max-union-width": {Severity: LintWarning},
+		"max-type-size":   {Severity: LintError, Limit: 10},
+	}, query)
+	assert.Empty(t, warnings)
+	assert.EqualError(t, err, `q.ts:3: Generated data type has 28 nodes, exceeding the limit of 10, of which the selection of "node" has 23. (max-type-size)`)
+
+	warnings, err = visit(map[string]LintConfig{
+		"max-type-size": {Severity: LintWarning, Limit: 3},
+	}, "query Q { now }")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`q.ts:1: Generated data type has 4 nodes, exceeding the limit of 3. (max-type-size)`}, warnings)
+}
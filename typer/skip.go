@@ -0,0 +1,33 @@
+package typer
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Removes the SkipDirective from the operations of doc. Reports whether any
+// was removed and whether any operation is skipped. The only argument is
+// skip, a boolean literal.
+func stripSkipDirective(doc *ast.QueryDocument) (stripped, skip bool, err *gqlerror.Error) {
+	for _, op := range doc.Operations {
+		directives := op.Directives[:0:0]
+		for _, directive := range op.Directives {
+			if directive.Name != SkipDirective {
+				directives = append(directives, directive)
+				continue
+			}
+			stripped = true
+			for _, arg := range directive.Arguments {
+				if arg.Name != "skip" {
+					return false, false, gqlerror.ErrorPosf(arg.Position, `Unknown argument "%s" on directive "@%s".`, arg.Name, SkipDirective)
+				}
+				if arg.Value.Kind != ast.BooleanValue {
+					return false, false, gqlerror.ErrorPosf(arg.Value.Position, `Argument "skip" of directive "@%s" must be true or false.`, SkipDirective)
+				}
+				skip = skip || arg.Value.Raw == "true"
+			}
+		}
+		op.Directives = directives
+	}
+	return stripped, skip, nil
+}
@@ -0,0 +1,38 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSkipDirective(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name:  "schema.gql",
+		Input: `type Query { now: String }`,
+	})
+	typer := &Typer{Schema: schema}
+
+	res, _, err := typer.VisitString("", `query Experiment @extractgqlts(skip: true) { now }`)
+	assert.NoError(t, err)
+	assert.Equal(t, `never /* skipped by @extractgqlts */`, res)
+	assert.Empty(t, typer.QueryMap)
+	assert.Empty(t, typer.Declarations)
+
+	// Skipped operations are still validated.
+	_, _, err = typer.VisitString("", `query Broken @extractgqlts(skip: true) { now(at: 1) }`)
+	assert.Error(t, err)
+
+	_, _, err = typer.VisitString("", `query Now @extractgqlts(skip: false) { now }`)
+	if assert.NoError(t, err) && assert.Len(t, typer.QueryMap, 1) {
+		assert.Equal(t, "Now", typer.QueryMap[0].Name)
+		assert.Equal(t, "query Now {\n\tnow\n}\n", typer.QueryMap[0].Sent)
+	}
+
+	_, _, err = typer.VisitString("", `query Now @extractgqlts(ignore: true) { now }`)
+	assert.EqualError(t, err, `input:1: Unknown argument "ignore" on directive "@extractgqlts".`)
+	_, _, err = typer.VisitString("", `query Now($skip: Boolean!) @extractgqlts(skip: $skip) { now }`)
+	assert.Error(t, err)
+}
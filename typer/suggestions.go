@@ -0,0 +1,69 @@
+package typer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// The validator suggests similar names for unknown fields, arguments, and
+// enum values, but not for every unknown type or directive. Those diagnostics
+// are completed here from the schema.
+
+var (
+	unknownTypeMessage      = regexp.MustCompile(`^Unknown type "([^"]+)"\.$`)
+	unknownDirectiveMessage = regexp.MustCompile(`^Unknown directive "@([^"]+)"\.$`)
+)
+
+// Appends suggestions to the diagnostics of unknown types and directives that
+// lack them.
+func (t *Typer) suggestNames(diags gqlerror.List) {
+	for _, diag := range diags {
+		switch diag.Rule {
+		case "KnownTypeNames":
+			if m := unknownTypeMessage.FindStringSubmatch(diag.Message); m != nil {
+				diag.Message += suggestion(m[1], t.typeNames(), "")
+			}
+		case "KnownDirectives":
+			if m := unknownDirectiveMessage.FindStringSubmatch(diag.Message); m != nil {
+				diag.Message += suggestion(m[1], t.directiveNames(), "@")
+			}
+		}
+	}
+}
+
+func (t *Typer) typeNames() []string {
+	names := make([]string, 0, len(t.Schema.Types))
+	for name := range t.Schema.Types {
+		if !strings.HasPrefix(name, "__") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t *Typer) directiveNames() []string {
+	names := make([]string, 0, len(t.Schema.Directives))
+	for name := range t.Schema.Directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns a sentence suggesting the options similar to name, each quoted with
+// prefix, or else the empty string.
+func suggestion(name string, options []string, prefix string) string {
+	suggestions := validator.SuggestionList(name, options)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	for i, s := range suggestions {
+		suggestions[i] = `"` + prefix + s + `"`
+	}
+	return " Did you mean " + validator.OrList(suggestions...) + "?"
+}
@@ -0,0 +1,54 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSuggestions(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+			}
+
+			type User {
+				name: String!
+			}
+		`,
+	})
+	tests := []struct {
+		Input    string
+		Expected string
+	}{
+		{
+			Input:    `query ($id: Id!) { user(id: 1) { name } }`,
+			Expected: `ops.ts:1: Unknown type "Id". Did you mean "ID"?`,
+		},
+		{
+			Input:    `{ user(id: 1) { ... on Usr { name } } }`,
+			Expected: `ops.ts:1: Unknown type "Usr". Did you mean "User"?`,
+		},
+		{
+			Input:    `{ user(id: 1) { name @skp(if: true) } }`,
+			Expected: `ops.ts:1: Unknown directive "@skp". Did you mean "@skip"?`,
+		},
+		{
+			Input:    `{ user(id: 1) { name @nothingLikeIt } }`,
+			Expected: `ops.ts:1: Unknown directive "@nothingLikeIt".`,
+		},
+		{
+			Input:    `{ user(ids: 1) { name } }`,
+			Expected: "ops.ts:1: Unknown argument \"ids\" on field \"Query.user\". Did you mean \"id\"?\nops.ts:1: Field \"user\" argument \"id\" of type \"ID!\" is required, but it was not provided.",
+		},
+	}
+	for _, test := range tests {
+		typer := &Typer{Schema: schema}
+		_, _, err := typer.VisitString("ops.ts", test.Input)
+		assert.EqualError(t, err, test.Expected, "input: %s", test.Input)
+	}
+}
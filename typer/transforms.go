@@ -0,0 +1,224 @@
+package typer
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// Transforms rewrite each document after it is parsed and before it is
+// validated and typed, as a client's own links rewrite documents before
+// sending them, so that the generated types match what is actually sent.
+// Options.Transforms lists those applied, in order. The keys of QueryTypes
+// remain the documents as written.
+
+type Transform struct {
+	Name        string
+	Description string
+	// Rewrites doc in place. Field definitions are not yet resolved.
+	Apply func(schema *ast.Schema, doc *ast.QueryDocument)
+}
+
+// The built-in transforms, in order of name.
+var Transforms = []Transform{
+	{
+		Name:        "add-id",
+		Description: "select id in each selection set of a type with an id field, as normalized caches do",
+		Apply: func(schema *ast.Schema, doc *ast.QueryDocument) {
+			walkSelectionSets(schema, doc, func(set *ast.SelectionSet, typ *ast.Definition, pos *ast.Position) {
+				if id := typ.Fields.ForName("id"); id != nil && !hasRequiredArguments(id) {
+					addField(set, "id", pos)
+				}
+			})
+		},
+	},
+	{
+		Name:        "add-typename",
+		Description: "select __typename in each selection set but those of operations, as Apollo Client and urql do",
+		Apply: func(schema *ast.Schema, doc *ast.QueryDocument) {
+			AddTypename(doc)
+		},
+	},
+	{
+		Name:        "strip-client",
+		Description: "remove selections with the @client directive, which are resolved locally rather than sent",
+		Apply: func(schema *ast.Schema, doc *ast.QueryDocument) {
+			for _, op := range doc.Operations {
+				op.SelectionSet = stripClient(op.SelectionSet)
+			}
+			for _, fragment := range doc.Fragments {
+				fragment.SelectionSet = stripClient(fragment.SelectionSet)
+			}
+		},
+	},
+}
+
+// Returns the built-in transform of the given name.
+func TransformByName(name string) (Transform, bool) {
+	for _, transform := range Transforms {
+		if transform.Name == name {
+			return transform, true
+		}
+	}
+	return Transform{}, false
+}
+
+// Returns the names of the built-in transforms.
+func TransformNames() []string {
+	names := make([]string, len(Transforms))
+	for i, transform := range Transforms {
+		names[i] = transform.Name
+	}
+	return names
+}
+
+// Applies Options.Transforms to doc. Returns its formatted source text, or
+// the empty string if there are no transforms.
+func (t *Typer) transform(doc *ast.QueryDocument) string {
+	if len(t.Options.Transforms) == 0 {
+		return ""
+	}
+	for _, transform := range t.Options.Transforms {
+		transform.Apply(t.Schema, doc)
+	}
+	return formatDocument(doc)
+}
+
+func formatDocument(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String()
+}
+
+// Selects __typename at the end of each selection set of doc but those of
+// operations, as Apollo Client and urql do before sending a document, unless
+// the set already selects it or another introspection field.
+func AddTypename(doc *ast.QueryDocument) {
+	var walk func(set *ast.SelectionSet, pos *ast.Position, root bool)
+	walk = func(set *ast.SelectionSet, pos *ast.Position, root bool) {
+		introspects := false
+		for _, selection := range *set {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				introspects = introspects || strings.HasPrefix(selection.Name, "__")
+				if len(selection.SelectionSet) > 0 {
+					walk(&selection.SelectionSet, selection.Position, false)
+				}
+			case *ast.InlineFragment:
+				walk(&selection.SelectionSet, selection.Position, false)
+			}
+		}
+		if !root && !introspects {
+			*set = append(*set, &ast.Field{Alias: "__typename", Name: "__typename", Position: pos})
+		}
+	}
+	for _, op := range doc.Operations {
+		walk(&op.SelectionSet, op.Position, true)
+	}
+	for _, fragment := range doc.Fragments {
+		walk(&fragment.SelectionSet, fragment.Position, false)
+	}
+}
+
+// Calls fn with each selection set of doc but those of operations, along
+// with the type selected from and the position of the selection it belongs
+// to. Selection sets of unknown types are skipped.
+func walkSelectionSets(schema *ast.Schema, doc *ast.QueryDocument, fn func(set *ast.SelectionSet, typ *ast.Definition, pos *ast.Position)) {
+	var walk func(set ast.SelectionSet, typ *ast.Definition)
+	walk = func(set ast.SelectionSet, typ *ast.Definition) {
+		for _, selection := range set {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				if len(selection.SelectionSet) == 0 {
+					continue
+				}
+				var def *ast.FieldDefinition
+				if typ != nil {
+					def = typ.Fields.ForName(selection.Name)
+				}
+				if def == nil {
+					continue
+				}
+				fieldType := schema.Types[def.Type.Name()]
+				if fieldType == nil {
+					continue
+				}
+				walk(selection.SelectionSet, fieldType)
+				fn(&selection.SelectionSet, fieldType, selection.Position)
+			case *ast.InlineFragment:
+				inner := typ
+				if selection.TypeCondition != "" {
+					inner = schema.Types[selection.TypeCondition]
+				}
+				walk(selection.SelectionSet, inner)
+			}
+		}
+	}
+	for _, op := range doc.Operations {
+		walk(op.SelectionSet, rootType(schema, op.Operation))
+	}
+	for _, fragment := range doc.Fragments {
+		typ := schema.Types[fragment.TypeCondition]
+		walk(fragment.SelectionSet, typ)
+		if typ != nil {
+			fn(&fragment.SelectionSet, typ, fragment.Position)
+		}
+	}
+}
+
+func rootType(schema *ast.Schema, operation ast.Operation) *ast.Definition {
+	switch operation {
+	case ast.Mutation:
+		return schema.Mutation
+	case ast.Subscription:
+		return schema.Subscription
+	default:
+		return schema.Query
+	}
+}
+
+func hasRequiredArguments(def *ast.FieldDefinition) bool {
+	for _, arg := range def.Arguments {
+		if arg.Type.NonNull && arg.DefaultValue == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Appends a selection of the field to set, unless it is already selected,
+// unaliased, among the fields of set itself.
+func addField(set *ast.SelectionSet, name string, pos *ast.Position) {
+	for _, selection := range *set {
+		if field, ok := selection.(*ast.Field); ok && field.Name == name && (field.Alias == "" || field.Alias == name) {
+			return
+		}
+	}
+	*set = append(*set, &ast.Field{Alias: name, Name: name, Position: pos})
+}
+
+func stripClient(set ast.SelectionSet) ast.SelectionSet {
+	res := set[:0:0]
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Directives.ForName("client") != nil {
+				continue
+			}
+			selection.SelectionSet = stripClient(selection.SelectionSet)
+		case *ast.InlineFragment:
+			if selection.Directives.ForName("client") != nil {
+				continue
+			}
+			selection.SelectionSet = stripClient(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if selection.Directives.ForName("client") != nil {
+				continue
+			}
+		}
+		res = append(res, selection)
+	}
+	return res
+}
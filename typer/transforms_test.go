@@ -0,0 +1,82 @@
+package typer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestTransforms(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				me: User
+			}
+
+			type User {
+				id: ID!
+				name: String
+				friends: [User!]!
+			}
+		`,
+	})
+	transforms := func(names ...string) []Transform {
+		var res []Transform
+		for _, name := range names {
+			transform, ok := TransformByName(name)
+			assert.True(t, ok, name)
+			res = append(res, transform)
+		}
+		return res
+	}
+	query := `query Me { me { name isLoggedIn @client friends { name } } }`
+
+	typer := &Typer{Schema: schema, Options: Options{Typename: TypenameSelected}}
+	_, _, err := typer.VisitString("", query)
+	assert.Error(t, err)
+
+	typer = &Typer{Schema: schema, Options: Options{
+		Typename:   TypenameSelected,
+		Transforms: transforms("strip-client", "add-id", "add-typename"),
+	}}
+	res, _, err := typer.VisitString("", query)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{ data: Query_Me_Data; variables: Query_Me_Variables; }`, res)
+		assert.Equal(t, query, typer.QueryMap[0].Query)
+		assert.Equal(t, "query Me {\n\tme {\n\t\tname\n\t\tfriends {\n\t\t\tname\n\t\t\tid\n\t\t\t__typename\n\t\t}\n\t\tid\n\t\t__typename\n\t}\n}\n", typer.QueryMap[0].Sent)
+		decl, _ := NewDeclarationSet(typer.Declarations).Get("Query_Me_Data")
+		assert.Equal(t, `{ me: (({ __typename: "User"; friends: ({ __typename: "User"; id: string; name: (string | null); })[]; id: string; name: (string | null); }) | null); }`, RenderType(decl.Type))
+	}
+
+	// Shared fragments are transformed too.
+	typer = &Typer{Schema: schema, Options: Options{
+		Typename:   TypenameSelected,
+		Transforms: transforms("add-typename"),
+	}}
+	typer.PrepareString("", `fragment Friend on User { name }`)
+	_, _, err = typer.VisitString("", `fragment Friend on User { name }`)
+	if assert.NoError(t, err) {
+		decl, _ := NewDeclarationSet(typer.Declarations).Get("Fragment_Friend_Data")
+		assert.Equal(t, `{ __typename: "User"; name: (string | null); }`, RenderType(decl.Type))
+	}
+}
+
+func TestAddTypename(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query { me { name ... on User { id } } __schema { types { name } } }
+		fragment F on User { friends { __typename name } }
+	`})
+	if !assert.Nil(t, err) {
+		return
+	}
+	AddTypename(doc)
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	assert.Equal(t, "query {\n\tme {\n\t\tname\n\t\t... on User {\n\t\t\tid\n\t\t\t__typename\n\t\t}\n\t\t__typename\n\t}\n\t__schema {\n\t\ttypes {\n\t\t\tname\n\t\t\t__typename\n\t\t}\n\t\t__typename\n\t}\n}\nfragment F on User {\n\tfriends {\n\t\t__typename\n\t\tname\n\t}\n\t__typename\n}\n", buf.String())
+}
@@ -0,0 +1,71 @@
+package typer
+
+// Generated types are represented as a small TypeScript type AST, so that
+// emitters can work with structure rather than text. RenderType produces
+// TypeScript source.
+
+// A TypeScript type expression. One of NamedType, StringLiteralType,
+// ObjectType, ArrayType, NullableType, UnionType, IntersectionType, or
+// RawType.
+type Type interface {
+	isType()
+}
+
+// A reference to a type by name: a keyword such as string or unknown, a
+// custom scalar or enum imported from the scalars module, or a generated
+// declaration.
+type NamedType struct {
+	Name string
+	// The built-in GraphQL scalar that the type translates, such as Int for
+	// number, so that emitters for other languages can tell Int from Float.
+	// Empty for other types.
+	Scalar string
+}
+
+// A string literal type, such as the possible values of __typename.
+type StringLiteralType struct {
+	Value string
+}
+
+type ObjectType struct {
+	Fields []Field
+}
+
+type Field struct {
+	Name string
+	Type Type
+}
+
+type ArrayType struct {
+	Elem Type
+}
+
+// A type that also admits null, and also undefined if Undefined is set, per
+// Options.Nullability.
+type NullableType struct {
+	Type      Type
+	Undefined bool
+}
+
+type UnionType struct {
+	Members []Type
+}
+
+type IntersectionType struct {
+	Members []Type
+}
+
+// TypeScript source text that is not otherwise interpreted, such as a type
+// given by Options.Scalars.
+type RawType struct {
+	Text string
+}
+
+func (NamedType) isType()         {}
+func (StringLiteralType) isType() {}
+func (ObjectType) isType()        {}
+func (ArrayType) isType()         {}
+func (NullableType) isType()      {}
+func (UnionType) isType()         {}
+func (IntersectionType) isType()  {}
+func (RawType) isType()           {}
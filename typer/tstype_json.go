@@ -0,0 +1,216 @@
+package typer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Types are encoded as JSON objects tagged with their kind, so that the
+// structure survives the cache, the json target, and plugins.
+
+type typeJSON struct {
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name,omitempty"`      // named
+	Scalar    string      `json:"scalar,omitempty"`    // named
+	Value     string      `json:"value,omitempty"`     // stringLiteral
+	Text      string      `json:"text,omitempty"`      // raw
+	Fields    []fieldJSON `json:"fields,omitempty"`    // object
+	Elem      *typeJSON   `json:"elem,omitempty"`      // array
+	Type      *typeJSON   `json:"type,omitempty"`      // nullable
+	Undefined bool        `json:"undefined,omitempty"` // nullable
+	Members   []*typeJSON `json:"members,omitempty"`   // union, intersection
+}
+
+type fieldJSON struct {
+	Name string    `json:"name"`
+	Type *typeJSON `json:"type"`
+}
+
+func toTypeJSON(typ Type) *typeJSON {
+	switch typ := typ.(type) {
+	case nil:
+		return nil
+	case NamedType:
+		return &typeJSON{Kind: "named", Name: typ.Name, Scalar: typ.Scalar}
+	case StringLiteralType:
+		return &typeJSON{Kind: "stringLiteral", Value: typ.Value}
+	case RawType:
+		return &typeJSON{Kind: "raw", Text: typ.Text}
+	case ObjectType:
+		fields := make([]fieldJSON, len(typ.Fields))
+		for i, field := range typ.Fields {
+			fields[i] = fieldJSON{Name: field.Name, Type: toTypeJSON(field.Type)}
+		}
+		return &typeJSON{Kind: "object", Fields: fields}
+	case ArrayType:
+		return &typeJSON{Kind: "array", Elem: toTypeJSON(typ.Elem)}
+	case NullableType:
+		return &typeJSON{Kind: "nullable", Type: toTypeJSON(typ.Type), Undefined: typ.Undefined}
+	case UnionType:
+		return &typeJSON{Kind: "union", Members: toTypesJSON(typ.Members)}
+	case IntersectionType:
+		return &typeJSON{Kind: "intersection", Members: toTypesJSON(typ.Members)}
+	default:
+		panic(fmt.Errorf("unexpected type: %T", typ))
+	}
+}
+
+func toTypesJSON(types []Type) []*typeJSON {
+	res := make([]*typeJSON, len(types))
+	for i, typ := range types {
+		res[i] = toTypeJSON(typ)
+	}
+	return res
+}
+
+func (j *typeJSON) toType() (Type, error) {
+	if j == nil {
+		return nil, nil
+	}
+	switch j.Kind {
+	case "named":
+		return NamedType{Name: j.Name, Scalar: j.Scalar}, nil
+	case "stringLiteral":
+		return StringLiteralType{Value: j.Value}, nil
+	case "raw":
+		return RawType{Text: j.Text}, nil
+	case "object":
+		var obj ObjectType
+		if len(j.Fields) > 0 {
+			obj.Fields = make([]Field, len(j.Fields))
+		}
+		for i, field := range j.Fields {
+			typ, err := field.Type.toType()
+			if err != nil {
+				return nil, err
+			}
+			obj.Fields[i] = Field{Name: field.Name, Type: typ}
+		}
+		return obj, nil
+	case "array":
+		elem, err := j.Elem.toType()
+		return ArrayType{Elem: elem}, err
+	case "nullable":
+		typ, err := j.Type.toType()
+		return NullableType{Type: typ, Undefined: j.Undefined}, err
+	case "union":
+		members, err := fromTypesJSON(j.Members)
+		return UnionType{Members: members}, err
+	case "intersection":
+		members, err := fromTypesJSON(j.Members)
+		return IntersectionType{Members: members}, err
+	default:
+		return nil, fmt.Errorf("unknown kind of type: %q", j.Kind)
+	}
+}
+
+func fromTypesJSON(types []*typeJSON) ([]Type, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+	res := make([]Type, len(types))
+	for i, j := range types {
+		typ, err := j.toType()
+		if err != nil {
+			return nil, err
+		}
+		res[i] = typ
+	}
+	return res, nil
+}
+
+type queryTypeJSON struct {
+	Query     string            `json:"query"`
+	Operation OperationKind     `json:"operation"`
+	Name      string            `json:"name,omitempty"`
+	Location  Location          `json:"location"`
+	Data      *typeJSON         `json:"data"`
+	Variables *typeJSON         `json:"variables"`
+	Multipart bool              `json:"multipart,omitempty"`
+	Live      bool              `json:"live,omitempty"`
+	Sent      string            `json:"sent,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Defaults  *VariableDefaults `json:"defaults,omitempty"`
+}
+
+func (q QueryType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(queryTypeJSON{
+		Query:     q.Query,
+		Operation: q.Operation,
+		Name:      q.Name,
+		Location:  q.Location,
+		Data:      toTypeJSON(q.Data),
+		Variables: toTypeJSON(q.Variables),
+		Multipart: q.Multipart,
+		Live:      q.Live,
+		Sent:      q.Sent,
+		Tags:      q.Tags,
+		Defaults:  q.Defaults,
+	})
+}
+
+func (q *QueryType) UnmarshalJSON(bs []byte) error {
+	var j queryTypeJSON
+	if err := json.Unmarshal(bs, &j); err != nil {
+		return err
+	}
+	data, err := j.Data.toType()
+	if err != nil {
+		return err
+	}
+	variables, err := j.Variables.toType()
+	if err != nil {
+		return err
+	}
+	*q = QueryType{
+		Query:     j.Query,
+		Operation: j.Operation,
+		Name:      j.Name,
+		Location:  j.Location,
+		Data:      data,
+		Variables: variables,
+		Multipart: j.Multipart,
+		Live:      j.Live,
+		Sent:      j.Sent,
+		Tags:      j.Tags,
+		Defaults:  j.Defaults,
+	}
+	return nil
+}
+
+type declarationJSON struct {
+	Name         string    `json:"name"`
+	Kind         string    `json:"kind"`
+	Source       string    `json:"source,omitempty"`
+	Dependencies []string  `json:"dependencies,omitempty"`
+	Type         *typeJSON `json:"type"`
+}
+
+func (d Declaration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(declarationJSON{
+		Name:         d.Name,
+		Kind:         d.Kind,
+		Source:       d.Source,
+		Dependencies: d.Dependencies,
+		Type:         toTypeJSON(d.Type),
+	})
+}
+
+func (d *Declaration) UnmarshalJSON(bs []byte) error {
+	var j declarationJSON
+	if err := json.Unmarshal(bs, &j); err != nil {
+		return err
+	}
+	typ, err := j.Type.toType()
+	if err != nil {
+		return err
+	}
+	*d = Declaration{
+		Name:         j.Name,
+		Kind:         j.Kind,
+		Source:       j.Source,
+		Dependencies: j.Dependencies,
+		Type:         typ,
+	}
+	return nil
+}
@@ -0,0 +1,844 @@
+package typer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Translates GraphQL documents to TypeScript types, accumulating the results
+// in GeneratedTypes. Schema is required; Options may be left zero.
+type Typer struct {
+	Schema  *ast.Schema
+	Options Options
+
+	GeneratedTypes
+
+	*alternativesBuilder
+	variables map[string]Type
+	multipart bool // Whether the current definition's variables include uploads.
+
+	fragments map[string]*ast.FragmentDefinition // Shared fragments by name.
+	prepared  map[string]*preparedDocument       // Keyed by preparedKey.
+
+	filename       string          // Of the current document.
+	documentShapes map[string]bool // Shape names declared by the current document.
+	sent           string          // Of the current document. See QueryType.Sent.
+	skip           bool            // Whether the current document is skipped. See SkipDirective.
+
+	unions *unionInterner
+
+	// Sizes of the types generated for the current document, if a lint rule
+	// checks them.
+	measures *[]typeMeasure
+}
+
+// Returns a Typer for use on another goroutine. The fork shares the
+// receiver's schema, options, and prepared documents, none of which may be
+// modified while forks are in use, but accumulates its own GeneratedTypes.
+// Each prepared document must be visited by at most one Typer.
+func (t *Typer) Fork() *Typer {
+	return &Typer{
+		Schema:    t.Schema,
+		Options:   t.Options,
+		fragments: t.fragments,
+		prepared:  t.prepared,
+	}
+}
+
+type typeUnion struct {
+	canonical   string
+	definitions []*ast.Definition
+}
+
+func newTypeUnion(defs []*ast.Definition) typeUnion {
+	names := make([]string, len(defs))
+	for i, def := range defs {
+		names[i] = StringToJSON(def.Name)
+	}
+
+	return typeUnion{
+		definitions: defs,
+		canonical:   canonicalizeUnion(names),
+	}
+}
+
+// Returns the type of the __typename field of objects of type u.
+func typenameType(u typeUnion) Type {
+	if len(u.definitions) == 0 {
+		return NamedType{Name: "never"}
+	}
+	names := make([]string, len(u.definitions))
+	for i, def := range u.definitions {
+		names[i] = def.Name
+	}
+	// Quoting preserves the order of GraphQL names, so this agrees with the
+	// canonical string.
+	sort.Strings(names)
+	literals := make([]Type, len(names))
+	for i, name := range names {
+		literals[i] = StringLiteralType{Value: name}
+	}
+	return UnionType{Members: literals}
+}
+
+func intersectUnions(a, b typeUnion) typeUnion {
+	seen := make(map[string]bool)
+	for _, def := range a.definitions {
+		seen[def.Name] = true
+	}
+	var common []*ast.Definition
+	for _, def := range b.definitions {
+		if seen[def.Name] {
+			common = append(common, def)
+		}
+	}
+	return newTypeUnion(common)
+}
+
+// Produce a canonicalized TypeScript union, suitable for use as Go map keys.
+func canonicalizeUnion(alternatives []string) string {
+	if len(alternatives) == 0 {
+		return "never"
+	}
+	sort.Strings(alternatives)
+	return strings.Join(alternatives, " | ")
+}
+
+type alternativesBuilder struct {
+	self         typeUnion                 // Current set of applicable concrete types.
+	fields       map[string]Type           // alias -> type.
+	objects      map[string]*objectBuilder // concrete type name -> applicable
+	alternatives map[string]typeUnion      // Set of possible type unions. Keyed by canonical.
+	references   map[string]bool           // Names of declarations referenced.
+}
+
+type objectBuilder struct {
+	fields    map[string]bool
+	fragments map[string]bool
+	typename  bool // Whether __typename was selected.
+}
+
+func newAlternativesBuilder(self typeUnion) *alternativesBuilder {
+	b := &alternativesBuilder{
+		self:    self,
+		fields:  make(map[string]Type),
+		objects: make(map[string]*objectBuilder),
+		alternatives: map[string]typeUnion{
+			self.canonical: self,
+		},
+		references: make(map[string]bool),
+	}
+	// The self constriant is only ever narrowed, so allocate builders for all
+	// the possible concrete types.
+	for _, def := range self.definitions {
+		b.objects[def.Name] = &objectBuilder{
+			fields:    make(map[string]bool),
+			fragments: make(map[string]bool),
+		}
+	}
+	return b
+}
+
+type GeneratedTypes struct {
+	Scalars      []string      `json:"scalars"`
+	QueryMap     []QueryType   `json:"queryMap"`
+	Declarations []Declaration `json:"declarations"` // May contain duplicates. See NewDeclarationSet.
+	// Documents that failed to type, if recorded. See FailedDocument.
+	Failures []FailedDocument `json:"failures,omitempty"`
+}
+
+// A document that failed to type, for which emitters write a placeholder
+// entry in QueryTypes, so that code using it still type-checks while the
+// problem is fixed.
+type FailedDocument struct {
+	Query    string   `json:"query"`
+	Location Location `json:"location"`
+	// The problems, one per line.
+	Error string `json:"error"`
+}
+
+// Returns the types generated since mark, which must be an earlier copy of gt.
+func (gt GeneratedTypes) Since(mark GeneratedTypes) GeneratedTypes {
+	return GeneratedTypes{
+		Scalars:      gt.Scalars[len(mark.Scalars):],
+		QueryMap:     gt.QueryMap[len(mark.QueryMap):],
+		Declarations: gt.Declarations[len(mark.Declarations):],
+	}
+}
+
+func (gt *GeneratedTypes) Append(other GeneratedTypes) {
+	gt.Scalars = append(gt.Scalars, other.Scalars...)
+	gt.QueryMap = append(gt.QueryMap, other.QueryMap...)
+	gt.Declarations = append(gt.Declarations, other.Declarations...)
+}
+
+// An entry of the QueryTypes map, which maps the source text of each
+// document to its data and variables types.
+type QueryType struct {
+	Query     string
+	Operation OperationKind
+	// Empty for anonymous operations.
+	Name      string
+	Location  Location
+	Data      Type
+	Variables Type
+	// Whether the variables may hold files, values of the UploadScalar, so
+	// that the operation must be sent as a multipart request.
+	Multipart bool
+	// Whether the operation is a query with the @live directive, so that its
+	// results are streamed, as are those of subscriptions.
+	Live bool
+	// The document as sent, rewritten by Options.Transforms and formatted.
+	// Empty if there are no transforms.
+	Sent string
+	// Metadata of the operation by name, such as its feature area or owner,
+	// derived from where its input file is. See generate.TagConvention.
+	Tags map[string]string
+	// Nil unless the operation is named and declares default values.
+	Defaults *VariableDefaults
+}
+
+type OperationKind string
+
+const (
+	OperationQuery        OperationKind = "query"
+	OperationMutation     OperationKind = "mutation"
+	OperationSubscription OperationKind = "subscription"
+	OperationFragment     OperationKind = "fragment"
+)
+
+// A position in an input file. Line and Column are 1-based.
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (loc Location) String() string {
+	return fmt.Sprintf("%s:%d:%d", loc.File, loc.Line, loc.Column)
+}
+
+// Renders the type of the entry's value in the QueryTypes map.
+func (q QueryType) Render() string {
+	return q.RenderWith(RenderType)
+}
+
+// Renders the type of the entry's value with the given renderer, such as
+// RenderCompact.
+func (q QueryType) RenderWith(render func(Type) string) string {
+	var flags string
+	if q.Multipart {
+		flags += " multipart: true;"
+	}
+	if q.Live {
+		flags += " live: true;"
+	}
+	if len(q.Tags) > 0 {
+		tags := make([]Field, 0, len(q.Tags))
+		for name, value := range q.Tags {
+			tags = append(tags, Field{Name: name, Type: StringLiteralType{Value: value}})
+		}
+		sort.Slice(tags, func(i, j int) bool {
+			return tags[i].Name < tags[j].Name
+		})
+		flags += " tags: " + render(ObjectType{Fields: tags}) + ";"
+	}
+	return fmt.Sprintf("{ data: %s; variables: %s;%s }", render(q.Data), render(q.Variables), flags)
+}
+
+// Whether results of the entry's operation are streamed, as those of
+// subscriptions and live queries are, rather than sent in a single response.
+func (q QueryType) Streamed() bool {
+	return q.Operation == OperationSubscription || q.Live
+}
+
+func (t *Typer) loadQuery(filename, gql string) (doc *ast.QueryDocument, warnings []error, err error) {
+	t.sent = ""
+	t.skip = false
+	prepared := t.prepared[preparedKey(filename, gql)]
+	switch {
+	case prepared == nil:
+		var gqlErr *gqlerror.Error
+		doc, gqlErr = parser.ParseQuery(&ast.Source{
+			Name:  filename,
+			Input: gql,
+		})
+		if gqlErr != nil {
+			err = &ValidationError{Errors: gqlerror.List{gqlErr}}
+			return
+		}
+		var stripped bool
+		stripped, t.skip, gqlErr = stripSkipDirective(doc)
+		if gqlErr != nil {
+			err = &ValidationError{Errors: gqlerror.List{gqlErr}}
+			return
+		}
+		t.sent = t.transform(doc)
+		if t.sent == "" && stripped {
+			t.sent = formatDocument(doc)
+		}
+	case prepared.err != nil:
+		return nil, nil, prepared.err
+	case prepared.warnings != nil:
+		// Already validated.
+		return prepared.doc, prepared.warnings, nil
+	default:
+		doc = prepared.doc
+	}
+	if prepared != nil {
+		t.sent = prepared.sent
+	}
+
+	diags := t.linkSharedFragments(doc, validator.Validate(t.Schema, doc))
+	diags = t.checkVariables(doc, diags)
+	diags = checkUniqueness(doc, diags)
+	t.suggestNames(diags)
+	var errs gqlerror.List
+	warnings, errs = t.extractWarnings(diags)
+	if len(errs) > 0 {
+		if prepared != nil {
+			prepared.err = &ValidationError{Errors: errs}
+		}
+		return doc, warnings, &ValidationError{Errors: errs}
+	}
+	if t.Options.WarnDeprecated && t.Options.Lint["no-deprecated"].Severity == LintOff {
+		warnings = append(warnings, deprecationWarnings(doc)...)
+	}
+	lintWarnings, lintErrs := t.lint(doc)
+	warnings = append(warnings, lintWarnings...)
+	if len(lintErrs) > 0 {
+		if prepared != nil {
+			prepared.err = &ValidationError{Errors: lintErrs}
+		}
+		return doc, warnings, &ValidationError{Errors: lintErrs}
+	}
+	if prepared != nil {
+		prepared.warnings = warnings
+	}
+	return doc, warnings, nil
+}
+
+func (t *Typer) extractWarnings(diags gqlerror.List) (warnings []error, errs gqlerror.List) {
+	warnings = make([]error, 0, len(diags))
+	errs = make(gqlerror.List, 0, len(diags))
+	for _, diag := range diags {
+		if diag.Rule == "KnownDirectives" && strings.HasPrefix(diag.Message, "Unknown directive") {
+			switch t.Options.UnknownDirectives {
+			case UnknownDirectivesWarning:
+				warnings = append(warnings, diag)
+				continue
+			case UnknownDirectivesAllow:
+				continue
+			}
+		}
+		switch classifyDiagnostic(diag) {
+		case "fail":
+			errs = append(errs, diag)
+		case "warn":
+			warnings = append(warnings, diag)
+		case "ignore":
+			// No-op.
+		}
+	}
+	return
+}
+
+func classifyDiagnostic(err error) string {
+	if _, ok := err.(*gqlerror.Error); ok {
+		message := err.Error()
+		if strings.HasSuffix(message, "is never used.") {
+			return "ignore"
+		}
+		if strings.Contains(message, "Cannot query field") {
+			return "warn"
+		}
+	}
+	return "fail"
+}
+
+// Returns a TypeScript type as a string.
+// On error, that type will be "unknown" with a comment.
+func (t *Typer) VisitString(filename, gql string) (res string, warnings []error, err error) {
+	doc, warnings, err := t.loadQuery(filename, gql)
+	t.filename = filename
+	t.documentShapes = make(map[string]bool)
+	if err == nil && t.skip {
+		return "never /* skipped by @" + SkipDirective + " */", warnings, nil
+	}
+	var entry QueryType
+	if err == nil {
+		t.measures = nil
+		if t.measuringTypes() {
+			t.measures = &[]typeMeasure{}
+		}
+		mark := t.GeneratedTypes
+		entry, err = t.visitDocument(doc)
+		if err == nil && t.measures != nil {
+			typeWarnings, typeErrs := t.lintTypes(*t.measures)
+			// Prepared documents share their warnings, so copy them.
+			warnings = append(warnings[:len(warnings):len(warnings)], typeWarnings...)
+			if len(typeErrs) > 0 {
+				t.GeneratedTypes = mark
+				err = &ValidationError{Errors: typeErrs}
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Sprintf("unknown /* ERROR: %v */", err), warnings, err
+	}
+	entry.Query = gql
+	entry.Sent = t.sent
+	t.GeneratedTypes.QueryMap = append(t.GeneratedTypes.QueryMap, entry)
+	return entry.Render(), warnings, nil
+}
+
+func (t *Typer) visitDocument(doc *ast.QueryDocument) (QueryType, error) {
+	switch len(doc.Operations) {
+	case 0:
+		switch len(doc.Fragments) {
+		case 0:
+			return QueryType{}, ErrNoDefinitions
+		case 1:
+			return t.visitFragmentDefinition(doc.Fragments[0]), nil
+		default:
+			return QueryType{}, &MultipleDefinitionsError{Kind: "fragment", Count: len(doc.Fragments)}
+		}
+	case 1:
+		for _, fragment := range doc.Fragments {
+			t.visitFragmentDefinition(fragment)
+		}
+		return t.visitOperationDefinition(doc.Operations[0]), nil
+	default:
+		return QueryType{}, &MultipleDefinitionsError{Kind: "operation", Count: len(doc.Operations)}
+	}
+}
+
+func (t *Typer) visitOperationDefinition(def *ast.OperationDefinition) QueryType {
+	var objectType *ast.Definition
+	var opKind string
+	switch def.Operation {
+	case ast.Query:
+		opKind = "Query"
+		objectType = t.Schema.Query
+	case ast.Mutation:
+		opKind = "Mutation"
+		objectType = t.Schema.Mutation
+	case ast.Subscription:
+		opKind = "Subscription"
+		objectType = t.Schema.Subscription
+	default:
+		panic(fmt.Errorf("unexpected kind of operation: %q", def.Operation))
+	}
+	end := t.startDefinition(opKind, def.Name, def.Position, objectType)
+	t.visitVariableDefinitions(def.VariableDefinitions)
+	t.visitSelectionSet(def.SelectionSet)
+	entry := end()
+	entry.Operation = OperationKind(def.Operation)
+	entry.Location = t.location(def.Position)
+	entry.Live = def.Operation == ast.Query && def.Directives.ForName(LiveDirective) != nil
+	entry.Defaults = t.variableDefaults(opKind, def)
+	return entry
+}
+
+func (t *Typer) location(pos *ast.Position) Location {
+	loc := Location{File: t.filename}
+	if pos != nil {
+		loc.Line = pos.Line
+		loc.Column = pos.Column
+	}
+	return loc
+}
+
+func (t *Typer) computeConcreteUnion(def *ast.Definition) typeUnion {
+	switch def.Kind {
+	case ast.Object:
+		return newTypeUnion([]*ast.Definition{def})
+
+	case ast.Interface:
+		var defs []*ast.Definition
+		for _, candidate := range t.Schema.Types {
+			if candidate.Kind != ast.Object {
+				continue
+			}
+			for _, iface := range candidate.Interfaces {
+				if iface == def.Name {
+					defs = append(defs, candidate)
+					break
+				}
+			}
+		}
+		return newTypeUnion(defs)
+
+	case ast.Union:
+		defs := make([]*ast.Definition, len(def.Types))
+		for i, name := range def.Types {
+			defs[i] = t.getDefinition(name)
+		}
+		return newTypeUnion(defs)
+
+	case ast.Scalar, ast.Enum, ast.InputObject:
+		panic(fmt.Errorf("expected only composite types, got %q", def.Kind))
+
+	default:
+		panic(fmt.Errorf("unknown kind: %q", def.Kind))
+	}
+}
+
+func (t *Typer) visitFragmentDefinition(op *ast.FragmentDefinition) QueryType {
+	objectType := t.getDefinition(op.TypeCondition)
+	end := t.startDefinition("Fragment", op.Name, op.Position, objectType)
+	t.visitSelectionSet(op.SelectionSet)
+	entry := end()
+	entry.Operation = OperationFragment
+	entry.Location = t.location(op.Position)
+	return entry
+}
+
+func (t *Typer) startDefinition(opKind, name string, pos *ast.Position, objectType *ast.Definition) (end func() QueryType) {
+	t.variables = make(map[string]Type)
+	t.multipart = false
+	endObject := t.startObject(objectType)
+	return func() QueryType {
+		dataType, references := endObject()
+		t.measureType(nil, pos, dataType)
+		entry := t.buildDocumentType(opKind, name, dataType, references)
+		entry.Multipart = t.multipart
+		t.variables = nil
+		return entry
+	}
+}
+
+func (t *Typer) startObject(typ *ast.Definition) (end func() (dataType Type, references map[string]bool)) {
+	oldBuilder := t.alternativesBuilder
+
+	concreteTypes := t.toConcreteUnion(typ)
+	t.alternativesBuilder = newAlternativesBuilder(concreteTypes)
+
+	return func() (Type, map[string]bool) {
+		dataType := t.buildDataType()
+		references := t.references
+		t.alternativesBuilder = oldBuilder
+		return dataType, references
+	}
+}
+
+func (t *Typer) getDefinition(name string) *ast.Definition {
+	return t.Schema.Types[name]
+}
+
+func (t *Typer) narrow(target *ast.Definition) (widen func()) {
+	old := t.self
+	u := t.intersectUnions(old, t.toConcreteUnion(target))
+	t.self = u
+	t.alternatives[u.canonical] = u
+	return func() {
+		t.self = old
+	}
+}
+
+func (t *Typer) buildDocumentType(prefix, name string, dataType Type, references map[string]bool) QueryType {
+	variablesType := t.buildVariablesType()
+
+	if name != "" {
+		dataName := t.declarationName(prefix, name, "Data")
+		variablesName := t.declarationName(prefix, name, "Variables")
+		t.Declarations = append(t.Declarations,
+			newDeclaration(dataName, DeclarationData, t.filename, dataType, references),
+			newDeclaration(variablesName, DeclarationVariables, t.filename, variablesType, nil),
+		)
+		dataType = NamedType{Name: dataName}
+		variablesType = NamedType{Name: variablesName}
+	}
+
+	return QueryType{
+		Name:      name,
+		Data:      dataType,
+		Variables: variablesType,
+	}
+}
+
+func (t *Typer) buildDataType() Type {
+	if len(t.alternatives) == 0 {
+		return RawType{Text: "/* buildDataType */ never"}
+	}
+	typenameUnions := make([]string, 0, len(t.alternatives))
+	for key := range t.alternatives {
+		typenameUnions = append(typenameUnions, key)
+	}
+	sort.Strings(typenameUnions)
+
+	if len(typenameUnions) == 1 {
+		return t.buildObject(t.alternatives[typenameUnions[0]])
+	}
+	members := make([]Type, len(typenameUnions))
+	for i, typenameUnion := range typenameUnions {
+		members[i] = t.buildObject(t.alternatives[typenameUnion])
+	}
+	return UnionType{Members: members}
+}
+
+func (t *Typer) buildVariablesType() Type {
+	variableNames := make([]string, 0, len(t.variables))
+	for variableName := range t.variables {
+		variableNames = append(variableNames, variableName)
+	}
+	sort.Strings(variableNames)
+	fields := make([]Field, len(variableNames))
+	for i, name := range variableNames {
+		fields[i] = Field{Name: name, Type: t.variables[name]}
+	}
+	return ObjectType{Fields: fields}
+}
+
+func (t *Typer) buildObject(types typeUnion) Type {
+	fieldSet := make(map[string]bool)
+	fragmentSet := make(map[string]bool)
+	var fieldAliases, fragmentNames []string
+	typename := t.Options.Typename == TypenameAlways
+
+	for _, def := range types.definitions {
+		obj := t.objects[def.Name]
+		typename = typename || obj.typename
+		for fieldAlias := range obj.fields {
+			if fieldSet[fieldAlias] {
+				continue
+			}
+			fieldSet[fieldAlias] = true
+			fieldAliases = append(fieldAliases, fieldAlias)
+		}
+		for fragmentName := range obj.fragments {
+			if fragmentSet[fragmentName] {
+				continue
+			}
+			fragmentSet[fragmentName] = true
+			fragmentNames = append(fragmentNames, fragmentName)
+		}
+	}
+	sort.Strings(fieldAliases)
+	sort.Strings(fragmentNames)
+
+	fields := make([]Field, 0, len(fieldAliases)+1)
+	if typename {
+		fields = append(fields, Field{Name: "__typename", Type: t.typenameType(types)})
+	}
+	for _, name := range fieldAliases {
+		fields = append(fields, Field{Name: name, Type: t.fields[name]})
+	}
+	var obj Type = ObjectType{Fields: fields}
+	if len(fragmentNames) == 0 {
+		return obj
+	}
+	members := make([]Type, 0, len(fragmentNames)+1)
+	members = append(members, obj)
+	for _, name := range fragmentNames {
+		fragmentType := t.declarationName("Fragment", name, "Data")
+		t.references[fragmentType] = true
+		members = append(members, NamedType{Name: fragmentType})
+	}
+	return IntersectionType{Members: members}
+}
+
+func (t *Typer) visitVariableDefinitions(vars ast.VariableDefinitionList) {
+	for _, v := range vars {
+		t.visitVariableDefinition(v)
+	}
+}
+
+func (t *Typer) visitVariableDefinition(def *ast.VariableDefinition) {
+	name := def.Variable
+	if _, exists := t.variables[name]; exists {
+		// TODO: Check for conflicts.
+		return
+	}
+	t.variables[name] = t.visitType(def.Type)
+	if t.holdsUploads(leafTypeName(def.Type), make(map[string]bool)) {
+		t.multipart = true
+	}
+}
+
+// Whether values of the named type may hold values of the UploadScalar,
+// directly or in fields of input objects. Visited guards against cycles.
+func (t *Typer) holdsUploads(name string, visited map[string]bool) bool {
+	def := t.getDefinition(name)
+	if def == nil || visited[name] {
+		return false
+	}
+	visited[name] = true
+	switch def.Kind {
+	case ast.Scalar:
+		return name == UploadScalar
+	case ast.InputObject:
+		for _, field := range def.Fields {
+			if t.holdsUploads(leafTypeName(field.Type), visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *Typer) visitSelectionSet(selections ast.SelectionSet) {
+	for _, selection := range selections {
+		t.visitSelection(selection)
+	}
+}
+
+func (t *Typer) concreteTypename(name string) string {
+	typ := t.Schema.Types[name]
+	if typ != nil && typ.Kind == ast.Object {
+		return StringToJSON(typ.Name)
+	} else {
+		return "string"
+	}
+}
+
+func (t *Typer) visitSelection(node ast.Selection) {
+	switch node := node.(type) {
+	case *ast.Field:
+		t.visitField(node)
+	case *ast.FragmentSpread:
+		t.visitFragmentSpread(node)
+	case *ast.InlineFragment:
+		t.visitInlineFragment(node)
+	default:
+		panic(fmt.Errorf("unexpected selection type: %T", node))
+	}
+}
+
+func (t *Typer) visitField(node *ast.Field) {
+	def := node.Definition
+	alias := node.Alias
+	if alias == "" {
+		alias = node.Name
+	}
+	if alias == "__typename" {
+		for _, def := range t.self.definitions {
+			t.objects[def.Name].typename = true
+		}
+		return
+	}
+	t.visitArgumentList(node.Arguments)
+	var fieldType Type
+	if def == nil {
+		fieldType = NamedType{Name: "unknown"}
+	} else if node.SelectionSet == nil {
+		fieldType = t.visitType(def.Type)
+	} else {
+		leafName := leafTypeName(def.Type)
+		endObject := t.startObject(t.getDefinition(leafName))
+		t.visitSelectionSet(node.SelectionSet)
+		dataType, references := endObject()
+		t.measureType(node, node.Position, dataType)
+		if t.Options.ShareShapes {
+			name := t.shareShape(dataType, references)
+			t.references[name] = true
+			dataType = NamedType{Name: name}
+		} else {
+			for name := range references {
+				t.references[name] = true
+			}
+		}
+		fieldType = t.wrapType(def.Type, dataType)
+	}
+	t.fields[alias] = fieldType
+	for _, def := range t.self.definitions {
+		t.objects[def.Name].fields[alias] = true
+	}
+}
+
+func leafTypeName(typ *ast.Type) string {
+	for typ.Elem != nil {
+		typ = typ.Elem
+	}
+	return typ.NamedType
+}
+
+// Wraps the translation of typ's leaf type in typ's list and nullability
+// modifiers.
+func (t *Typer) wrapType(typ *ast.Type, leaf Type) Type {
+	res := leaf
+	if typ.Elem != nil {
+		res = ArrayType{Elem: t.wrapType(typ.Elem, leaf)}
+	}
+	if !typ.NonNull {
+		res = NullableType{
+			Type:      res,
+			Undefined: t.Options.Nullability == NullabilityNullOrUndefined,
+		}
+	}
+	return res
+}
+
+func (t *Typer) visitFragmentSpread(node *ast.FragmentSpread) {
+	widen := t.narrow(t.getDefinition(node.Definition.TypeCondition))
+	defer widen()
+
+	if node.Name == "" {
+		t.visitSelectionSet(node.Definition.SelectionSet)
+	} else {
+		for _, def := range t.self.definitions {
+			t.objects[def.Name].fragments[node.Name] = true
+		}
+	}
+}
+
+func (t *Typer) visitInlineFragment(node *ast.InlineFragment) {
+	// Without a type condition, the fragment is of the enclosing type.
+	if node.TypeCondition != "" {
+		widen := t.narrow(t.getDefinition(node.TypeCondition))
+		defer widen()
+	}
+
+	t.visitSelectionSet(node.SelectionSet)
+}
+
+func (t *Typer) visitType(typ *ast.Type) Type {
+	leafName := leafTypeName(typ)
+	if t.Options.ResolveLeafType != nil {
+		if def := t.getDefinition(leafName); def != nil {
+			if resolved, ok := t.Options.ResolveLeafType(def); ok {
+				return t.wrapType(typ, RawType{Text: resolved})
+			}
+		}
+	}
+	if mapped, ok := t.Options.Scalars[leafName]; ok {
+		return t.wrapType(typ, RawType{Text: mapped})
+	}
+	switch leafName {
+	case "String", "ID":
+		return t.wrapType(typ, NamedType{Name: "string", Scalar: leafName})
+	case "Boolean":
+		return t.wrapType(typ, NamedType{Name: "boolean", Scalar: leafName})
+	case "Int", "Float":
+		return t.wrapType(typ, NamedType{Name: "number", Scalar: leafName})
+	}
+	t.Scalars = append(t.Scalars, leafName)
+	return t.wrapType(typ, NamedType{Name: leafName})
+}
+
+func (t *Typer) visitArgumentList(args ast.ArgumentList) {
+	for _, arg := range args {
+		t.visitArgument(arg)
+	}
+}
+
+func (t *Typer) visitArgument(arg *ast.Argument) {
+	t.visitValue(arg.Value)
+}
+
+func (t *Typer) visitValue(v *ast.Value) {
+	switch v.Kind {
+	case ast.Variable:
+		// Variables of shared fragments have no definition here, but are
+		// those of the operations spreading them, which type them.
+		if v.VariableDefinition != nil {
+			t.visitVariableDefinition(v.VariableDefinition)
+		}
+	}
+}
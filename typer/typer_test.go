@@ -0,0 +1,74 @@
+package typer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GeneratedTypes with declarations rendered, for readable comparisons.
+type renderedTypes struct {
+	Scalars      []string
+	QueryMap     []renderedQuery
+	Declarations []string
+}
+
+type renderedQuery struct {
+	Query string
+	Type  string
+}
+
+func renderTypes(types GeneratedTypes) renderedTypes {
+	res := renderedTypes{
+		Scalars: types.Scalars,
+	}
+	for _, entry := range types.QueryMap {
+		res.QueryMap = append(res.QueryMap, renderedQuery{
+			Query: entry.Query,
+			Type:  entry.Render(),
+		})
+	}
+	for _, decl := range types.Declarations {
+		res.Declarations = append(res.Declarations, decl.String())
+	}
+	return res
+}
+
+// Builds a schema where an interface is implemented by many object types.
+func wideInterfaceSchema(width int) *ast.Schema {
+	var b strings.Builder
+	b.WriteString("type Query { nodes: [Node!]! }\n")
+	b.WriteString("interface Node { id: ID! }\n")
+	for i := 0; i < width; i++ {
+		fmt.Fprintf(&b, "type T%d implements Node { id: ID! value: Int! }\n", i)
+	}
+	return gqlparser.MustLoadSchema(&ast.Source{
+		Name:  "schema.gql",
+		Input: b.String(),
+	})
+}
+
+func BenchmarkWideInterface(b *testing.B) {
+	schema := wideInterfaceSchema(200)
+	var q strings.Builder
+	q.WriteString("{ nodes { id")
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&q, " ... on T%d { value }", i)
+	}
+	q.WriteString(" } }")
+	query := q.String()
+
+	b.ReportAllocs()
+	typer := &Typer{
+		Schema: schema,
+	}
+	for i := 0; i < b.N; i++ {
+		typer.GeneratedTypes = GeneratedTypes{}
+		if _, _, err := typer.VisitString("", query); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
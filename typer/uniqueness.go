@@ -0,0 +1,112 @@
+package typer
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// The validator reports variables and arguments given more than once among
+// the errors that follow from them, such as those of the usages of a variable
+// whose type is then ambiguous, and without saying what the repeats are. They
+// are reported here first, at each repeat, naming the definitions or values
+// that conflict.
+
+const (
+	uniqueVariablesRule = "UniqueVariableNames"
+	uniqueArgumentsRule = "UniqueArgumentNames"
+)
+
+// Replaces the validator's diagnostics of repeated variables and arguments
+// with those of uniquenessErrors, before any others.
+func checkUniqueness(doc *ast.QueryDocument, diags gqlerror.List) gqlerror.List {
+	res := uniquenessErrors(doc)
+	for _, diag := range diags {
+		if diag.Rule != uniqueVariablesRule && diag.Rule != uniqueArgumentsRule {
+			res = append(res, diag)
+		}
+	}
+	return res
+}
+
+func uniquenessErrors(doc *ast.QueryDocument) gqlerror.List {
+	var errs gqlerror.List
+	for _, op := range doc.Operations {
+		first := make(map[string]*ast.VariableDefinition)
+		for _, def := range op.VariableDefinitions {
+			prev := first[def.Variable]
+			if prev == nil {
+				first[def.Variable] = def
+				continue
+			}
+			err := gqlerror.ErrorPosf(def.Position, `Variable "$%s" is defined more than once, as "%s" and as "%s". Remove or rename one of them.`,
+				def.Variable, prev.Type, def.Type)
+			err.Rule = uniqueVariablesRule
+			errs = append(errs, err)
+		}
+		for _, def := range op.VariableDefinitions {
+			errs = append(errs, uniqueArguments(def.Directives)...)
+		}
+		errs = append(errs, uniqueArguments(op.Directives)...)
+		errs = append(errs, uniqueSelectionArguments(op.SelectionSet)...)
+	}
+	for _, fragment := range doc.Fragments {
+		errs = append(errs, uniqueArguments(fragment.Directives)...)
+		errs = append(errs, uniqueSelectionArguments(fragment.SelectionSet)...)
+	}
+	return errs
+}
+
+func uniqueSelectionArguments(set ast.SelectionSet) gqlerror.List {
+	var errs gqlerror.List
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			errs = append(errs, uniqueArgumentList(selection.Arguments, `field "`+selection.Name+`"`)...)
+			errs = append(errs, uniqueArguments(selection.Directives)...)
+			errs = append(errs, uniqueSelectionArguments(selection.SelectionSet)...)
+		case *ast.InlineFragment:
+			errs = append(errs, uniqueArguments(selection.Directives)...)
+			errs = append(errs, uniqueSelectionArguments(selection.SelectionSet)...)
+		case *ast.FragmentSpread:
+			errs = append(errs, uniqueArguments(selection.Directives)...)
+		}
+	}
+	return errs
+}
+
+func uniqueArguments(directives ast.DirectiveList) gqlerror.List {
+	var errs gqlerror.List
+	for _, directive := range directives {
+		errs = append(errs, uniqueArgumentList(directive.Arguments, `directive "@`+directive.Name+`"`)...)
+	}
+	return errs
+}
+
+// Reports each repeat of an argument of the given field or directive.
+func uniqueArgumentList(args ast.ArgumentList, of string) gqlerror.List {
+	var errs gqlerror.List
+	first := make(map[string]*ast.Argument)
+	for _, arg := range args {
+		prev := first[arg.Name]
+		if prev == nil {
+			first[arg.Name] = arg
+			continue
+		}
+		err := gqlerror.ErrorPosf(arg.Position, `Argument "%s" is given to %s more than once, as %s and as %s. Remove one of them.`,
+			arg.Name, of, prev.Value, arg.Value)
+		err.Rule = uniqueArgumentsRule
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Whether op defines a variable of the given name more than once.
+func repeatedVariable(op *ast.OperationDefinition, name string) bool {
+	n := 0
+	for _, def := range op.VariableDefinitions {
+		if def.Variable == name {
+			n++
+		}
+	}
+	return n > 1
+}
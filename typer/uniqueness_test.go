@@ -0,0 +1,40 @@
+package typer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestUniqueness(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+			}
+
+			type User {
+				name: String!
+			}
+		`,
+	})
+	typer := &Typer{Schema: schema}
+	_, _, err := typer.VisitString("", "query Q($id: ID, $id: ID!, $other: ID!) {\n  user(id: $id, id: $other) {\n    name @include(if: true, if: false)\n  }\n}")
+	var validationErr *ValidationError
+	if !assert.True(t, errors.As(err, &validationErr)) {
+		return
+	}
+	var messages []string
+	for _, err := range validationErr.Errors {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		`input:1: Variable "$id" is defined more than once, as "ID" and as "ID!". Remove or rename one of them.`,
+		`input:2: Argument "id" is given to field "user" more than once, as $id and as $other. Remove one of them.`,
+		`input:3: Argument "if" is given to directive "@include" more than once, as true and as false. Remove one of them.`,
+	}, messages)
+}
@@ -0,0 +1,136 @@
+package typer
+
+import (
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Maps operations, fragments, and schema fields to the places in source files
+// that use them, for impact analysis and for finding usages across the GraphQL
+// boundary.
+type Usages struct {
+	Operations []DocumentUsage `json:"operations"`
+	Fragments  []DocumentUsage `json:"fragments"`
+	// Locations of the documents that select each field, by coordinate, such
+	// as User.name. Fields selected by a fragment are used both where it is
+	// defined and by the operations that spread it.
+	Fields map[string][]Location `json:"fields"`
+}
+
+type DocumentUsage struct {
+	Operation OperationKind `json:"operation"`
+	// Empty for anonymous operations.
+	Name string `json:"name"`
+	// Where the document is written.
+	Definitions []Location `json:"definitions"`
+	// Where operations that spread the fragment, directly or not, are written.
+	// Empty for operations.
+	UsedBy []Location `json:"usedBy,omitempty"`
+}
+
+// Returns the usages of the operations and fragments in types, which must
+// have been generated against schema, in order of appearance. Identical
+// documents are merged.
+func UsageMap(schema *ast.Schema, types GeneratedTypes) (Usages, error) {
+	shared, err := parseSharedFragments(types)
+	if err != nil {
+		return Usages{}, err
+	}
+	usages := Usages{
+		Operations: []DocumentUsage{},
+		Fragments:  []DocumentUsage{},
+		Fields:     make(map[string][]Location),
+	}
+	fields := make(map[string]map[Location]bool)
+	addFields := func(r *reporter, loc Location) {
+		for coordinate := range r.fields {
+			if fields[coordinate] == nil {
+				fields[coordinate] = make(map[Location]bool)
+			}
+			fields[coordinate][loc] = true
+		}
+	}
+
+	byQuery := make(map[string]*DocumentUsage)
+	var order []string
+	for _, entry := range types.QueryMap {
+		usage, ok := byQuery[entry.Query]
+		if !ok {
+			usage = &DocumentUsage{Operation: entry.Operation, Name: entry.Name}
+			byQuery[entry.Query] = usage
+			order = append(order, entry.Query)
+		}
+		usage.Definitions = append(usage.Definitions, entry.Location)
+		if entry.Operation != OperationFragment {
+			continue
+		}
+		fragment := shared[entry.Name]
+		if fragment == nil {
+			continue
+		}
+		r := newReporter(schema, nil, shared)
+		r.spreading[fragment.Name] = true
+		r.walk(schema.Types[fragment.TypeCondition], fragment.SelectionSet, 1, "")
+		addFields(r, entry.Location)
+	}
+
+	// Fragments by name, including those local to operation documents.
+	fragments := make(map[string]*DocumentUsage)
+	for _, query := range order {
+		if usage := byQuery[query]; usage.Operation == OperationFragment {
+			if _, ok := fragments[usage.Name]; !ok {
+				fragments[usage.Name] = usage
+			}
+		}
+	}
+	err = walkOperations(schema, types, func(entry QueryType, r *reporter) {
+		addFields(r, entry.Location)
+		for _, name := range sortedKeys(r.spread) {
+			usage := fragments[name]
+			if usage == nil {
+				// Defined in the operation's own document.
+				usage = &DocumentUsage{Operation: OperationFragment, Name: name, Definitions: []Location{entry.Location}}
+				fragments[name] = usage
+				key := "fragment " + name
+				byQuery[key] = usage
+				order = append(order, key)
+			}
+			usage.UsedBy = append(usage.UsedBy, entry.Location)
+		}
+	})
+	if err != nil {
+		return Usages{}, err
+	}
+	for _, query := range order {
+		usage := byQuery[query]
+		if usage.Operation == OperationFragment {
+			sortLocations(usage.UsedBy)
+			usages.Fragments = append(usages.Fragments, *usage)
+		} else {
+			usages.Operations = append(usages.Operations, *usage)
+		}
+	}
+	for coordinate, set := range fields {
+		locs := make([]Location, 0, len(set))
+		for loc := range set {
+			locs = append(locs, loc)
+		}
+		sortLocations(locs)
+		usages.Fields[coordinate] = locs
+	}
+	return usages, nil
+}
+
+func sortLocations(locs []Location) {
+	sort.Slice(locs, func(i, j int) bool {
+		a, b := locs[i], locs[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
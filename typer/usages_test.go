@@ -0,0 +1,65 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestUsageMap(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+				now: String!
+			}
+
+			type User {
+				id: ID!
+				name: String!
+			}
+		`,
+	})
+	tp := &Typer{Schema: schema}
+	inputs := []struct{ file, query string }{
+		{"Name.tsx", `fragment UserName on User { name }`},
+		{"User.tsx", `query GetUser($id: ID!) { user(id: $id) { ...UserName ...UserID } } fragment UserID on User { id }`},
+		{"Clock.tsx", `{ now }`},
+		{"Other.tsx", `{ now }`},
+	}
+	for _, in := range inputs {
+		tp.PrepareString(in.file, in.query)
+	}
+	for _, in := range inputs {
+		if _, _, err := tp.VisitString(in.file, in.query); !assert.NoError(t, err) {
+			return
+		}
+	}
+	usages, err := UsageMap(schema, tp.GeneratedTypes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	name := Location{File: "Name.tsx", Line: 1, Column: 1}
+	user := Location{File: "User.tsx", Line: 1, Column: 1}
+	clock := Location{File: "Clock.tsx", Line: 1, Column: 1}
+	other := Location{File: "Other.tsx", Line: 1, Column: 1}
+	assert.Equal(t, Usages{
+		Operations: []DocumentUsage{
+			{Operation: OperationQuery, Name: "GetUser", Definitions: []Location{user}},
+			{Operation: OperationQuery, Definitions: []Location{clock, other}},
+		},
+		Fragments: []DocumentUsage{
+			{Operation: OperationFragment, Name: "UserName", Definitions: []Location{name}, UsedBy: []Location{user}},
+			{Operation: OperationFragment, Name: "UserID", Definitions: []Location{user}, UsedBy: []Location{user}},
+		},
+		Fields: map[string][]Location{
+			"Query.user": {user},
+			"Query.now":  {clock, other},
+			"User.id":    {user},
+			"User.name":  {name, user},
+		},
+	}, usages)
+}
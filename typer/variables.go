@@ -0,0 +1,263 @@
+package typer
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// The validator's check that variables are used where their types are
+// allowed rejects usages in arguments with default values, which the
+// specification allows, does not follow spreads of shared fragments, and does
+// not say why a usage is not allowed. It is replaced here by an
+// implementation of the specification's "All Variable Usages Are Allowed".
+// Variables used only within shared fragments are not unused.
+
+const variablesRule = "VariablesInAllowedPosition"
+
+// Replaces the validator's diagnostics of variable usages with those of
+// checkVariableUsages.
+func (t *Typer) checkVariables(doc *ast.QueryDocument, diags gqlerror.List) gqlerror.List {
+	var errs gqlerror.List
+	used := make(map[gqlerror.Location]bool)
+	for _, op := range doc.Operations {
+		u := t.checkVariableUsages(doc, op)
+		errs = append(errs, u.errs...)
+		for _, def := range op.VariableDefinitions {
+			if u.used[def.Variable] {
+				used[gqlerror.Location{Line: def.Position.Line, Column: def.Position.Column}] = true
+			}
+		}
+	}
+	res := make(gqlerror.List, 0, len(diags)+len(errs))
+	for _, diag := range diags {
+		switch {
+		case diag.Rule == variablesRule:
+		case diag.Rule == "NoUnusedVariables" && len(diag.Locations) > 0 && used[diag.Locations[0]]:
+		default:
+			res = append(res, diag)
+		}
+	}
+	return append(res, errs...)
+}
+
+type variableUsages struct {
+	t       *Typer
+	doc     *ast.QueryDocument
+	op      *ast.OperationDefinition
+	visited map[string]bool
+	// Where to report problems within the shared fragment being walked, which
+	// is the spread of it in doc, or nil within doc.
+	spread *ast.FragmentSpread
+	used   map[string]bool
+	errs   []*gqlerror.Error
+}
+
+func (t *Typer) checkVariableUsages(doc *ast.QueryDocument, op *ast.OperationDefinition) *variableUsages {
+	u := &variableUsages{
+		t:       t,
+		doc:     doc,
+		op:      op,
+		visited: make(map[string]bool),
+		used:    make(map[string]bool),
+	}
+	var root *ast.Definition
+	switch op.Operation {
+	case ast.Query:
+		root = t.Schema.Query
+	case ast.Mutation:
+		root = t.Schema.Mutation
+	case ast.Subscription:
+		root = t.Schema.Subscription
+	}
+	u.directives(op.Directives)
+	u.selectionSet(op.SelectionSet, root)
+	return u
+}
+
+// Walks a selection set of the parent type, which is nil if unknown. Types
+// are found from the schema, rather than from the validator's annotations,
+// since shared fragments may not have been validated yet.
+func (u *variableUsages) selectionSet(set ast.SelectionSet, parent *ast.Definition) {
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			var def *ast.FieldDefinition
+			var typ *ast.Definition
+			if parent != nil {
+				def = parent.Fields.ForName(selection.Name)
+			}
+			var args ast.ArgumentDefinitionList
+			if def != nil {
+				args = def.Arguments
+				typ = u.t.Schema.Types[def.Type.Name()]
+			}
+			u.arguments(selection.Arguments, args)
+			u.directives(selection.Directives)
+			u.selectionSet(selection.SelectionSet, typ)
+		case *ast.InlineFragment:
+			u.directives(selection.Directives)
+			typ := parent
+			if selection.TypeCondition != "" {
+				typ = u.t.Schema.Types[selection.TypeCondition]
+			}
+			u.selectionSet(selection.SelectionSet, typ)
+		case *ast.FragmentSpread:
+			u.directives(selection.Directives)
+			fragment := selection.Definition
+			if fragment == nil || u.visited[fragment.Name] {
+				continue
+			}
+			u.visited[fragment.Name] = true
+			typ := u.t.Schema.Types[fragment.TypeCondition]
+			if u.spread == nil && u.doc.Fragments.ForName(fragment.Name) != fragment {
+				u.spread = selection
+				u.selectionSet(fragment.SelectionSet, typ)
+				u.spread = nil
+			} else {
+				u.selectionSet(fragment.SelectionSet, typ)
+			}
+		}
+	}
+}
+
+func (u *variableUsages) directives(directives ast.DirectiveList) {
+	for _, directive := range directives {
+		var args ast.ArgumentDefinitionList
+		if def := u.t.Schema.Directives[directive.Name]; def != nil {
+			args = def.Arguments
+		}
+		u.arguments(directive.Arguments, args)
+	}
+}
+
+// Checks the arguments of the given definitions. The variables of arguments
+// not defined are only noted as used.
+func (u *variableUsages) arguments(args ast.ArgumentList, defs ast.ArgumentDefinitionList) {
+	for _, arg := range args {
+		if def := defs.ForName(arg.Name); def != nil {
+			u.value(arg.Value, def.Type, def.DefaultValue != nil)
+		} else {
+			u.value(arg.Value, nil, false)
+		}
+	}
+}
+
+// Checks the variable usages within a value in a position of the given type,
+// which is nil if unknown.
+func (u *variableUsages) value(value *ast.Value, location *ast.Type, hasLocationDefault bool) {
+	if value == nil {
+		return
+	}
+	switch value.Kind {
+	case ast.Variable:
+		u.used[value.Raw] = true
+		def := u.op.VariableDefinitions.ForName(value.Raw)
+		if def == nil || location == nil || repeatedVariable(u.op, value.Raw) {
+			// Reported by NoUndefinedVariables, by the rule that rejects the
+			// position, or as a repeated variable, whose type is ambiguous.
+			return
+		}
+		if reason := variableUsageProblem(def, location, hasLocationDefault); reason != "" {
+			u.report(value, def, location, reason)
+		}
+	case ast.ListValue:
+		var elem *ast.Type
+		if location != nil {
+			elem = location.Elem
+		}
+		for _, child := range value.Children {
+			u.value(child.Value, elem, false)
+		}
+	case ast.ObjectValue:
+		var def *ast.Definition
+		if location != nil {
+			def = u.t.Schema.Types[location.Name()]
+		}
+		for _, child := range value.Children {
+			var field *ast.FieldDefinition
+			if def != nil {
+				field = def.Fields.ForName(child.Name)
+			}
+			if field != nil {
+				u.value(child.Value, field.Type, field.DefaultValue != nil)
+			} else {
+				u.value(child.Value, nil, false)
+			}
+		}
+	}
+}
+
+func (u *variableUsages) report(value *ast.Value, def *ast.VariableDefinition, location *ast.Type, reason string) {
+	pos := value.Position
+	var in string
+	if u.spread != nil {
+		pos = u.spread.Position
+		in = fmt.Sprintf(" in fragment \"%s\"", u.spread.Name)
+	}
+	err := gqlerror.ErrorPosf(pos, `Variable "$%s" of type "%s" used%s in position expecting type "%s": %s.`,
+		def.Variable, def.Type, in, location, reason)
+	err.Rule = variablesRule
+	u.errs = append(u.errs, err)
+}
+
+// Returns why a variable may not be used in a position of the given type, or
+// the empty string if it may be.
+func variableUsageProblem(def *ast.VariableDefinition, location *ast.Type, hasLocationDefault bool) string {
+	if location.NonNull && !def.Type.NonNull {
+		hasDefault := def.DefaultValue != nil && def.DefaultValue.Kind != ast.NullValue
+		if !hasDefault && !hasLocationDefault {
+			required := *def.Type
+			required.NonNull = true
+			return fmt.Sprintf(`it may be null, but the position requires a value. Declare it as "%s", or give it a default value`, &required)
+		}
+		nullable := *location
+		nullable.NonNull = false
+		location = &nullable
+	}
+	if have, want := listDimensions(def.Type), listDimensions(location); have != want {
+		return fmt.Sprintf("it is %s, but the position expects %s", describeDimensions(have), describeDimensions(want))
+	}
+	return typeCompatibilityProblem(def.Type, location, "it")
+}
+
+// Returns why values of a variable's type may not be used where values of the
+// location's type are expected, which have as many list dimensions, or the
+// empty string if they may be. Subject names the values compared.
+func typeCompatibilityProblem(variable, location *ast.Type, subject string) string {
+	switch {
+	case location.NonNull && !variable.NonNull:
+		return fmt.Sprintf("%s may be null, but the position requires them not to be", subject)
+	case location.Elem != nil:
+		items := "its items"
+		if subject != "it" {
+			items = "the items of " + subject
+		}
+		return typeCompatibilityProblem(variable.Elem, location.Elem, items)
+	case variable.NamedType != location.NamedType:
+		return fmt.Sprintf(`the position expects "%s", not "%s"`, location.NamedType, variable.NamedType)
+	}
+	return ""
+}
+
+func listDimensions(typ *ast.Type) int {
+	n := 0
+	for ; typ.Elem != nil; typ = typ.Elem {
+		n++
+	}
+	return n
+}
+
+func describeDimensions(n int) string {
+	switch n {
+	case 0:
+		return "not a list"
+	case 1:
+		return "a list"
+	case 2:
+		return "a list of lists"
+	default:
+		return fmt.Sprintf("a list of %d dimensions", n)
+	}
+}
@@ -0,0 +1,131 @@
+package typer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestVariableUsages(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+				users(ids: [ID!]!): [User!]!
+				grid(cells: [[Int!]]): Int
+				page(first: Int! = 10, filter: Filter): [User!]!
+			}
+
+			input Filter {
+				name: String!
+				limit: Int! = 5
+			}
+
+			type User {
+				name: String!
+				friends(first: Int!): [User!]!
+			}
+		`,
+	})
+	tests := []struct {
+		Input    string
+		Expected string
+	}{
+		{
+			Input: `query Q($id: ID!, $ids: [ID!]!, $cells: [[Int!]!], $name: String!) { user(id: $id) { name } users(ids: $ids) { name } grid(cells: $cells) other: users(ids: [$id]) { name } page(filter: { name: $name }) { name } }`,
+		},
+		{
+			// Defaults of variables and of arguments allow nullable variables.
+			Input: `query Q($id: ID = "1", $first: Int, $limit: Int) { user(id: $id) { name } page(first: $first, filter: { name: "x", limit: $limit }) { name } }`,
+		},
+		{
+			Input:    `query Q($id: ID) { user(id: $id) { name } }`,
+			Expected: `input:1: Variable "$id" of type "ID" used in position expecting type "ID!": it may be null, but the position requires a value. Declare it as "ID!", or give it a default value.`,
+		},
+		{
+			Input:    `query Q($id: ID!) { users(ids: $id) { name } }`,
+			Expected: `input:1: Variable "$id" of type "ID!" used in position expecting type "[ID!]!": it is not a list, but the position expects a list.`,
+		},
+		{
+			Input:    `query Q($cells: [Int!]) { grid(cells: $cells) }`,
+			Expected: `input:1: Variable "$cells" of type "[Int!]" used in position expecting type "[[Int!]]": it is a list, but the position expects a list of lists.`,
+		},
+		{
+			Input:    `query Q($cells: [[Int]]) { grid(cells: $cells) }`,
+			Expected: `input:1: Variable "$cells" of type "[[Int]]" used in position expecting type "[[Int!]]": the items of its items may be null, but the position requires them not to be.`,
+		},
+		{
+			Input:    `query Q($ids: [String!]!) { users(ids: $ids) { name } }`,
+			Expected: `input:1: Variable "$ids" of type "[String!]!" used in position expecting type "[ID!]!": the position expects "ID", not "String".`,
+		},
+		{
+			Input:    `query Q($name: String) { page(filter: { name: $name }) { name } }`,
+			Expected: `input:1: Variable "$name" of type "String" used in position expecting type "String!": it may be null, but the position requires a value. Declare it as "String!", or give it a default value.`,
+		},
+	}
+	for _, test := range tests {
+		typer := &Typer{Schema: schema}
+		_, _, err := typer.VisitString("", test.Input)
+		if test.Expected == "" {
+			assert.NoError(t, err, "input: %s", test.Input)
+		} else {
+			assert.EqualError(t, err, test.Expected, "input: %s", test.Input)
+		}
+	}
+}
+
+func TestVariableUsagesInSharedFragments(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user: User
+			}
+
+			type User {
+				friends(first: Int!): [User!]!
+			}
+		`,
+	})
+	typer := &Typer{Schema: schema}
+	fragment := `fragment Friends on User { friends(first: $first) { __typename } }`
+	query := "query Q($first: Int) {\n  user { ...Friends }\n}"
+	typer.PrepareString("fragment.ts", fragment)
+	typer.PrepareString("query.ts", query)
+	_, _, err := typer.VisitString("query.ts", query)
+	assert.EqualError(t, err, `query.ts:2: Variable "$first" of type "Int" used in fragment "Friends" in position expecting type "Int!": it may be null, but the position requires a value. Declare it as "Int!", or give it a default value.`)
+}
+
+func TestSharedFragmentVariables(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name: "schema.gql",
+		Input: `
+			type Query {
+				user(id: ID!): User
+			}
+
+			type User {
+				id: ID!
+				friends(first: Int): [User!]!
+			}
+		`,
+	})
+	typer := &Typer{Schema: schema}
+	fragment := `fragment Friends on User { friends(first: $n) { id } }`
+	query := `query Q($n: Int) { user(id: "1") { ...Friends } }`
+	typer.PrepareString("fragment.ts", fragment)
+	typer.PrepareString("query.ts", query)
+	_, _, err := typer.VisitString("fragment.ts", fragment)
+	assert.NoError(t, err)
+	_, _, err = typer.VisitString("query.ts", query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`export type Fragment_Friends_Data = { __typename: "User"; friends: ({ __typename: "User"; id: string; })[]; };`,
+		`export type Fragment_Friends_Variables = { };`,
+		`export type Query_Q_Data = { __typename: "Query"; user: (({ __typename: "User"; } & Fragment_Friends_Data) | null); };`,
+		`export type Query_Q_Variables = { n: (number | null); };`,
+	}, renderTypes(typer.GeneratedTypes).Declarations)
+}
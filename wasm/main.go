@@ -0,0 +1,116 @@
+//go:build js && wasm
+
+// Command wasm exposes extractgqlts to JavaScript as a WebAssembly module.
+// Once run, it defines a global extractgqlts.generate function that accepts
+// a generateRequest and returns a generateResponse, both as JSON strings.
+// See the npm directory for a wrapper.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/deref/extractgqlts/emit"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/deref/extractgqlts/typer"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type generateRequest struct {
+	Schema  string            `json:"schema"`
+	Sources []generate.Source `json:"sources"`
+	// Defaults to "typescript".
+	Target      string            `json:"target"`
+	Nullability string            `json:"nullability"`
+	Typename    string            `json:"typename"`
+	Scalars     map[string]string `json:"scalars"`
+	ShareShapes bool              `json:"shareShapes"`
+}
+
+type generateResponse struct {
+	Output      string   `json:"output"`
+	Diagnostics []string `json:"diagnostics"`
+	Error       string   `json:"error,omitempty"`
+}
+
+func main() {
+	js.Global().Set("extractgqlts", map[string]interface{}{
+		"generate": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			var res generateResponse
+			var req generateRequest
+			if len(args) != 1 {
+				res.Error = "expected one argument"
+			} else if err := json.Unmarshal([]byte(args[0].String()), &req); err != nil {
+				res.Error = fmt.Sprintf("decoding request: %v", err)
+			} else {
+				res = generateJS(req)
+			}
+			bs, _ := json.Marshal(res)
+			return string(bs)
+		}),
+	})
+	// Keep the Go runtime alive to serve calls.
+	select {}
+}
+
+func generateJS(req generateRequest) (res generateResponse) {
+	res.Diagnostics = []string{}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{
+		Name:  "schema.gql",
+		Input: req.Schema,
+	})
+	if gqlErr != nil {
+		res.Error = fmt.Sprintf("loading schema: %v", gqlErr)
+		return
+	}
+	g := &generate.Generator{
+		Schema: schema,
+		Options: typer.Options{
+			Scalars:     req.Scalars,
+			ShareShapes: req.ShareShapes,
+		},
+		// The js/wasm runtime is single-threaded.
+		Jobs: 1,
+	}
+	switch req.Nullability {
+	case "", "null":
+	case "null-or-undefined":
+		g.Options.Nullability = typer.NullabilityNullOrUndefined
+	default:
+		res.Error = fmt.Sprintf("invalid nullability: %q", req.Nullability)
+		return
+	}
+	switch req.Typename {
+	case "", "always":
+	case "selected":
+		g.Options.Typename = typer.TypenameSelected
+	default:
+		res.Error = fmt.Sprintf("invalid typename: %q", req.Typename)
+		return
+	}
+	target := req.Target
+	if target == "" {
+		target = "typescript"
+	}
+	var err error
+	if g.Emitter, err = emit.NewTarget(target); err != nil {
+		res.Error = err.Error()
+		return
+	}
+
+	var out bytes.Buffer
+	result, err := g.GenerateSources(context.Background(), &out, req.Sources)
+	if err != nil {
+		res.Error = err.Error()
+		return
+	}
+	for _, d := range result.Diagnostics {
+		res.Diagnostics = append(res.Diagnostics, d.String())
+	}
+	res.Output = out.String()
+	return
+}
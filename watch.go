@@ -0,0 +1,163 @@
+//go:build !wasip1
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/deref/extractgqlts/generate"
+	"github.com/fsnotify/fsnotify"
+)
+
+// How long --watch waits after a change for more changes before
+// regenerating, so that saving many files at once, as a formatter or a
+// branch switch does, regenerates once.
+const watchDebounce = 100 * time.Millisecond
+
+// Generates outputs, then again whenever the schema or an input file, one
+// matching inputPatterns or in a directory input, is written, created,
+// removed, or renamed, until ctx is done. Directories are watched rather than
+// files, since editors often save by replacing files. The generator is
+// reused, so that only changed inputs are retyped.
+func watchInputs(ctx context.Context, g *generate.Generator, pkgs []outputPackage, inputPatterns []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watching: %w", err)
+	}
+	defer watcher.Close()
+
+	schema := filepath.Clean(schemaPath)
+	if err := watcher.Add(filepath.Dir(schema)); err != nil {
+		return fmt.Errorf("watching %s: %w", schemaPath, err)
+	}
+	var patterns, dirs []string
+	for _, pattern := range inputPatterns {
+		pattern = filepath.ToSlash(filepath.Clean(strings.ReplaceAll(pattern, `\`, "/")))
+		root := patternRoot(pattern)
+		if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+			dirs = append(dirs, pattern)
+			root = pattern
+		} else {
+			patterns = append(patterns, pattern)
+		}
+		if err := watchTree(watcher, root); err != nil {
+			return err
+		}
+	}
+	extensions := g.Extensions
+	if len(extensions) == 0 {
+		extensions = generate.DefaultExtensions
+	}
+	relevant := func(path string) bool {
+		if filepath.Clean(path) == schema {
+			return true
+		}
+		path = filepath.ToSlash(filepath.Clean(path))
+		for _, pattern := range patterns {
+			if ok, _ := doublestar.Match(pattern, path); ok {
+				return true
+			}
+		}
+		for _, dir := range dirs {
+			if dir != "." && !strings.HasPrefix(path, dir+"/") {
+				continue
+			}
+			for _, ext := range extensions {
+				if strings.HasSuffix(path, ext) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	regenerate := func() {
+		start := time.Now()
+		ok, err := generateOutputs(ctx, g, pkgs, inputPatterns)
+		switch {
+		case ctx.Err() != nil:
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		case ok:
+			fmt.Fprintf(os.Stderr, "generated in %v\n", time.Since(start).Round(time.Millisecond))
+		}
+	}
+	regenerate()
+	fmt.Fprintln(os.Stderr, "watching for changes")
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-watcher.Events:
+			if event.Op&fsnotify.Create != 0 {
+				// Watch new directories, and whatever they already hold.
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, event.Name); err != nil {
+						fmt.Fprintf(os.Stderr, "%v\n", err)
+					}
+					continue
+				}
+			}
+			if event.Op == fsnotify.Chmod || !relevant(event.Name) {
+				continue
+			}
+			timer.Reset(watchDebounce)
+		case err := <-watcher.Errors:
+			fmt.Fprintf(os.Stderr, "watching: %v\n", err)
+		case <-timer.C:
+			regenerate()
+		}
+	}
+}
+
+// Returns the directory of pattern above its first metacharacter, which
+// contains every match.
+func patternRoot(pattern string) string {
+	parts := strings.Split(pattern, "/")
+	for i, part := range parts {
+		if strings.ContainsAny(part, "*?[{") {
+			root := strings.Join(parts[:i], "/")
+			if root == "" && strings.HasPrefix(pattern, "/") {
+				return "/"
+			}
+			if root == "" {
+				return "."
+			}
+			return root
+		}
+	}
+	return filepath.Dir(pattern)
+}
+
+// Watches dir and the directories within it, but for node_modules and hidden
+// ones.
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != dir && (name == "node_modules" || strings.HasPrefix(name, ".")) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("watching %s: %w", path, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,15 @@
+//go:build wasip1
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deref/extractgqlts/generate"
+)
+
+// WASI has no file change notifications.
+func watchInputs(ctx context.Context, g *generate.Generator, pkgs []outputPackage, inputPatterns []string) error {
+	return fmt.Errorf("--watch is not supported on WASI")
+}